@@ -14,10 +14,10 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
-	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
@@ -29,12 +29,33 @@ func main() {
 	cfg := config.Load()
 	flag.Parse()
 
+	if cfg.ValidateOnly {
+		if err := validateConfig(context.Background(), cfg, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize structured logger aligned with KServe conventions
-	appLogger := logger.New(cfg.DebugMode)
+	appLogger, err := logger.NewWithOptions(logger.Options{
+		Debug:  cfg.DebugMode,
+		Format: cfg.LogFormat,
+		Level:  cfg.LogLevel,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid logger configuration:", err)
+		os.Exit(1)
+	}
 	defer func() {
 		_ = appLogger.Sync() // Ignore sync errors on close, as per zap documentation
 	}()
 
+	if err := cfg.Validate(); err != nil {
+		appLogger.Fatal("Invalid configuration",
+			"error", err,
+		)
+	}
+
 	gin.SetMode(gin.ReleaseMode) // Explicitly set release mode
 	if cfg.DebugMode {
 		gin.SetMode(gin.DebugMode)
@@ -54,6 +75,22 @@ func main() {
 		}))
 	}
 
+	router.Use(handlers.Compress(cfg.CompressionMinSize))
+
+	trustedProxies, err := handlers.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		appLogger.Fatal("Invalid trusted proxies configuration",
+			"error", err,
+		)
+	}
+	// Gin trusts every proxy by default, align its client IP, e.g. in access logs, with the resolved one.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		appLogger.Fatal("Invalid trusted proxies configuration",
+			"error", err,
+		)
+	}
+	router.Use(handlers.ResolveClientIP(trustedProxies))
+
 	router.OPTIONS("/*path", func(c *gin.Context) { c.Status(204) })
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -74,15 +111,7 @@ func main() {
 
 	registerHandlers(ctx, appLogger, router, cfg, store)
 
-	srv := &http.Server{
-		Addr:              ":" + cfg.Port,
-		Handler:           router,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       15 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		IdleTimeout:       60 * time.Second,
-		MaxHeaderBytes:    1 << 20,
-	}
+	srv := newHTTPServer(cfg, router)
 
 	go func() {
 		appLogger.Info("Server starting",
@@ -114,7 +143,20 @@ func main() {
 	appLogger.Info("Server exited gracefully")
 }
 
-// initStore creates the store based on the configured storage mode.
+// newHTTPServer creates the HTTP server serving the given handler with the configured timeouts.
+func newHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    1 << 20,
+	}
+}
+
+// initStore creates the store based on the configured storage mode, tolerating the configured clock skew in expiration checks.
 //
 // Storage modes:
 //   - in-memory (default): Ephemeral storage, data lost on restart
@@ -123,11 +165,16 @@ func main() {
 //
 //nolint:ireturn // Returns MetadataStore interface by design for pluggable storage backends.
 func initStore(ctx context.Context, log *logger.Logger, cfg *config.Config) (api_keys.MetadataStore, error) {
+	storeOptions := api_keys.StoreOptions{
+		ExpirationGrace: cfg.ExpirationGrace,
+		IDPrefix:        cfg.TokenIDPrefix,
+	}
+
 	switch cfg.StorageMode {
 	case config.StorageModeInMemory, "":
 		log.Info("Using in-memory storage (data will be lost on restart). " +
 			"For persistent storage, use --storage=disk or --storage=external")
-		return api_keys.NewSQLiteStore(ctx, log, ":memory:")
+		return api_keys.NewSQLiteStore(ctx, log, ":memory:", storeOptions)
 
 	case config.StorageModeDisk:
 		dataPath := strings.TrimSpace(cfg.DataPath)
@@ -135,7 +182,7 @@ func initStore(ctx context.Context, log *logger.Logger, cfg *config.Config) (api
 			dataPath = config.DefaultDataPath
 		}
 		log.Info("Using persistent disk storage", "path", dataPath)
-		return api_keys.NewSQLiteStore(ctx, log, dataPath)
+		return api_keys.NewSQLiteStore(ctx, log, dataPath, storeOptions)
 
 	case config.StorageModeExternal:
 		dbURL := strings.TrimSpace(cfg.DBConnectionURL)
@@ -143,17 +190,61 @@ func initStore(ctx context.Context, log *logger.Logger, cfg *config.Config) (api
 			return nil, errors.New("--db-connection-url is required when using --storage=external")
 		}
 		log.Info("Connecting to external database...")
-		return api_keys.NewExternalStore(ctx, log, dbURL)
+		return api_keys.NewExternalStore(ctx, log, dbURL, storeOptions)
 
 	default:
 		return nil, fmt.Errorf("unknown storage mode: %q (valid modes: in-memory, disk, external)", cfg.StorageMode)
 	}
 }
 
+// tokenAudiences returns the audiences of the Service Account tokens: the default {instance}-sa audience,
+// followed by the audiences configured for the gateways.
+func tokenAudiences(instanceName string, gatewayRefs []models.GatewayRef) []string {
+	audiences := []string{instanceName + "-sa"}
+	for _, ref := range gatewayRefs {
+		if ref.Audience != "" {
+			audiences = append(audiences, ref.Audience)
+		}
+	}
+	return audiences
+}
+
+// revocationInterval is how often Service Account recreations deferred by the revocation grace period are checked.
+// They are performed at most that long after their grace period elapsed.
+const revocationInterval = time.Minute
+
+// runDueRevocations performs the Service Account recreations deferred by the revocation grace period, including the
+// ones recorded before a restart, until the context is canceled.
+func runDueRevocations(ctx context.Context, log *logger.Logger, service *api_keys.Service) {
+	ticker := time.NewTicker(revocationInterval)
+	defer ticker.Stop()
+
+	for {
+		revoked, err := service.RevokeDue(ctx, time.Now())
+		if err != nil {
+			log.Error("Failed to recreate service accounts after the revocation grace period",
+				"error", err,
+			)
+		}
+		if revoked > 0 {
+			log.Info("Recreated service accounts after the revocation grace period",
+				"count", revoked,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engine, cfg *config.Config, store api_keys.MetadataStore) {
 	router.GET("/health", handlers.NewHealthHandler().HealthCheck)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	cluster, err := config.NewClusterConfig(cfg.Namespace, constant.DefaultResyncPeriod)
+	cluster, err := config.NewClusterConfig(cfg.Namespace, cfg.ResyncPeriod)
 	if err != nil {
 		log.Fatal("Failed to create cluster config",
 			"error", err,
@@ -165,16 +256,59 @@ func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engin
 	}
 
 	v1Routes := router.Group("/v1")
+	requireJSON := handlers.RequireJSONContentType(cfg.RequireJSONContentType)
+
+	tierMapper := tier.NewMapper(log, cluster.ConfigMapLister, cfg.Name, cfg.Namespace, tier.MapperOptions{
+		ConfigMapClient: cluster.ClientSet.CoreV1(),
+	})
+	tierHandler := tier.NewHandler(tierMapper)
+	v1Routes.GET("/tiers", tierHandler.ListTiers)
+	v1Routes.POST("/tiers/lookup", requireJSON, tierHandler.TierLookup)
+
+	gatewayRefs := []models.GatewayRef{{Name: cfg.GatewayName, Namespace: cfg.GatewayNamespace}}
+	if len(cfg.Gateways) > 0 {
+		var errRefs error
+		if gatewayRefs, errRefs = models.ParseGatewayRefs(cfg.Gateways, cfg.GatewayNamespace); errRefs != nil {
+			log.Fatal("Invalid gateways configuration",
+				"error", errRefs,
+			)
+		}
+	}
+
+	checkGateways := func() error {
+		return models.CheckGatewaysExist(cluster.GatewayLister, gatewayRefs)
+	}
+	if err := checkGateways(); err != nil {
+		log.Warn("No models are listed until the configured gateways exist, check --gateways or --gateway-namespace and --gateway-name",
+			"error", err,
+		)
+	}
+	router.GET("/ready", handlers.NewReadinessHandler(cluster.HasSynced, checkGateways).ReadinessCheck)
 
-	tierMapper := tier.NewMapper(log, cluster.ConfigMapLister, cfg.Name, cfg.Namespace)
-	v1Routes.POST("/tiers/lookup", tier.NewHandler(tierMapper).TierLookup)
+	managedRouteLabels, errRouteLabels := models.ParseManagedRouteLabels(cfg.ManagedRouteLabels)
+	if errRouteLabels != nil {
+		log.Fatal("Invalid managed route labels configuration",
+			"error", errRouteLabels,
+		)
+	}
+
+	idStrategy, errIDStrategy := models.ParseIDStrategy(cfg.ModelIDStrategy)
+	if errIDStrategy != nil {
+		log.Fatal("Invalid model ID strategy",
+			"error", errIDStrategy,
+		)
+	}
 
 	modelMgr, errMgr := models.NewManager(
 		log,
 		cluster.InferenceServiceLister,
 		cluster.LLMInferenceServiceLister,
 		cluster.HTTPRouteLister,
-		models.GatewayRef{Name: cfg.GatewayName, Namespace: cfg.GatewayNamespace},
+		models.ManagerOptions{
+			ManagedRouteLabels: managedRouteLabels,
+			IDStrategy:         idStrategy,
+		},
+		gatewayRefs...,
 	)
 
 	if errMgr != nil {
@@ -183,7 +317,26 @@ func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engin
 		)
 	}
 
-	modelsHandler := handlers.NewModelsHandler(log, modelMgr)
+	modelsHandler := handlers.NewModelsHandler(log, modelMgr, handlers.ModelsHandlerOptions{
+		AdminGroups:            cfg.AdminGroups,
+		ModelAccessGroups:      cfg.ModelAccessGroups,
+		ListNotReady:           cfg.ListNotReadyModels,
+		AuthzDebug:             cfg.AuthzDebugHeader,
+		ExcludeEndpointPending: cfg.ExcludeModelsWithoutURL,
+	})
+
+	namespaceLabelTemplates, errLabels := token.ParseLabelTemplates(cfg.TierNamespaceLabels)
+	if errLabels != nil {
+		log.Fatal("Invalid tier namespace labels configuration",
+			"error", errLabels,
+		)
+	}
+
+	if err := token.ValidateSystemUsers(cfg.SystemUsers); err != nil {
+		log.Fatal("Invalid system users configuration",
+			"error", err,
+		)
+	}
 
 	tokenManager := token.NewManager(
 		log,
@@ -192,22 +345,86 @@ func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engin
 		cluster.ClientSet,
 		cluster.NamespaceLister,
 		cluster.ServiceAccountLister,
+		token.ManagerOptions{
+			LabelTemplates:          namespaceLabelTemplates,
+			Unmanaged:               !cfg.ManageNamespaces,
+			FallbackNamespace:       cfg.TierNamespaceFallback,
+			MaxNamespaces:           cfg.MaxTierNamespaces,
+			SystemUsers:             cfg.SystemUsers,
+			SystemNamespace:         cfg.SystemUserNamespace,
+			Audiences:               tokenAudiences(cfg.Name, gatewayRefs),
+			ExpirationJitterPercent: cfg.TokenExpirationJitterPercent,
+		},
 	)
-	tokenHandler := token.NewHandler(log, cfg.Name, tokenManager)
-
-	apiKeyService := api_keys.NewService(tokenManager, store)
+	tokenHandler := token.NewHandler(log, cfg.Name, tokenManager, token.HandlerOptions{
+		MaxGroups: cfg.MaxGroups,
+	})
+
+	apiKeyService := api_keys.NewService(tokenManager, store, api_keys.ServiceOptions{
+		EnforceUniqueNames:       cfg.EnforceUniqueKeyNames,
+		RevocationGracePeriod:    cfg.RevocationGracePeriod,
+		RevokeConfirmationTTL:    cfg.RevokeConfirmationTTL,
+		RevokeConfirmationSecret: []byte(cfg.RevokeConfirmationSecret),
+		DefaultPageSize:          cfg.DefaultPageSize,
+		MaxPageSize:              cfg.MaxPageSize,
+		MaxActiveKeysPerUser:     cfg.MaxActiveKeysPerUser,
+	})
 	apiKeyHandler := api_keys.NewHandler(log, apiKeyService)
 
+	// The informer caches synced above, so that the Service Accounts to recreate are found.
+	go runDueRevocations(ctx, log, apiKeyService)
+
+	// Clients may set a tighter deadline than the server timeouts on the endpoints calling the cluster.
+	requestTimeout := handlers.RequestTimeout(cfg.MaxRequestTimeout)
+
 	// Model listing endpoint (v1Routes is grouped under /v1, so this creates /v1/models)
-	v1Routes.GET("/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+	v1Routes.GET("/models", requestTimeout, tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+	v1Routes.GET("/admin/models/summary", tokenHandler.ExtractUserInfo(),
+		handlers.RequireAnyGroup(cfg.AdminGroups), modelsHandler.SummarizeModels)
+
+	if cfg.PublicCatalog {
+		// No user info is extracted on purpose: the catalog is browsable anonymously.
+		v1Routes.GET("/catalog", modelsHandler.ListCatalog)
+	}
+
+	limitBody := handlers.LimitRequestBody(cfg.MaxRequestBodySize)
 
-	tokenRoutes := v1Routes.Group("/tokens", tokenHandler.ExtractUserInfo())
-	tokenRoutes.POST("", tokenHandler.IssueToken)
+	tokenRoutes := v1Routes.Group("/tokens", limitBody, requireJSON, tokenHandler.ExtractUserInfo())
+	// Revocations are not bounded: cut short, they would leave the tokens of the user partially revoked.
+	tokenRoutes.POST("", requestTimeout, tokenHandler.IssueToken)
 	tokenRoutes.DELETE("", apiKeyHandler.RevokeAllTokens)
 
-	apiKeyRoutes := v1Routes.Group("/api-keys", tokenHandler.ExtractUserInfo())
+	// Tokens issued on behalf of other users, e.g. for service accounts set up by administrators.
+	v1Routes.POST("/admin/tokens", requestTimeout, limitBody, requireJSON, tokenHandler.ExtractUserInfo(),
+		handlers.RequireAnyGroup(cfg.ImpersonationGroups), tokenHandler.IssueTokenOnBehalf)
+	v1Routes.GET("/admin/tokens", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.SearchTokens)
+	v1Routes.POST("/admin/tokens/import", limitBody, requireJSON, tokenHandler.ExtractUserInfo(),
+		handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.ImportTokens)
+	v1Routes.GET("/admin/sa-name", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), tokenHandler.PreviewServiceAccount)
+	v1Routes.GET("/admin/users/:username/identity", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.GetUserIdentity)
+
+	tierAdminRoutes := v1Routes.Group("/admin/tiers", limitBody, requireJSON, tokenHandler.ExtractUserInfo(),
+		handlers.RequireAnyGroup(cfg.AdminGroups))
+	tierAdminRoutes.GET("", tierHandler.ListTierConfig)
+	tierAdminRoutes.POST("", tierHandler.CreateTier)
+	tierAdminRoutes.PUT("/:name", tierHandler.UpdateTier)
+	tierAdminRoutes.DELETE("/:name", tierHandler.DeleteTier)
+
+	apiKeyRoutes := v1Routes.Group("/api-keys", limitBody, requireJSON, tokenHandler.ExtractUserInfo())
 	apiKeyRoutes.POST("", apiKeyHandler.CreateAPIKey)
 	apiKeyRoutes.GET("", apiKeyHandler.ListAPIKeys)
 	apiKeyRoutes.GET("/:id", apiKeyHandler.GetAPIKey)
+	apiKeyRoutes.POST("/:id/rotate", apiKeyHandler.RotateAPIKey)
+	apiKeyRoutes.PATCH("/:id", apiKeyHandler.ExtendAPIKey)
+	// Custom methods of the collection, e.g. /v1/api-keys:stream.
+	v1Routes.GET("/api-keys:method", tokenHandler.ExtractUserInfo(), apiKeyHandler.APIKeysMethod)
+
+	v1Routes.POST("/introspect", limitBody, requireJSON, tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.Introspect)
+	v1Routes.POST("/admin/reconcile-sa", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.ReconcileServiceAccounts)
+	v1Routes.POST("/admin/revoke", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups), apiKeyHandler.RevokeIssuedBefore)
+	v1Routes.GET("/admin/config", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups),
+		handlers.NewConfigHandler(cfg).GetConfig)
+	router.GET("/debug/informers", tokenHandler.ExtractUserInfo(), handlers.RequireAnyGroup(cfg.AdminGroups),
+		handlers.NewInformersHandler(cluster.InformerStatuses).ListInformers)
 	// Note: Single key deletion removed for initial release - use DELETE /v1/tokens to revoke all tokens
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func TestNewHTTPServer_AppliesConfiguredTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		Port:              "9090",
+		ReadHeaderTimeout: 2 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      10 * time.Minute,
+		IdleTimeout:       5 * time.Minute,
+	}
+	require.NoError(t, cfg.Validate())
+
+	srv := newHTTPServer(cfg, http.NotFoundHandler())
+
+	assert.Equal(t, ":9090", srv.Addr)
+	assert.Equal(t, 2*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, time.Minute, srv.ReadTimeout)
+	assert.Equal(t, 10*time.Minute, srv.WriteTimeout)
+	assert.Equal(t, 5*time.Minute, srv.IdleTimeout)
+}
+
+func TestConfigValidate_RejectsNonPositiveTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       0,
+		WriteTimeout:      -time.Second,
+		IdleTimeout:       config.DefaultIdleTimeout,
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-timeout")
+	assert.Contains(t, err.Error(), "write-timeout")
+	assert.NotContains(t, err.Error(), "idle-timeout")
+}
+
+func TestTokenAudiences(t *testing.T) {
+	refs := []models.GatewayRef{
+		{Name: "maas-internal", Namespace: "gateway-ns"},
+		{Name: "maas-external", Namespace: "edge-ns", Audience: "maas-external-sa"},
+	}
+
+	assert.Equal(t, []string{"maas-sa", "maas-external-sa"}, tokenAudiences("maas", refs))
+	assert.Equal(t, []string{"maas-sa"}, tokenAudiences("maas", nil))
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// validateConfig runs the checks performed at startup without starting the server, so that configuration
+// changes can be gated in CI. Every problem found is printed to out, and reported in the returned error.
+//
+// The tier mapping is read from cfg.TierConfigFile when set, or from the cluster otherwise.
+func validateConfig(ctx context.Context, cfg *config.Config, out io.Writer) error {
+	var errs []error
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if cfg.StorageMode == config.StorageModeExternal && strings.TrimSpace(cfg.DBConnectionURL) == "" {
+		errs = append(errs, errors.New("--db-connection-url is required when using --storage=external"))
+	}
+
+	if err := (logger.Options{Debug: cfg.DebugMode, Format: cfg.LogFormat, Level: cfg.LogLevel}).Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(cfg.Gateways) > 0 {
+		if _, err := models.ParseGatewayRefs(cfg.Gateways, cfg.GatewayNamespace); err != nil {
+			errs = append(errs, fmt.Errorf("gateways: %w", err))
+		}
+	}
+
+	if _, err := models.ParseManagedRouteLabels(cfg.ManagedRouteLabels); err != nil {
+		errs = append(errs, fmt.Errorf("managed-route-labels: %w", err))
+	}
+
+	if _, err := models.ParseIDStrategy(cfg.ModelIDStrategy); err != nil {
+		errs = append(errs, fmt.Errorf("model-id-strategy: %w", err))
+	}
+
+	if _, err := handlers.ParseTrustedProxies(cfg.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("trusted-proxies: %w", err))
+	}
+
+	if _, err := token.ParseLabelTemplates(cfg.TierNamespaceLabels); err != nil {
+		errs = append(errs, fmt.Errorf("tier-namespace-labels: %w", err))
+	}
+
+	if err := token.ValidateSystemUsers(cfg.SystemUsers); err != nil {
+		errs = append(errs, fmt.Errorf("system-users: %w", err))
+	}
+
+	tierData, err := loadTierConfigData(ctx, cfg)
+	if err == nil {
+		_, err = tier.ParseConfig(tierData)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s ConfigMap: %w", constant.TierMappingConfigMap, err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(out, "- "+problem)
+		}
+		return err
+	}
+
+	fmt.Fprintln(out, "Configuration is valid")
+	return nil
+}
+
+// loadTierConfigData returns the data of the tier mapping ConfigMap, read from the manifest in cfg.TierConfigFile
+// or from the cluster.
+func loadTierConfigData(ctx context.Context, cfg *config.Config) (map[string]string, error) {
+	if cfg.TierConfigFile != "" {
+		manifest, err := os.ReadFile(cfg.TierConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", cfg.TierConfigFile, err)
+		}
+
+		var cm corev1.ConfigMap
+		if err := yaml.Unmarshal(manifest, &cm); err != nil {
+			return nil, fmt.Errorf("failed to parse %s as a ConfigMap manifest: %w", cfg.TierConfigFile, err)
+		}
+		return cm.Data, nil
+	}
+
+	restConfig, err := config.LoadRestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(cfg.Namespace).Get(ctx, constant.TierMappingConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap from namespace %s: %w", cfg.Namespace, err)
+	}
+	return cm.Data, nil
+}
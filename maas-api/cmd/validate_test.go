@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+)
+
+func validTestConfig(tierConfigFile string) *config.Config {
+	return &config.Config{
+		Namespace:         "maas-api",
+		StorageMode:       config.StorageModeInMemory,
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       config.DefaultReadTimeout,
+		WriteTimeout:      config.DefaultWriteTimeout,
+		IdleTimeout:       config.DefaultIdleTimeout,
+		TierConfigFile:    tierConfigFile,
+	}
+}
+
+func TestValidateConfig_Valid(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, validateConfig(t.Context(), validTestConfig("testdata/valid-tiers.yaml"), &out))
+	assert.Equal(t, "Configuration is valid\n", out.String())
+}
+
+func TestValidateConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *config.Config)
+		errContains []string
+	}{
+		{
+			name:        "invalid tier mapping",
+			modify:      func(cfg *config.Config) { cfg.TierConfigFile = "testdata/invalid-tiers.yaml" },
+			errContains: []string{`duplicate tier name "free"`},
+		},
+		{
+			name:        "missing tier mapping file",
+			modify:      func(cfg *config.Config) { cfg.TierConfigFile = "testdata/does-not-exist.yaml" },
+			errContains: []string{"failed to read testdata/does-not-exist.yaml"},
+		},
+		{
+			name: "every problem is reported",
+			modify: func(cfg *config.Config) {
+				cfg.TierConfigFile = "testdata/invalid-tiers.yaml"
+				cfg.ReadTimeout = -time.Second
+				cfg.StorageMode = config.StorageModeExternal
+				cfg.TierNamespaceLabels = config.StringList{"not-a-label"}
+				cfg.ManagedRouteLabels = config.StringList{"app.kubernetes.io/name={name}/router"}
+				cfg.ModelIDStrategy = "uuid"
+			},
+			errContains: []string{
+				"read-timeout must be a positive duration",
+				"--db-connection-url is required",
+				"tier-namespace-labels",
+				"managed-route-labels",
+				"model-id-strategy",
+				"duplicate tier name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig("testdata/valid-tiers.yaml")
+			tt.modify(cfg)
+
+			var out bytes.Buffer
+			err := validateConfig(t.Context(), cfg, &out)
+			require.Error(t, err)
+			for _, expected := range tt.errContains {
+				assert.ErrorContains(t, err, expected)
+				assert.Contains(t, out.String(), expected, "problems must be printed")
+			}
+			assert.NotContains(t, out.String(), "Configuration is valid")
+		})
+	}
+}
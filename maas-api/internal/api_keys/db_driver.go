@@ -3,12 +3,15 @@ package api_keys
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
-	_ "github.com/mattn/go-sqlite3"    // SQLite driver
+	"github.com/mattn/go-sqlite3"      // SQLite driver, registered on import
 	"k8s.io/utils/env"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
@@ -27,7 +30,7 @@ const (
 )
 
 // Currently supports PostgreSQL only.
-func NewExternalStore(ctx context.Context, log *logger.Logger, databaseURL string) (*SQLStore, error) {
+func NewExternalStore(ctx context.Context, log *logger.Logger, databaseURL string, options StoreOptions) (*SQLStore, error) {
 	databaseURL = strings.TrimSpace(databaseURL)
 
 	if !strings.HasPrefix(databaseURL, "postgresql://") && !strings.HasPrefix(databaseURL, "postgres://") {
@@ -48,10 +51,16 @@ func NewExternalStore(ctx context.Context, log *logger.Logger, databaseURL strin
 		return nil, fmt.Errorf("failed to connect to PostgreSQL database: %w", err)
 	}
 
-	s := &SQLStore{db: db, dbType: DBTypePostgres, logger: log}
-	if err := s.initSchema(ctx); err != nil {
+	s := &SQLStore{
+		db:              db,
+		dbType:          DBTypePostgres,
+		logger:          log,
+		expirationGrace: options.ExpirationGrace,
+		idPrefix:        options.IDPrefix,
+	}
+	if err := s.migrate(ctx); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	log.Info("Connected to external PostgreSQL database")
@@ -67,6 +76,57 @@ func placeholder(dbType DBType, index int) string {
 	return fmt.Sprintf("$%d", index)
 }
 
+// pgUniqueViolation is the PostgreSQL error code of unique and primary key constraint violations.
+const pgUniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a primary key or unique constraint violation, for either database type.
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+const (
+	defaultSQLiteJournalMode   = "WAL"
+	defaultSQLiteBusyTimeoutMS = 5000
+	defaultSQLiteForeignKeys   = true
+)
+
+// sqliteJournalModes are the journal modes accepted by SQLITE_JOURNAL_MODE.
+var sqliteJournalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+
+// sqliteDSN returns the DSN of a SQLite database file. The journal mode, busy timeout and foreign keys
+// enforcement default to WAL, 5s and on, and can be overridden with SQLITE_JOURNAL_MODE, SQLITE_BUSY_TIMEOUT_MS
+// and SQLITE_FOREIGN_KEYS, e.g. on network filesystems where WAL is not supported.
+func sqliteDSN(dbPath string) (string, error) {
+	journalMode := strings.ToUpper(strings.TrimSpace(env.GetString("SQLITE_JOURNAL_MODE", defaultSQLiteJournalMode)))
+	if !slices.Contains(sqliteJournalModes, journalMode) {
+		return "", fmt.Errorf("invalid SQLITE_JOURNAL_MODE %q: must be one of %s",
+			journalMode, strings.Join(sqliteJournalModes, ", "))
+	}
+
+	busyTimeoutMS, err := env.GetInt("SQLITE_BUSY_TIMEOUT_MS", defaultSQLiteBusyTimeoutMS)
+	if err != nil || busyTimeoutMS < 0 {
+		return "", errors.New("invalid SQLITE_BUSY_TIMEOUT_MS: must be a non-negative number of milliseconds")
+	}
+
+	foreignKeys, err := env.GetBool("SQLITE_FOREIGN_KEYS", defaultSQLiteForeignKeys)
+	if err != nil {
+		return "", fmt.Errorf("invalid SQLITE_FOREIGN_KEYS: %w", err)
+	}
+	foreignKeysPragma := "off"
+	if foreignKeys {
+		foreignKeysPragma = "on"
+	}
+
+	return fmt.Sprintf("%s?_journal_mode=%s&_foreign_keys=%s&_busy_timeout=%d",
+		dbPath, journalMode, foreignKeysPragma, busyTimeoutMS), nil
+}
+
 const (
 	defaultMaxOpenConns        = 25
 	defaultMaxIdleConns        = 5
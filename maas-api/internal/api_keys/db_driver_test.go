@@ -0,0 +1,47 @@
+package api_keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		dsn, err := sqliteDSN("/data/maas-api.db")
+		require.NoError(t, err)
+		assert.Equal(t, "/data/maas-api.db?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000", dsn)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("SQLITE_JOURNAL_MODE", "delete")
+		t.Setenv("SQLITE_BUSY_TIMEOUT_MS", "15000")
+		t.Setenv("SQLITE_FOREIGN_KEYS", "false")
+
+		dsn, err := sqliteDSN("/data/maas-api.db")
+		require.NoError(t, err)
+		assert.Equal(t, "/data/maas-api.db?_journal_mode=DELETE&_foreign_keys=off&_busy_timeout=15000", dsn)
+	})
+
+	t.Run("InvalidJournalMode", func(t *testing.T) {
+		t.Setenv("SQLITE_JOURNAL_MODE", "fast")
+
+		_, err := sqliteDSN("/data/maas-api.db")
+		require.ErrorContains(t, err, "invalid SQLITE_JOURNAL_MODE")
+	})
+
+	t.Run("InvalidBusyTimeout", func(t *testing.T) {
+		t.Setenv("SQLITE_BUSY_TIMEOUT_MS", "-1")
+
+		_, err := sqliteDSN("/data/maas-api.db")
+		require.ErrorContains(t, err, "invalid SQLITE_BUSY_TIMEOUT_MS")
+	})
+
+	t.Run("InvalidForeignKeys", func(t *testing.T) {
+		t.Setenv("SQLITE_FOREIGN_KEYS", "sometimes")
+
+		_, err := sqliteDSN("/data/maas-api.db")
+		require.ErrorContains(t, err, "invalid SQLITE_FOREIGN_KEYS")
+	})
+}
@@ -0,0 +1,175 @@
+package api_keys
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+
+	// methodStream is the custom method of GET /v1/api-keys:stream.
+	methodStream = ":stream"
+)
+
+var csvHeader = []string{"id", "name", "description", "creationDate", "expirationDate", "status"}
+
+// listFormat returns the requested format of the API key listing. The format query parameter takes
+// precedence over the Accept header, and JSON is used when neither asks for CSV.
+func listFormat(c *gin.Context) string {
+	if format := strings.ToLower(strings.TrimSpace(c.Query("format"))); format != "" {
+		return format
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV {
+		return formatCSV
+	}
+
+	return formatJSON
+}
+
+// exportAPIKeysCSV streams the API key metadata of the user as CSV. Keys are read from the store in batches, each
+// written and flushed to the client once the store released it, so that a slow client never holds a database
+// connection.
+func (h *Handler) exportAPIKeysCSV(c *gin.Context, user *token.UserContext) {
+	writer := csv.NewWriter(c.Writer)
+	rows := 0
+
+	writeHeader := func() error {
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="api-keys.csv"`)
+		c.Status(http.StatusOK)
+		return writer.Write(csvHeader)
+	}
+
+	err := h.service.StreamAPIKeys(c.Request.Context(), user, nil, func(keys []ApiKeyMetadata) error {
+		if rows == 0 {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+		}
+		rows += len(keys)
+
+		for _, key := range keys {
+			if err := writer.Write([]string{
+				key.ID,
+				csvSafe(key.Name),
+				csvSafe(key.Description),
+				key.CreationDate,
+				key.ExpirationDate,
+				key.Status,
+			}); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		c.Writer.Flush()
+		return writer.Error()
+	})
+
+	if err != nil {
+		h.logger.Error("Failed to export API keys",
+			"error", err,
+		)
+		if rows == 0 {
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to list api keys")
+		}
+		// The response is already being streamed, the client sees a truncated export.
+		return
+	}
+
+	if rows == 0 {
+		if err := writeHeader(); err != nil {
+			h.logger.Error("Failed to export API keys",
+				"error", err,
+			)
+			return
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.logger.Error("Failed to export API keys",
+			"error", err,
+		)
+	}
+}
+
+// APIKeysMethod handles the custom methods of the API key collection, GET /v1/api-keys:<method>.
+func (h *Handler) APIKeysMethod(c *gin.Context) {
+	if c.Param("method") != methodStream {
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, "Not found")
+		return
+	}
+
+	h.StreamAPIKeys(c)
+}
+
+// StreamAPIKeys handles GET /v1/api-keys:stream, streaming the API key metadata of the user as NDJSON, newest first.
+// Keys are read from the store in batches, each flushed to the client before the next one is read. Every line
+// carries the cursor to pass in the cursor query parameter to resume the stream after it, e.g. once the connection
+// broke.
+func (h *Handler) StreamAPIKeys(c *gin.Context) {
+	userCtx, exists := c.Get("user")
+	if !exists {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
+		return
+	}
+
+	user, ok := userCtx.(*token.UserContext)
+	if !ok {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+		return
+	}
+
+	var after *KeysetCursor
+	if value := c.Query("cursor"); value != "" {
+		var err error
+		if after, err = decodeKeysetCursor(value); err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	c.Header("Content-Type", mimeNDJSON)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+
+	err := h.service.StreamAPIKeys(c.Request.Context(), user, after, func(keys []ApiKeyMetadata) error {
+		for _, key := range keys {
+			if err := encoder.Encode(StreamedAPIKey{ApiKeyMetadata: key, Cursor: encodeKeysetCursor(key)}); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// The response is already being streamed, the client sees a truncated stream and can resume it.
+		h.logger.Error("Failed to stream API keys",
+			"error", err,
+		)
+	}
+}
+
+// csvSafe prevents user-provided values from being interpreted as formulas by spreadsheet applications.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -2,11 +2,16 @@ package api_keys
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
 
@@ -29,26 +34,43 @@ type CreateRequest struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description,omitempty"`
 	Expiration  *token.Duration `json:"expiration"`
+	// Models optionally restricts the models the key is intended for.
+	Models []string `json:"models,omitempty"`
+	// Metadata optionally attaches key/value pairs to the key, e.g. env=prod.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ExtendRequest is the body of PATCH /v1/api-keys/:id.
+type ExtendRequest struct {
+	// Expiration is the new lifetime of the key, counted from now.
+	Expiration *token.Duration `json:"expiration"`
 }
 
 type Response struct {
-	Token       string `json:"token"`
-	Expiration  string `json:"expiration"`
-	ExpiresAt   int64  `json:"expiresAt"`
-	JTI         string `json:"jti"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	Token       string   `json:"token"`
+	Expiration  string   `json:"expiration"`
+	ExpiresAt   int64    `json:"expiresAt"`
+	ExpiresIn   int64    `json:"expiresIn"`
+	JTI         string   `json:"jti"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	RotatedFrom string   `json:"rotatedFrom,omitempty"`
+	Models      []string `json:"models,omitempty"`
+	// Metadata holds the key/value pairs attached to the key.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Source is the client the key was created from, see APIKey.Source.
+	Source string `json:"source,omitempty"`
 }
 
 func (h *Handler) CreateAPIKey(c *gin.Context) {
 	var req CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
 		return
 	}
 
-	if req.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "token name is required for api keys"})
+	if errs := req.Validate(); errs != nil {
+		apierror.WriteWithDetails(c, http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "Validation failed", errs)
 		return
 	}
 
@@ -58,28 +80,43 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 
 	userCtx, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User context not found"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
 		return
 	}
 
 	user, ok := userCtx.(*token.UserContext)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context type"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
 		return
 	}
 
-	expiration := req.Expiration.Duration
-	if err := token.ValidateExpiration(expiration, 10*time.Minute); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	tok, err := h.service.CreateAPIKey(c.Request.Context(), user, req.Name, req.Description, req.Models, req.Metadata, req.Expiration.Duration)
+	if errors.Is(err, ErrDuplicateName) {
+		apierror.Write(c, http.StatusConflict, apierror.CodeConflict, "An active API key named "+strconv.Quote(req.Name)+" already exists")
+		return
+	}
+	if errors.Is(err, ErrDuplicateToken) {
+		apierror.Write(c, http.StatusConflict, apierror.CodeConflict, "An API key with the same token ID already exists")
+		return
+	}
+	var limitErr *KeyLimitError
+	if errors.As(err, &limitErr) {
+		apierror.Write(c, http.StatusConflict, apierror.CodeConflict,
+			fmt.Sprintf("You already have %d active API keys, the maximum. Revoke one or let it expire before creating another", limitErr.Max))
+		return
+	}
+	if writeExpirationLimit(c, err) {
+		return
+	}
+	if errors.Is(err, token.ErrTierNamespaceForbidden) {
+		apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, token.TierNamespaceForbiddenMessage)
 		return
 	}
-
-	tok, err := h.service.CreateAPIKey(c.Request.Context(), user, req.Name, req.Description, expiration)
 	if err != nil {
 		h.logger.Error("Failed to generate API key",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate api key"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to generate api key")
 		return
 	}
 
@@ -87,82 +124,519 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 		Token:       tok.Token.Token,
 		Expiration:  tok.Expiration.String(),
 		ExpiresAt:   tok.ExpiresAt,
+		ExpiresIn:   token.ExpiresIn(tok.ExpiresAt, time.Now()),
 		JTI:         tok.JTI,
 		Name:        tok.Name,
 		Description: tok.Description,
+		Models:      tok.Models,
+		Metadata:    tok.Metadata,
+		Source:      tok.Source,
 	})
 }
 
 func (h *Handler) ListAPIKeys(c *gin.Context) {
 	userCtx, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User context not found"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
 		return
 	}
 
 	user, ok := userCtx.(*token.UserContext)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context type"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+		return
+	}
+
+	switch format := listFormat(c); format {
+	case formatJSON:
+	case formatCSV:
+		h.exportAPIKeysCSV(c, user)
+		return
+	default:
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Unsupported format "+strconv.Quote(format)+", expected json or csv")
+		return
+	}
+
+	defaultLimit, maxLimit := h.service.pageSizes()
+	offset, limit, err := listPage(c, defaultLimit, maxLimit)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	filter, err := listFilter(c)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
 		return
 	}
 
-	tokens, err := h.service.ListAPIKeys(c.Request.Context(), user)
+	page, err := h.service.ListAPIKeys(c.Request.Context(), user, filter, offset, limit)
 	if err != nil {
 		h.logger.Error("Failed to list API keys",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list api keys"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to list api keys")
 		return
 	}
 
-	c.JSON(http.StatusOK, tokens)
+	c.JSON(http.StatusOK, page)
 }
 
 func (h *Handler) GetAPIKey(c *gin.Context) {
 	tokenID := c.Param("id")
 	if tokenID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token ID required"})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Token ID required")
 		return
 	}
 
 	tok, err := h.service.GetAPIKey(c.Request.Context(), tokenID)
 	if err != nil {
 		if errors.Is(err, ErrTokenNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, "API key not found")
 			return
 		}
 		h.logger.Error("Failed to get API key",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve API key"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve API key")
 		return
 	}
 
 	c.JSON(http.StatusOK, tok)
 }
 
-// RevokeAllTokens handles DELETE /v1/tokens.
+// RotateAPIKey handles POST /v1/api-keys/:id/rotate.
+// The new token value is returned only once, in this response.
+func (h *Handler) RotateAPIKey(c *gin.Context) {
+	tokenID := c.Param("id")
+	if tokenID == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Token ID required")
+		return
+	}
+
+	userCtx, exists := c.Get("user")
+	if !exists {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
+		return
+	}
+
+	user, ok := userCtx.(*token.UserContext)
+	if !ok {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+		return
+	}
+
+	tok, err := h.service.RotateAPIKey(c.Request.Context(), user, tokenID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTokenNotFound):
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, "API key not found")
+		case errors.Is(err, ErrTokenNotActive):
+			apierror.Write(c, http.StatusConflict, apierror.CodeConflict, "API key is not active")
+		case errors.Is(err, ErrDuplicateToken):
+			apierror.Write(c, http.StatusConflict, apierror.CodeConflict, "An API key with the same token ID already exists")
+		case errors.Is(err, token.ErrTierNamespaceForbidden):
+			apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, token.TierNamespaceForbiddenMessage)
+		default:
+			h.logger.Error("Failed to rotate API key",
+				"error", err,
+			)
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to rotate api key")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Token:       tok.Token.Token,
+		Expiration:  tok.Expiration.String(),
+		ExpiresAt:   tok.ExpiresAt,
+		ExpiresIn:   token.ExpiresIn(tok.ExpiresAt, time.Now()),
+		JTI:         tok.JTI,
+		Name:        tok.Name,
+		Description: tok.Description,
+		RotatedFrom: tok.RotatedFrom,
+		Models:      tok.Models,
+		Metadata:    tok.Metadata,
+		Source:      tok.Source,
+	})
+}
+
+// ExtendAPIKey handles PATCH /v1/api-keys/:id.
+// A new token is minted for the key, its value is returned only once, in this response.
+func (h *Handler) ExtendAPIKey(c *gin.Context) {
+	tokenID := c.Param("id")
+	if tokenID == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Token ID required")
+		return
+	}
+
+	var req ExtendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		apierror.WriteWithDetails(c, http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "Validation failed", errs)
+		return
+	}
+
+	userCtx, exists := c.Get("user")
+	if !exists {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
+		return
+	}
+
+	user, ok := userCtx.(*token.UserContext)
+	if !ok {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+		return
+	}
+
+	tok, err := h.service.ExtendAPIKey(c.Request.Context(), user, tokenID, req.Expiration.Duration)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTokenNotFound):
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, "API key not found")
+		case errors.Is(err, ErrTokenNotActive):
+			apierror.Write(c, http.StatusConflict, apierror.CodeConflict, "API key is not active")
+		case writeExpirationLimit(c, err):
+		case errors.Is(err, token.ErrTierNamespaceForbidden):
+			apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, token.TierNamespaceForbiddenMessage)
+		default:
+			h.logger.Error("Failed to extend API key",
+				"error", err,
+			)
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to extend api key")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Token:       tok.Token.Token,
+		Expiration:  tok.Expiration.String(),
+		ExpiresAt:   tok.ExpiresAt,
+		ExpiresIn:   token.ExpiresIn(tok.ExpiresAt, time.Now()),
+		JTI:         tok.JTI,
+		Name:        tok.Name,
+		Description: tok.Description,
+		RotatedFrom: tok.RotatedFrom,
+		Models:      tok.Models,
+		Metadata:    tok.Metadata,
+		Source:      tok.Source,
+	})
+}
+
+// writeExpirationLimit reports a lifetime exceeding the maximum of the tier of the user as a validation error
+// of the expiration field. It returns false, writing nothing, for any other error.
+func writeExpirationLimit(c *gin.Context, err error) bool {
+	var limitErr *token.ExpirationLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	apierror.WriteWithDetails(c, http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "Validation failed",
+		ValidationErrors{"expiration": limitErr.Error()})
+	return true
+}
+
+// RevokeAllTokens handles DELETE /v1/tokens, revoking the tokens in the scope query parameter, all by default.
+// When confirmation is required, a request without the confirm query parameter revokes nothing and gets
+// a RevokeConfirmation to send back. Tokens revoked without their metadata being
+// marked as expired are reported with 207 Multi-Status and the RevokeResult.
 func (h *Handler) RevokeAllTokens(c *gin.Context) {
 	userCtx, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User context not found"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
 		return
 	}
 
 	user, ok := userCtx.(*token.UserContext)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context type"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
 		return
 	}
 
-	if err := h.service.RevokeAll(c.Request.Context(), user); err != nil {
+	scope, err := ParseRevokeScope(c.Query("scope"))
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	var result RevokeResult
+	if h.service.RevokeConfirmationRequired() {
+		confirm := c.Query("confirm")
+		if confirm == "" {
+			h.requestRevokeConfirmation(c, user, scope)
+			return
+		}
+		result, err = h.service.RevokeConfirmed(c.Request.Context(), user, scope, confirm)
+	} else {
+		result, err = h.service.Revoke(c.Request.Context(), user, scope)
+	}
+
+	if errors.Is(err, ErrInvalidConfirmation) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+	// The tokens are revoked, only their metadata is stale: clients must not retry as if nothing happened.
+	if err != nil && result.Partial() {
+		h.logger.Error("Revoked tokens but failed to mark their metadata as expired",
+			"error", err,
+		)
+		c.JSON(http.StatusMultiStatus, result)
+		return
+	}
+	if err != nil {
 		h.logger.Error("Failed to revoke tokens",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke tokens"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to revoke tokens")
 		return
 	}
 
-	h.logger.Debug("Successfully revoked tokens")
+	h.logger.Debug("Successfully revoked tokens",
+		"scope", scope,
+	)
 	c.Status(http.StatusNoContent)
 }
+
+// requestRevokeConfirmation writes the confirmation token the user must send back to revoke their tokens in the scope.
+func (h *Handler) requestRevokeConfirmation(c *gin.Context, user *token.UserContext, scope RevokeScope) {
+	confirmation, err := h.service.RequestRevokeConfirmation(c.Request.Context(), user, scope)
+	if err != nil {
+		h.logger.Error("Failed to request revocation confirmation",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to revoke tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, confirmation)
+}
+
+type IntrospectRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Introspect reports whether the token in the request body is an active API key (RFC 7662-style).
+func (h *Handler) Introspect(c *gin.Context) {
+	var req IntrospectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	introspection, err := h.service.Introspect(c.Request.Context(), req.Token)
+	if err != nil {
+		h.logger.Error("Failed to introspect token",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to introspect token")
+		return
+	}
+
+	c.JSON(http.StatusOK, introspection)
+}
+
+// SearchTokens handles GET /v1/admin/tokens, listing the tokens of all users whose ID starts with
+// the jti_prefix query parameter, e.g. to find a token from the partial JTI logged by a gateway.
+func (h *Handler) SearchTokens(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("jti_prefix"))
+	if prefix == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "jti_prefix is required")
+		return
+	}
+
+	defaultLimit, maxLimit := h.service.pageSizes()
+	offset, limit, err := listPage(c, defaultLimit, maxLimit)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.SearchTokens(c.Request.Context(), prefix, offset, limit)
+	if err != nil {
+		h.logger.Error("Failed to search tokens by JTI prefix",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to search tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetUserIdentity handles GET /v1/admin/users/:username/identity, returning the tier, namespace and Service Account
+// the tokens of the user are issued for, and the number of their active API keys. As for GET /v1/admin/sa-name, the
+// optional, repeatable groups query parameter resolves the tier the user would get, otherwise the tier of their
+// existing Service Account is returned.
+func (h *Handler) GetUserIdentity(c *gin.Context) {
+	username := strings.TrimSpace(c.Param("username"))
+	if username == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "username must not be empty")
+		return
+	}
+
+	identity, err := h.service.UserIdentity(c.Request.Context(), username, c.QueryArray("groups"))
+	if errors.Is(err, token.ErrInvalidUsername) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+	var groupNotFoundErr *tier.GroupNotFoundError
+	if errors.As(err, &groupNotFoundErr) {
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, groupNotFoundErr.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to resolve user identity",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to resolve user identity")
+		return
+	}
+
+	c.JSON(http.StatusOK, identity)
+}
+
+// ImportedToken is the metadata of a token issued outside of maas-api, see Handler.ImportTokens.
+type ImportedToken struct {
+	JTI      string `json:"jti"`
+	Username string `json:"username"`
+	// Namespace is the namespace of the Service Account the token was issued for. It is recorded, so that the Service
+	// Account is not pruned while the token is active.
+	Namespace      string `json:"namespace,omitempty"`
+	Name           string `json:"name"`
+	CreationDate   string `json:"creationDate"`
+	ExpirationDate string `json:"expirationDate"`
+	// Token is the token itself, when known. Only tokens imported with it are accepted by introspection.
+	Token string `json:"token,omitempty"`
+}
+
+// ImportRequest is the body of POST /v1/admin/tokens/import.
+type ImportRequest struct {
+	Tokens []ImportedToken `json:"tokens"`
+}
+
+// ImportResponse is the result of POST /v1/admin/tokens/import.
+type ImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// ImportTokens handles POST /v1/admin/tokens/import, adding the metadata of tokens issued outside of maas-api to
+// the store without issuing any token, e.g. to rebuild it after its loss. Either all tokens are imported, or none is.
+// Access must be restricted to administrators.
+func (h *Handler) ImportTokens(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	if errs := req.Validate(time.Now()); errs != nil {
+		apierror.WriteWithDetails(c, http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "Validation failed", errs)
+		return
+	}
+
+	err := h.service.ImportTokens(c.Request.Context(), req.Tokens)
+	if errors.Is(err, ErrDuplicateToken) {
+		apierror.Write(c, http.StatusConflict, apierror.CodeConflict, err.Error()+", no token was imported")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to import tokens",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to import tokens")
+		return
+	}
+
+	fields := []any{"count", len(req.Tokens)}
+	if user, ok := c.Get("user"); ok {
+		if userCtx, ok := user.(*token.UserContext); ok {
+			fields = append(fields, "admin", userCtx.Username)
+		}
+	}
+	h.logger.Info("Imported tokens", fields...)
+
+	c.JSON(http.StatusCreated, ImportResponse{Imported: len(req.Tokens)})
+}
+
+// RevokeIssuedBeforeResponse is the result of POST /v1/admin/revoke.
+type RevokeIssuedBeforeResponse struct {
+	IssuedBefore time.Time `json:"issuedBefore"`
+	// Revoked is the number of active API keys marked as expired.
+	Revoked int64 `json:"revoked"`
+}
+
+// RevokeIssuedBefore handles POST /v1/admin/revoke, marking the active API keys of all users created before
+// the RFC 3339 timestamp of the issued_before query parameter as expired.
+func (h *Handler) RevokeIssuedBefore(c *gin.Context) {
+	value := c.Query("issued_before")
+	if value == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "issued_before is required")
+		return
+	}
+
+	cutoff, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid issued_before "+strconv.Quote(value)+", expected an RFC 3339 timestamp")
+		return
+	}
+
+	revoked, err := h.service.RevokeIssuedBefore(c.Request.Context(), cutoff)
+	if err != nil {
+		h.logger.Error("Failed to revoke tokens issued before cutoff",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to revoke tokens")
+		return
+	}
+
+	fields := []any{"issued_before", cutoff.UTC().Format(time.RFC3339), "count", revoked}
+	if user, ok := c.Get("user"); ok {
+		if userCtx, ok := user.(*token.UserContext); ok {
+			fields = append(fields, "admin", userCtx.Username)
+		}
+	}
+	h.logger.Info("Revoked tokens issued before cutoff", fields...)
+
+	c.JSON(http.StatusOK, RevokeIssuedBeforeResponse{
+		IssuedBefore: cutoff.UTC(),
+		Revoked:      revoked,
+	})
+}
+
+// ReconcileResponse is the result of POST /v1/admin/reconcile-sa.
+type ReconcileResponse struct {
+	DryRun bool `json:"dryRun"`
+	// ServiceAccounts are the orphaned Service Accounts, deleted unless DryRun is set.
+	ServiceAccounts []token.ServiceAccountRef `json:"serviceAccounts"`
+}
+
+// ReconcileServiceAccounts handles POST /v1/admin/reconcile-sa, pruning the Service Accounts of users without
+// any active API key. Nothing is deleted unless the dryRun query parameter is set to false.
+func (h *Handler) ReconcileServiceAccounts(c *gin.Context) {
+	dryRun := true
+	if value := c.Query("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid dryRun "+strconv.Quote(value)+", expected true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	orphans, err := h.service.ReconcileServiceAccounts(c.Request.Context(), dryRun)
+	if err != nil {
+		h.logger.Error("Failed to reconcile service accounts",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to reconcile service accounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, ReconcileResponse{
+		DryRun:          dryRun,
+		ServiceAccounts: orphans,
+	})
+}
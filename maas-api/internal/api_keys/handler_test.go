@@ -0,0 +1,2354 @@
+package api_keys_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
+)
+
+func performRequest(t *testing.T, router *gin.Engine, method, path, username string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), method, path, bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.HeaderUsername, username)
+	req.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const owner = "rotate-user@example.com"
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{
+		"name":        "compromised-key",
+		"description": "key used by the CI pipeline",
+		"expiration":  "24h",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	t.Run("OtherUserCannotRotate", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", "someone-else", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys/does-not-exist/rotate", owner, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	w = performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", owner, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var rotated api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.NotEmpty(t, rotated.Token)
+	assert.NotEqual(t, created.JTI, rotated.JTI)
+	assert.Equal(t, created.Name, rotated.Name)
+	assert.Equal(t, created.Description, rotated.Description)
+	assert.Equal(t, created.JTI, rotated.RotatedFrom)
+
+	// The old token is only marked as expired in the store, the cluster accepts it until its own expiration.
+	t.Run("OldKeyIsReportedExpired", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys/"+created.JTI, owner, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var meta api_keys.ApiKeyMetadata
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+		assert.Equal(t, api_keys.TokenStatusExpired, meta.Status)
+	})
+
+	t.Run("NewKeyIsActive", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys/"+rotated.JTI, owner, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var meta api_keys.ApiKeyMetadata
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+		assert.Equal(t, api_keys.TokenStatusActive, meta.Status)
+		assert.Equal(t, created.Name, meta.Name)
+		assert.Equal(t, created.JTI, meta.RotatedFrom)
+	})
+
+	t.Run("OldKeyCannotBeRotatedAgain", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", owner, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestExtendAPIKey(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const owner = "extend-user@example.com"
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{
+		"name":        "long-lived-key",
+		"description": "key used by the nightly job",
+		"models":      []string{"granite"},
+		"expiration":  "24h",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	path := "/v1/api-keys/" + created.JTI
+
+	t.Run("OtherUserCannotExtend", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPatch, path, "someone-else", map[string]any{"expiration": "720h"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPatch, "/v1/api-keys/does-not-exist", owner, map[string]any{"expiration": "720h"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("InvalidExpiration", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			body   map[string]any
+			reason string
+		}{
+			{name: "missing", body: map[string]any{}, reason: "is required"},
+			{name: "too short", body: map[string]any{"expiration": "1m"}, reason: "at least 10 minutes"},
+			{name: "beyond global maximum", body: map[string]any{"expiration": "9000h"}, reason: "must not exceed " + api_keys.MaxExpiration.String()},
+			{name: "beyond tier maximum", body: map[string]any{"expiration": "2880h"}, reason: "must not exceed 2160h0m0s for tier free"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := performRequest(t, router, http.MethodPatch, path, owner, tt.body)
+				require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+				var response struct {
+					Error struct {
+						Code    string            `json:"code"`
+						Details map[string]string `json:"details"`
+					} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, apierror.CodeValidationFailed, response.Error.Code)
+				assert.Contains(t, response.Error.Details["expiration"], tt.reason)
+			})
+		}
+	})
+
+	w = performRequest(t, router, http.MethodPatch, path, owner, map[string]any{"expiration": "2160h"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var extended api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &extended))
+	assert.Equal(t, created.JTI, extended.JTI, "the key must keep its ID")
+	assert.Equal(t, created.Name, extended.Name)
+	assert.Equal(t, created.Description, extended.Description)
+	assert.Equal(t, created.Models, extended.Models)
+	assert.NotEqual(t, created.Token, extended.Token, "a new token must be minted")
+	assert.Equal(t, "2160h0m0s", extended.Expiration)
+	assert.Greater(t, extended.ExpiresAt, created.ExpiresAt)
+
+	t.Run("MetadataIsExtended", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, path, owner, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var meta api_keys.ApiKeyMetadata
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+		assert.Equal(t, api_keys.TokenStatusActive, meta.Status)
+
+		expiresAt, err := time.Parse(time.RFC3339, meta.ExpirationDate)
+		require.NoError(t, err)
+		assert.Equal(t, extended.ExpiresAt, expiresAt.Unix())
+	})
+
+	t.Run("ListStillHasOneKey", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys", owner, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var page api_keys.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, created.JTI, page.Data[0].ID)
+	})
+
+	t.Run("RotatedKeyCannotBeExtended", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPost, path+"/rotate", owner, nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = performRequest(t, router, http.MethodPatch, path, owner, map[string]any{"expiration": "720h"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestRotateAPIKey_AfterExtend(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+
+	store, err := api_keys.NewSQLiteStore(t.Context(), logger.Development(), ":memory:", api_keys.StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+	service := api_keys.NewService(manager, store, api_keys.ServiceOptions{})
+
+	user := &token.UserContext{Username: "extend-then-rotate-user", Groups: []string{"system:authenticated"}}
+
+	// A 90-day key created 60 days ago.
+	now := time.Now()
+	require.NoError(t, store.Add(t.Context(), user.Username, &api_keys.APIKey{
+		Token: token.Token{
+			JTI:       "jti-extended",
+			IssuedAt:  now.Add(-60 * 24 * time.Hour).Unix(),
+			ExpiresAt: now.Add(30 * 24 * time.Hour).Unix(),
+		},
+		Name: "extended-key",
+	}))
+
+	_, err = service.ExtendAPIKey(t.Context(), user, "jti-extended", 24*time.Hour)
+	require.NoError(t, err)
+
+	rotated, err := service.RotateAPIKey(t.Context(), user, "jti-extended")
+	require.NoError(t, err)
+
+	// The lifetime of the token issued by the extension, not the one between the creation of the key and its
+	// extended expiration.
+	assert.InDelta(t, now.Add(24*time.Hour).Unix(), rotated.ExpiresAt, 60)
+}
+
+func TestCreateAPIKey_ExpiresIn(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "expires-in-user", map[string]any{
+		"name":       "refresh-timer-key",
+		"expiration": "48h",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	requested := int64((48 * time.Hour).Seconds())
+	assert.InDelta(t, requested, created.ExpiresIn, 5, "expiresIn should match the requested TTL")
+	assert.InDelta(t, created.ExpiresAt-time.Now().Unix(), created.ExpiresIn, 5, "expiresIn should be consistent with expiresAt")
+}
+
+func TestCreateAPIKey_Source(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const owner = "source-user"
+	create := func(name, source string) api_keys.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/api-keys",
+			strings.NewReader(`{"name":"`+name+`","expiration":"24h"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(constant.HeaderUsername, owner)
+		req.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+		if source != "" {
+			req.Header.Set(constant.HeaderSource, source)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created
+	}
+
+	fromCLI := create("cli-key", " CLI ")
+	assert.Equal(t, "cli", fromCLI.Source)
+	unknown := create("unknown-key", "")
+	assert.Empty(t, unknown.Source)
+	invalid := create("invalid-key", "<script>")
+	assert.Empty(t, invalid.Source, "invalid sources should not be recorded")
+
+	w := performRequest(t, router, http.MethodGet, "/v1/api-keys", owner, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list api_keys.ListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 3)
+
+	sources := make(map[string]string, len(list.Data))
+	for _, key := range list.Data {
+		sources[key.Name] = key.Source
+	}
+	assert.Equal(t, map[string]string{"cli-key": "cli", "unknown-key": "", "invalid-key": ""}, sources)
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys/"+fromCLI.JTI, owner, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var key api_keys.ApiKeyMetadata
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+	assert.Equal(t, "cli", key.Source)
+}
+
+func TestOversizedRequestBody(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	oversized := map[string]any{
+		"name":        "oversized-key",
+		"description": strings.Repeat("x", config.DefaultMaxRequestBodySize),
+	}
+
+	for _, path := range []string{"/v1/api-keys", "/v1/tokens"} {
+		t.Run(path, func(t *testing.T) {
+			w := performRequest(t, router, http.MethodPost, path, "oversized-user", oversized)
+			require.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+			var response apierror.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, apierror.CodeRequestTooLarge, response.Error.Code)
+		})
+	}
+
+	t.Run("NothingIssued", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys", "oversized-user", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var page api_keys.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Empty(t, page.Data)
+	})
+}
+
+func TestCreateAPIKey_ValidationErrors(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	tests := []struct {
+		name           string
+		body           map[string]any
+		expectedErrors map[string]string
+	}{
+		{
+			name:           "missing name",
+			body:           map[string]any{"expiration": "1h"},
+			expectedErrors: map[string]string{"name": "is required"},
+		},
+		{
+			name:           "name too long",
+			body:           map[string]any{"name": strings.Repeat("k", api_keys.MaxNameLength+1)},
+			expectedErrors: map[string]string{"name": "must not exceed 128 characters"},
+		},
+		{
+			name: "name with disallowed characters",
+			body: map[string]any{"name": "my/key;drop"},
+			expectedErrors: map[string]string{
+				"name": "must start with a letter or a digit and contain only letters, digits, spaces, '.', '_' or '-'",
+			},
+		},
+		{
+			name: "description too long",
+			body: map[string]any{
+				"name":        "valid-key",
+				"description": strings.Repeat("d", api_keys.MaxDescriptionLength+1),
+			},
+			expectedErrors: map[string]string{"description": "must not exceed 1024 characters"},
+		},
+		{
+			name:           "expiration too short",
+			body:           map[string]any{"name": "valid-key", "expiration": "1m"},
+			expectedErrors: map[string]string{"expiration": "token expiration must be at least 10 minutes"},
+		},
+		{
+			name:           "expiration too long",
+			body:           map[string]any{"name": "valid-key", "expiration": "9000h"},
+			expectedErrors: map[string]string{"expiration": "must not exceed 8760h0m0s"},
+		},
+		{
+			name:           "negative expiration",
+			body:           map[string]any{"name": "valid-key", "expiration": "-1h"},
+			expectedErrors: map[string]string{"expiration": "expiration must be positive"},
+		},
+		{
+			name:           "empty model",
+			body:           map[string]any{"name": "valid-key", "models": []string{"gpt-3-turbo", " "}},
+			expectedErrors: map[string]string{"models": "must not contain empty model IDs"},
+		},
+		{
+			name:           "duplicate model",
+			body:           map[string]any{"name": "valid-key", "models": []string{"gpt-3-turbo", "gpt-3-turbo"}},
+			expectedErrors: map[string]string{"models": `must not list model "gpt-3-turbo" more than once`},
+		},
+		{
+			name: "too many metadata entries",
+			body: map[string]any{"name": "valid-key", "metadata": func() map[string]string {
+				metadata := make(map[string]string, api_keys.MaxMetadataEntries+1)
+				for i := range api_keys.MaxMetadataEntries + 1 {
+					metadata[fmt.Sprintf("key-%d", i)] = "value"
+				}
+				return metadata
+			}()},
+			expectedErrors: map[string]string{"metadata": "must not contain more than 16 entries"},
+		},
+		{
+			name:           "empty metadata key",
+			body:           map[string]any{"name": "valid-key", "metadata": map[string]string{" ": "value"}},
+			expectedErrors: map[string]string{"metadata": "must not contain empty keys"},
+		},
+		{
+			name: "metadata key too long",
+			body: map[string]any{"name": "valid-key", "metadata": map[string]string{
+				strings.Repeat("k", api_keys.MaxMetadataKeyLength+1): "value",
+			}},
+			expectedErrors: map[string]string{
+				"metadata": fmt.Sprintf("key %q must not exceed 63 characters", strings.Repeat("k", api_keys.MaxMetadataKeyLength+1)),
+			},
+		},
+		{
+			name: "metadata value too long",
+			body: map[string]any{"name": "valid-key", "metadata": map[string]string{
+				"env": strings.Repeat("v", api_keys.MaxMetadataValueLength+1),
+			}},
+			expectedErrors: map[string]string{"metadata": `value of "env" must not exceed 256 characters`},
+		},
+		{
+			name: "multiple invalid fields",
+			body: map[string]any{"description": strings.Repeat("d", api_keys.MaxDescriptionLength+1), "expiration": "1m"},
+			expectedErrors: map[string]string{
+				"name":        "is required",
+				"description": "must not exceed 1024 characters",
+				"expiration":  "token expiration must be at least 10 minutes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "validation-user", tt.body)
+			require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+			var response struct {
+				Error struct {
+					Code    string            `json:"code"`
+					Details map[string]string `json:"details"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, apierror.CodeValidationFailed, response.Error.Code)
+			assert.Equal(t, tt.expectedErrors, response.Error.Details)
+		})
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "validation-user", map[string]any{
+			"name":       "CI pipeline_key-1.0",
+			"expiration": "720h",
+		})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+}
+
+func TestIntrospect(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const owner = "introspected-user@example.com"
+
+	introspect := func(t *testing.T, groups string, tok string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		payload, err := json.Marshal(map[string]string{"token": tok})
+		require.NoError(t, err)
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/introspect", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(constant.HeaderUsername, "gateway-service")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	createKey := func(t *testing.T, name string) api_keys.Response {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{
+			"name":       name,
+			"expiration": "24h",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created
+	}
+
+	introspectorGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+
+	assertInactive := func(t *testing.T, w *httptest.ResponseRecorder) {
+		t.Helper()
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"active": false}`, w.Body.String(), "inactive tokens must not disclose any detail")
+	}
+
+	t.Run("ActiveToken", func(t *testing.T) {
+		created := createKey(t, "active-key")
+
+		w := introspect(t, introspectorGroups, created.Token)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.Introspection
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Active)
+		assert.Equal(t, owner, response.Username)
+		assert.Equal(t, "free", response.Tier)
+		assert.InDelta(t, created.ExpiresAt, response.ExpiresAt, 1)
+	})
+
+	t.Run("ExpiredToken", func(t *testing.T) {
+		created := createKey(t, "rotated-away-key")
+
+		// Rotation marks the original key as expired.
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", owner, nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		assertInactive(t, introspect(t, introspectorGroups, created.Token))
+	})
+
+	t.Run("ExtendedToken", func(t *testing.T) {
+		created := createKey(t, "extended-key")
+
+		w := performRequest(t, router, http.MethodPatch, "/v1/api-keys/"+created.JTI, owner, map[string]any{"expiration": "48h"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var extended api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &extended))
+
+		w = introspect(t, introspectorGroups, extended.Token)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.Introspection
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Active)
+		assert.InDelta(t, extended.ExpiresAt, response.ExpiresAt, 1)
+
+		// The metadata only records the latest token of the key.
+		assertInactive(t, introspect(t, introspectorGroups, created.Token))
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		unknown, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "unknown-jti"}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		assertInactive(t, introspect(t, introspectorGroups, unknown))
+	})
+
+	t.Run("ForgedTokenWithKnownJTI", func(t *testing.T) {
+		created := createKey(t, "forged-key")
+
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": created.JTI}).SignedString([]byte("forged"))
+		require.NoError(t, err)
+
+		assertInactive(t, introspect(t, introspectorGroups, forged))
+	})
+
+	t.Run("MalformedToken", func(t *testing.T) {
+		assertInactive(t, introspect(t, introspectorGroups, "not-a-jwt"))
+	})
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		created := createKey(t, "forbidden-key")
+
+		w := introspect(t, `["system:authenticated"]`, created.Token)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestListAPIKeys_CSV(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "csv-export-user"
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{
+		"name":        "access-review-key",
+		"description": "=SUM(A1:A2), used by CI",
+		"expiration":  "24h",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	readCSV := func(t *testing.T, w *httptest.ResponseRecorder) [][]string {
+		t.Helper()
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "api-keys.csv")
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	t.Run("format query parameter", func(t *testing.T) {
+		records := readCSV(t, performRequest(t, router, http.MethodGet, "/v1/api-keys?format=csv", username, nil))
+		require.Len(t, records, 2)
+
+		assert.Equal(t, []string{"id", "name", "description", "creationDate", "expirationDate", "status"}, records[0])
+
+		row := records[1]
+		assert.Equal(t, created.JTI, row[0])
+		assert.Equal(t, "access-review-key", row[1])
+		assert.Equal(t, "'=SUM(A1:A2), used by CI", row[2], "formulas must be neutralized")
+		assert.NotEmpty(t, row[3])
+		assert.NotEmpty(t, row[4])
+		assert.Equal(t, api_keys.TokenStatusActive, row[5])
+	})
+
+	t.Run("accept header", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/api-keys", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/csv")
+		req.Header.Set(constant.HeaderUsername, username)
+		req.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		records := readCSV(t, w)
+		require.Len(t, records, 2)
+		assert.Equal(t, created.JTI, records[1][0])
+	})
+
+	t.Run("no keys yields the header row only", func(t *testing.T) {
+		records := readCSV(t, performRequest(t, router, http.MethodGet, "/v1/api-keys?format=csv", "user-without-keys", nil))
+		require.Len(t, records, 1)
+		assert.Equal(t, "id", records[0][0])
+	})
+
+	t.Run("json remains the default", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys", username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var page api_keys.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, created.JTI, page.Data[0].ID)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys?format=xml", username, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestListAPIKeys_CSVSpansStorePages(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	// Small batches, so that the export is read from several store pages.
+	router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{MaxPageSize: 2})
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "csv-pages-user"
+
+	created := make(map[string]bool)
+	for i := range 5 {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": fmt.Sprintf("key-%d", i)})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var key api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+		created[key.JTI] = true
+	}
+
+	w := performRequest(t, router, http.MethodGet, "/v1/api-keys?format=csv", username, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(created)+1)
+	assert.Equal(t, "id", records[0][0])
+
+	seen := make(map[string]bool)
+	for _, row := range records[1:] {
+		assert.True(t, created[row[0]], "key %s does not belong to the user", row[0])
+		assert.False(t, seen[row[0]], "key %s exported twice", row[0])
+		seen[row[0]] = true
+	}
+}
+
+func TestCreateAPIKey_UniqueNames(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+
+	createTwice := func(t *testing.T, router *gin.Engine) (*httptest.ResponseRecorder, *httptest.ResponseRecorder) {
+		t.Helper()
+
+		body := map[string]any{"name": "prod", "expiration": "24h"}
+		first := performRequest(t, router, http.MethodPost, "/v1/api-keys", "unique-names-user", body)
+		require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+
+		return first, performRequest(t, router, http.MethodPost, "/v1/api-keys", "unique-names-user", body)
+	}
+
+	t.Run("duplicates are allowed by default", func(t *testing.T) {
+		router, cleanupRouter := fixtures.SetupTestRouter(manager)
+		defer func() {
+			if err := cleanupRouter(); err != nil {
+				t.Logf("Router cleanup error: %v", err)
+			}
+		}()
+
+		_, second := createTwice(t, router)
+		assert.Equal(t, http.StatusCreated, second.Code, second.Body.String())
+	})
+
+	t.Run("duplicates are rejected when enforced", func(t *testing.T) {
+		router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{EnforceUniqueNames: true})
+		defer func() {
+			if err := cleanupRouter(); err != nil {
+				t.Logf("Router cleanup error: %v", err)
+			}
+		}()
+
+		first, second := createTwice(t, router)
+		require.Equal(t, http.StatusConflict, second.Code, second.Body.String())
+		assert.Contains(t, second.Body.String(), `\"prod\"`)
+
+		// Another user may use the same name.
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "other-user", map[string]any{"name": "prod"})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		// Rotation is not blocked, and the new key keeps the name taken.
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(first.Body.Bytes(), &created))
+		w = performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", "unique-names-user", nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = performRequest(t, router, http.MethodPost, "/v1/api-keys", "unique-names-user", map[string]any{"name": "prod"})
+		assert.Equal(t, http.StatusConflict, w.Code, "the rotated key is still active under the same name")
+	})
+}
+
+func TestCreateAPIKey_MaxActiveKeysPerUser(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{MaxActiveKeysPerUser: 2})
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "capped-user"
+	create := func(t *testing.T, username string) *httptest.ResponseRecorder {
+		t.Helper()
+		return performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": "key"})
+	}
+
+	var first api_keys.Response
+	w := create(t, username)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+
+	w = create(t, username)
+	require.Equal(t, http.StatusCreated, w.Code, "reaching the cap is allowed: %s", w.Body.String())
+
+	w = create(t, username)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "2 active API keys")
+
+	// Other users have their own cap.
+	w = create(t, "other-user")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Rotation replaces a key, it is not blocked by the cap.
+	w = performRequest(t, router, http.MethodPost, "/v1/api-keys/"+first.JTI+"/rotate", username, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Revoked keys no longer count.
+	w = performRequest(t, router, http.MethodDelete, "/v1/tokens", username, nil)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = create(t, username)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestCreateAPIKey_ModelScope(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "scoped-key-user"
+	scope := []string{"gpt-3-turbo", "llama-7b"}
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{
+		"name":   "scoped-key",
+		"models": scope,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, scope, created.Models)
+
+	w = performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": "unscoped-key"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys", username, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var page api_keys.ListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Data, 2)
+	for _, key := range page.Data {
+		if key.ID == created.JTI {
+			assert.Equal(t, scope, key.Models)
+		} else {
+			assert.Empty(t, key.Models, "keys issued without a scope are not scoped")
+		}
+	}
+
+	w = performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", username, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var rotated api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.Equal(t, scope, rotated.Models, "rotation must keep the model scope")
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys/"+rotated.JTI, username, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var fetched api_keys.ApiKeyMetadata
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, scope, fetched.Models)
+}
+
+func TestCreateAPIKey_Metadata(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "tagged-key-user"
+	metadata := map[string]string{"env": "prod", "team": "search"}
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{
+		"name":     "tagged-key",
+		"metadata": metadata,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, metadata, created.Metadata)
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys", username, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var page api_keys.ListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, metadata, page.Data[0].Metadata)
+
+	w = performRequest(t, router, http.MethodPost, "/v1/api-keys/"+created.JTI+"/rotate", username, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var rotated api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	assert.Equal(t, metadata, rotated.Metadata, "rotation must keep the metadata")
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys/"+rotated.JTI, username, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var fetched api_keys.ApiKeyMetadata
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, metadata, fetched.Metadata)
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           any
+		expectedStatus int
+		expectedCode   string
+		expectedType   string
+	}{
+		{
+			name:           "unknown key",
+			method:         http.MethodGet,
+			path:           "/v1/api-keys/does-not-exist",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   apierror.CodeNotFound,
+			expectedType:   "not_found_error",
+		},
+		{
+			name:           "unsupported list format",
+			method:         http.MethodGet,
+			path:           "/v1/api-keys?format=xml",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   apierror.CodeInvalidRequest,
+			expectedType:   "invalid_request_error",
+		},
+		{
+			name:           "validation failure",
+			method:         http.MethodPost,
+			path:           "/v1/api-keys",
+			body:           map[string]any{"expiration": "24h"},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   apierror.CodeValidationFailed,
+			expectedType:   "invalid_request_error",
+		},
+		{
+			name:           "introspection without permission",
+			method:         http.MethodPost,
+			path:           "/v1/introspect",
+			body:           map[string]any{"token": "any"},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apierror.CodeForbidden,
+			expectedType:   "permission_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(t, router, tt.method, tt.path, "envelope-user", tt.body)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+
+			var response apierror.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCode, response.Error.Code)
+			assert.Equal(t, tt.expectedType, response.Error.Type)
+			assert.NotEmpty(t, response.Error.Message)
+			assert.Equal(t, w.Header().Get(constant.HeaderRequestID), response.Error.RequestID)
+			assert.NotEmpty(t, response.Error.RequestID)
+		})
+	}
+}
+
+func TestListAPIKeys_Pagination(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "paging-user@example.com"
+
+	for i := range 5 {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": fmt.Sprintf("key-%d", i)})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	list := func(t *testing.T, path string) api_keys.ListResponse {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodGet, path, username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var page api_keys.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "list", page.Object)
+		return page
+	}
+
+	t.Run("result set smaller than the page", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys", username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `false`, rawField(t, w.Body.Bytes(), "has_more"))
+		assert.NotContains(t, w.Body.String(), "next_cursor")
+
+		page := list(t, "/v1/api-keys")
+		assert.Len(t, page.Data, 5)
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("result set larger than the page", func(t *testing.T) {
+		seen := make(map[string]bool)
+
+		first := list(t, "/v1/api-keys?limit=2")
+		require.Len(t, first.Data, 2)
+		require.True(t, first.HasMore)
+		require.NotEmpty(t, first.NextCursor)
+
+		second := list(t, "/v1/api-keys?limit=2&cursor="+first.NextCursor)
+		require.Len(t, second.Data, 2)
+		require.True(t, second.HasMore)
+
+		last := list(t, "/v1/api-keys?limit=2&cursor="+second.NextCursor)
+		require.Len(t, last.Data, 1)
+		assert.False(t, last.HasMore)
+		assert.Empty(t, last.NextCursor)
+
+		for _, page := range []api_keys.ListResponse{first, second, last} {
+			for _, key := range page.Data {
+				assert.False(t, seen[key.ID], "key %s listed twice", key.ID)
+				seen[key.ID] = true
+			}
+		}
+		assert.Len(t, seen, 5)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, path := range []string{
+			"/v1/api-keys?limit=0",
+			"/v1/api-keys?limit=-1",
+			"/v1/api-keys?limit=ten",
+			"/v1/api-keys?cursor=not-a-cursor",
+		} {
+			w := performRequest(t, router, http.MethodGet, path, username, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		}
+	})
+
+	t.Run("limit above the maximum is clamped", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys?limit=1000", username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Warning"), "clamped to 200")
+	})
+}
+
+func TestListAPIKeys_PageSizes(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{
+		DefaultPageSize: 2,
+		MaxPageSize:     3,
+	})
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "page-size-user@example.com"
+
+	for i := range 5 {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": fmt.Sprintf("key-%d", i)})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedLen     int
+		expectedWarning string
+	}{
+		{
+			name:        "default page size without limit",
+			path:        "/v1/api-keys",
+			expectedLen: 2,
+		},
+		{
+			name:        "limit within the maximum",
+			path:        "/v1/api-keys?limit=3",
+			expectedLen: 3,
+		},
+		{
+			name:            "limit above the maximum is clamped",
+			path:            "/v1/api-keys?limit=4",
+			expectedLen:     3,
+			expectedWarning: `299 - "limit 4 exceeds the maximum page size, clamped to 3"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(t, router, http.MethodGet, tt.path, username, nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, tt.expectedWarning, w.Header().Get("Warning"))
+
+			var page api_keys.ListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Len(t, page.Data, tt.expectedLen)
+			assert.True(t, page.HasMore)
+		})
+	}
+}
+
+func TestStreamAPIKeys(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	// Small batches, so that the stream spans several store pages.
+	router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{MaxPageSize: 2})
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "stream-user@example.com"
+
+	created := make(map[string]bool)
+	for i := range 5 {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": fmt.Sprintf("key-%d", i)})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var key api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+		created[key.JTI] = true
+	}
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "other-user@example.com", map[string]any{"name": "other"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	stream := func(t *testing.T, path string) []api_keys.StreamedAPIKey {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodGet, path, username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		var keys []api_keys.StreamedAPIKey
+		for line := range strings.SplitSeq(strings.TrimSpace(w.Body.String()), "\n") {
+			var key api_keys.StreamedAPIKey
+			require.NoError(t, json.Unmarshal([]byte(line), &key), line)
+			assert.NotEmpty(t, key.Cursor)
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	ids := func(keys []api_keys.StreamedAPIKey) []string {
+		result := make([]string, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, key.ID)
+		}
+		return result
+	}
+
+	t.Run("StreamsEveryKeyOnce", func(t *testing.T) {
+		keys := stream(t, "/v1/api-keys:stream")
+		require.Len(t, keys, len(created))
+		seen := make(map[string]bool)
+		for _, key := range keys {
+			assert.True(t, created[key.ID], "key %s does not belong to the user", key.ID)
+			assert.False(t, seen[key.ID], "key %s streamed twice", key.ID)
+			seen[key.ID] = true
+		}
+	})
+
+	t.Run("ResumesAfterCursor", func(t *testing.T) {
+		all := stream(t, "/v1/api-keys:stream")
+		require.Len(t, all, 5)
+
+		rest := stream(t, "/v1/api-keys:stream?cursor="+all[2].Cursor)
+		assert.Equal(t, ids(all[3:]), ids(rest))
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys:stream?cursor=not-a-cursor", username, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownMethod", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys:purge", username, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestListAPIKeys_Filter(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const username = "filter-user@example.com"
+
+	keys := make(map[string]string)
+	for _, name := range []string{"prod-search", "Prod-Ads", "staging"} {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": name})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		keys[name] = created.JTI
+	}
+
+	// Rotating a key marks it as expired.
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys/"+keys["Prod-Ads"]+"/rotate", username, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	list := func(t *testing.T, path string) []api_keys.ApiKeyMetadata {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodGet, path, username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var page api_keys.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Data
+	}
+
+	t.Run("status", func(t *testing.T) {
+		expired := list(t, "/v1/api-keys?status=expired")
+		require.Len(t, expired, 1)
+		assert.Equal(t, keys["Prod-Ads"], expired[0].ID)
+
+		active := list(t, "/v1/api-keys?status=active")
+		assert.Len(t, active, 3)
+		for _, key := range active {
+			assert.Equal(t, api_keys.TokenStatusActive, key.Status)
+		}
+	})
+
+	t.Run("name contains", func(t *testing.T) {
+		matching := list(t, "/v1/api-keys?name_contains=prod")
+		assert.Len(t, matching, 3, "the rotated key and its replacement both match")
+		for _, key := range matching {
+			assert.Contains(t, strings.ToLower(key.Name), "prod")
+		}
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		matching := list(t, "/v1/api-keys?status=active&name_contains=PROD")
+		require.Len(t, matching, 2)
+		for _, key := range matching {
+			assert.NotEqual(t, keys["Prod-Ads"], key.ID)
+		}
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys?status=revoked", username, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRevokeAllTokens_GracePeriod(t *testing.T) {
+	manager, fakeClient, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{
+		RevocationGracePeriod: time.Hour,
+	})
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const owner = "graceful-revoker@example.com"
+
+	introspect := func(t *testing.T, tok string) bool {
+		t.Helper()
+
+		payload, err := json.Marshal(map[string]string{"token": tok})
+		require.NoError(t, err)
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/introspect", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(constant.HeaderUsername, "gateway-service")
+		req.Header.Set(constant.HeaderGroup, `["`+fixtures.TestIntrospectionGroup+`"]`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.Introspection
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Active
+	}
+
+	w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{"name": "graceful-key"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created api_keys.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.True(t, introspect(t, created.Token))
+
+	w = performRequest(t, router, http.MethodDelete, "/v1/tokens", owner, nil)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	assert.False(t, introspect(t, created.Token), "the API key must be rejected through the store right away")
+
+	for _, action := range fakeClient.Actions() {
+		assert.False(t, action.Matches("delete", "serviceaccounts"), "the Service Account must not be recreated before the grace period elapsed")
+	}
+
+	w = performRequest(t, router, http.MethodGet, "/v1/api-keys/"+created.JTI, owner, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `"`+api_keys.TokenStatusExpired+`"`, rawField(t, w.Body.Bytes(), "status"))
+}
+
+func TestRevokeAll_GracePeriodRecreatesServiceAccount(t *testing.T) {
+	testLogger := logger.Development()
+
+	existing := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alice-example-com-fc2398a7",
+			Namespace: fixtures.TestTenant + "-tier-free",
+		},
+	}
+	fakeClient := k8sfake.NewClientset(existing)
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+		fakeClient,
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(existing),
+		token.ManagerOptions{},
+	)
+
+	dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+	options := api_keys.ServiceOptions{RevocationGracePeriod: time.Hour}
+	user := &token.UserContext{Username: "alice@example.com", Groups: []string{"system:authenticated"}}
+
+	recreated := func() bool {
+		for _, action := range fakeClient.Actions() {
+			if action.Matches("delete", "serviceaccounts") {
+				return true
+			}
+		}
+		return false
+	}
+
+	store, err := api_keys.NewSQLiteStore(t.Context(), testLogger, dbPath, api_keys.StoreOptions{})
+	require.NoError(t, err)
+	result, err := api_keys.NewService(manager, store, options).RevokeAll(t.Context(), user)
+	require.NoError(t, err)
+	assert.True(t, result.MetadataMarked)
+	assert.False(t, result.SARecreated)
+	require.NoError(t, store.Close())
+
+	// The recreation is recorded in the store, so that it still happens after a restart.
+	store, err = api_keys.NewSQLiteStore(t.Context(), testLogger, dbPath, api_keys.StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+	service := api_keys.NewService(manager, store, options)
+
+	revoked, err := service.RevokeDue(t.Context(), time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, revoked)
+	assert.False(t, recreated(), "the Service Account must not be recreated before the grace period elapsed")
+
+	revoked, err = service.RevokeDue(t.Context(), time.Now().Add(time.Hour+time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, revoked)
+	assert.True(t, recreated(), "the Service Account must be deleted once the grace period elapsed")
+	_, err = fakeClient.CoreV1().ServiceAccounts(existing.Namespace).Get(t.Context(), existing.Name, metav1.GetOptions{})
+	require.NoError(t, err, "the Service Account must be recreated")
+
+	revoked, err = service.RevokeDue(t.Context(), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, revoked, "the Service Account must only be recreated once")
+}
+
+func TestRevokeAll_GracePeriodKeyCreatedInsideGraceWindow(t *testing.T) {
+	testLogger := logger.Development()
+
+	existing := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alice-example-com-fc2398a7",
+			Namespace: fixtures.TestTenant + "-tier-free",
+		},
+	}
+	fakeClient := k8sfake.NewClientset(existing)
+	fixtures.StubServiceAccountTokenCreation(fakeClient)
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+		fakeClient,
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(existing),
+		token.ManagerOptions{},
+	)
+
+	store, err := api_keys.NewSQLiteStore(t.Context(), testLogger, ":memory:", api_keys.StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+	service := api_keys.NewService(manager, store, api_keys.ServiceOptions{RevocationGracePeriod: time.Hour})
+	user := &token.UserContext{Username: "alice@example.com", Groups: []string{"system:authenticated"}}
+
+	deletions := func() int {
+		count := 0
+		for _, action := range fakeClient.Actions() {
+			if action.Matches("delete", "serviceaccounts") {
+				count++
+			}
+		}
+		return count
+	}
+
+	_, err = service.RevokeAll(t.Context(), user)
+	require.NoError(t, err)
+	require.Zero(t, deletions())
+
+	apiKey, err := service.CreateAPIKey(t.Context(), user, "after-revoke", "", nil, nil, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deletions(), "the Service Account must be recreated before minting the key")
+
+	// The key was minted for the recreated Service Account, the recreation is no longer due.
+	revoked, err := service.RevokeDue(t.Context(), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, revoked)
+	assert.Equal(t, 1, deletions(), "the key minted inside the grace window must not be revoked")
+
+	meta, err := store.Get(t.Context(), apiKey.JTI)
+	require.NoError(t, err)
+	assert.Equal(t, api_keys.TokenStatusActive, meta.Status)
+}
+
+// failingInvalidateStore fails to mark API key metadata as expired, e.g. during a database outage.
+type failingInvalidateStore struct {
+	api_keys.MetadataStore
+}
+
+func (s *failingInvalidateStore) InvalidateAll(context.Context, string) error {
+	return errors.New("database unavailable")
+}
+
+func TestRevokeAllTokens_MetadataFailure(t *testing.T) {
+	testLogger := logger.Development()
+
+	existing := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alice-example-com-fc2398a7",
+			Namespace: fixtures.TestTenant + "-tier-free",
+		},
+	}
+	fakeClient := k8sfake.NewClientset(existing)
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+		fakeClient,
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(existing),
+		token.ManagerOptions{},
+	)
+
+	store, err := api_keys.NewSQLiteStore(t.Context(), testLogger, ":memory:", api_keys.StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	service := api_keys.NewService(manager, &failingInvalidateStore{MetadataStore: store}, api_keys.ServiceOptions{})
+
+	t.Run("Service", func(t *testing.T) {
+		user := &token.UserContext{Username: "alice@example.com", Groups: []string{"system:authenticated"}}
+		result, err := service.RevokeAll(t.Context(), user)
+		require.Error(t, err)
+		assert.True(t, result.SARecreated)
+		assert.False(t, result.MetadataMarked)
+		assert.True(t, result.Partial())
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.DELETE("/v1/tokens", token.NewHandler(testLogger, fixtures.TestTenant, manager, token.HandlerOptions{}).ExtractUserInfo(),
+			api_keys.NewHandler(testLogger, service).RevokeAllTokens)
+
+		w := performRequest(t, router, http.MethodDelete, "/v1/tokens", "alice@example.com", nil)
+		require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"saRecreated":true,"metadataMarked":false}`, w.Body.String())
+	})
+
+	deleted := 0
+	for _, action := range fakeClient.Actions() {
+		if action.Matches("delete", "serviceaccounts") {
+			deleted++
+		}
+	}
+	assert.Equal(t, 2, deleted, "the Service Account must be recreated on each revocation")
+}
+
+func TestRevokeAllTokens_Scope(t *testing.T) {
+	testLogger := logger.Development()
+	const owner = "alice@example.com"
+
+	tests := []struct {
+		name                string
+		scope               string
+		expectedCode        int
+		expectedKeyStatus   string
+		expectedSARecreated bool
+	}{
+		{
+			name:                "default revokes API keys and ephemeral tokens",
+			expectedCode:        http.StatusNoContent,
+			expectedKeyStatus:   api_keys.TokenStatusExpired,
+			expectedSARecreated: true,
+		},
+		{
+			name:                "all revokes API keys and ephemeral tokens",
+			scope:               "all",
+			expectedCode:        http.StatusNoContent,
+			expectedKeyStatus:   api_keys.TokenStatusExpired,
+			expectedSARecreated: true,
+		},
+		{
+			name:              "named revokes API keys only",
+			scope:             "named",
+			expectedCode:      http.StatusNoContent,
+			expectedKeyStatus: api_keys.TokenStatusExpired,
+		},
+		{
+			name:              "ephemeral is not a scope",
+			scope:             "ephemeral",
+			expectedCode:      http.StatusBadRequest,
+			expectedKeyStatus: api_keys.TokenStatusActive,
+		},
+		{
+			name:              "unknown scope",
+			scope:             "expired",
+			expectedCode:      http.StatusBadRequest,
+			expectedKeyStatus: api_keys.TokenStatusActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alice-example-com-fc2398a7",
+					Namespace: fixtures.TestTenant + "-tier-free",
+				},
+			}
+			fakeClient := k8sfake.NewClientset(existing)
+			fixtures.StubServiceAccountTokenCreation(fakeClient)
+			manager := token.NewManager(
+				testLogger,
+				fixtures.TestTenant,
+				tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+				fakeClient,
+				fixtures.NewNamespaceLister(),
+				fixtures.NewServiceAccountLister(existing),
+				token.ManagerOptions{},
+			)
+			router, cleanupRouter := fixtures.SetupTestRouter(manager)
+			defer func() {
+				if err := cleanupRouter(); err != nil {
+					t.Logf("Router cleanup error: %v", err)
+				}
+			}()
+
+			w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{"name": "named-key"})
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var created api_keys.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+			w = performRequest(t, router, http.MethodPost, "/v1/tokens", owner, map[string]any{"expiration": "1h"})
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+			path := "/v1/tokens"
+			if tt.scope != "" {
+				path += "?scope=" + tt.scope
+			}
+			w = performRequest(t, router, http.MethodDelete, path, owner, nil)
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+
+			w = performRequest(t, router, http.MethodGet, "/v1/api-keys/"+created.JTI, owner, nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.JSONEq(t, `"`+tt.expectedKeyStatus+`"`, rawField(t, w.Body.Bytes(), "status"))
+
+			// Ephemeral tokens are only revoked by recreating the Service Account.
+			saRecreated := false
+			for _, action := range fakeClient.Actions() {
+				saRecreated = saRecreated || action.Matches("delete", "serviceaccounts")
+			}
+			assert.Equal(t, tt.expectedSARecreated, saRecreated)
+		})
+	}
+}
+
+func TestRevokeAllTokens_Confirmation(t *testing.T) {
+	createKey := func(t *testing.T, router *gin.Engine, owner string) string {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", owner, map[string]any{"name": "key"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.JTI
+	}
+
+	keyStatus := func(t *testing.T, router *gin.Engine, owner, id string) string {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys/"+id, owner, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var status string
+		require.NoError(t, json.Unmarshal([]byte(rawField(t, w.Body.Bytes(), "status")), &status))
+		return status
+	}
+
+	t.Run("ConfirmationRequired", func(t *testing.T) {
+		manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+		defer cleanup()
+		options := api_keys.ServiceOptions{
+			RevokeConfirmationTTL:    time.Minute,
+			RevokeConfirmationSecret: []byte("revoke-confirmation-secret-of-32b"),
+		}
+		router, cleanupRouter := fixtures.SetupTestRouterWithOptions(manager, options)
+		defer func() {
+			if err := cleanupRouter(); err != nil {
+				t.Logf("Router cleanup error: %v", err)
+			}
+		}()
+		options.RevokeConfirmationSecret = []byte("another-confirmation-secret-32b!")
+		otherRouter, cleanupOtherRouter := fixtures.SetupTestRouterWithOptions(manager, options)
+		defer func() {
+			if err := cleanupOtherRouter(); err != nil {
+				t.Logf("Router cleanup error: %v", err)
+			}
+		}()
+
+		const owner = "careful-revoker@example.com"
+		id := createKey(t, router, owner)
+
+		w := performRequest(t, router, http.MethodDelete, "/v1/tokens", owner, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var confirmation api_keys.RevokeConfirmation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &confirmation))
+		assert.NotEmpty(t, confirmation.Confirm)
+		assert.Equal(t, api_keys.RevokeScopeAll, confirmation.Scope)
+		assert.Equal(t, 1, confirmation.ActiveAPIKeys)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), confirmation.ExpiresAt, 5*time.Second)
+		assert.Equal(t, api_keys.TokenStatusActive, keyStatus(t, router, owner, id), "nothing must be revoked before confirmation")
+
+		w = performRequest(t, otherRouter, http.MethodDelete, "/v1/tokens", owner, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var otherConfirmation api_keys.RevokeConfirmation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &otherConfirmation))
+
+		expires, _, _ := strings.Cut(confirmation.Confirm, ".")
+		unkeyed := sha256.Sum256([]byte("revoke\x00" + owner + "\x00" + string(api_keys.RevokeScopeAll) + "\x00" + expires))
+
+		for name, confirm := range map[string]string{
+			"malformed":        "not-a-confirmation",
+			"tampered":         confirmation.Confirm + "0",
+			"expired":          "1." + strings.SplitN(confirmation.Confirm, ".", 2)[1],
+			"issued elsewhere": confirmation.Confirm,
+			"other scope":      confirmation.Confirm,
+			"unkeyed":          expires + "." + hex.EncodeToString(unkeyed[:]),
+			"other secret":     otherConfirmation.Confirm,
+		} {
+			requester, query := owner, "?confirm="+url.QueryEscape(confirm)
+			switch name {
+			case "issued elsewhere":
+				requester = "someone-else@example.com"
+			case "other scope":
+				query += "&scope=" + string(api_keys.RevokeScopeNamed)
+			}
+			w = performRequest(t, router, http.MethodDelete, "/v1/tokens"+query, requester, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, "%s confirmation: %s", name, w.Body.String())
+		}
+		assert.Equal(t, api_keys.TokenStatusActive, keyStatus(t, router, owner, id))
+
+		w = performRequest(t, router, http.MethodDelete, "/v1/tokens?confirm="+url.QueryEscape(confirmation.Confirm), owner, nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		assert.Equal(t, api_keys.TokenStatusExpired, keyStatus(t, router, owner, id))
+	})
+
+	t.Run("ConfirmationNotRequired", func(t *testing.T) {
+		manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+		defer cleanup()
+		router, cleanupRouter := fixtures.SetupTestRouter(manager)
+		defer func() {
+			if err := cleanupRouter(); err != nil {
+				t.Logf("Router cleanup error: %v", err)
+			}
+		}()
+
+		const owner = "hasty-revoker@example.com"
+		id := createKey(t, router, owner)
+
+		w := performRequest(t, router, http.MethodDelete, "/v1/tokens", owner, nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		assert.Equal(t, api_keys.TokenStatusExpired, keyStatus(t, router, owner, id))
+	})
+}
+
+func TestReconcileServiceAccounts(t *testing.T) {
+	manager, fakeClient, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	const (
+		tierNamespace    = fixtures.TestTenant + "-tier-free"
+		premiumNamespace = fixtures.TestTenant + "-tier-premium"
+	)
+
+	serviceAccountOf := func(t *testing.T, namespace, prefix string) *corev1.ServiceAccount {
+		t.Helper()
+
+		list, err := fakeClient.CoreV1().ServiceAccounts(namespace).List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		for i := range list.Items {
+			if strings.HasPrefix(list.Items[i].Name, prefix) {
+				return &list.Items[i]
+			}
+		}
+		t.Fatalf("no service account %s* in namespace %s", prefix, namespace)
+		return nil
+	}
+
+	for _, username := range []string{"active-user", "revoked-user"} {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": "key"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	w := performRequest(t, router, http.MethodDelete, "/v1/tokens", "revoked-user", nil)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	// The Service Account is recreated by the revocation, without the expiration of the revoked tokens. The informer
+	// cache of the fixture is empty, so that the recreation is done here.
+	revoked := serviceAccountOf(t, tierNamespace, "revoked-user-")
+	revoked.Annotations = nil
+	_, err := fakeClient.CoreV1().ServiceAccounts(tierNamespace).Update(t.Context(), revoked, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// Ephemeral tokens are not stored, the Service Account is kept until they expire.
+	w = performRequest(t, router, http.MethodPost, "/v1/tokens", "ephemeral-user", map[string]any{"expiration": "1h"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// The user moved from the premium tier, whose Service Account no active key was issued for.
+	moved := serviceAccountOf(t, tierNamespace, "active-user-")
+	for _, sa := range []*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "removed-user-0a1b2c3d", Namespace: tierNamespace, Labels: map[string]string{
+			"app.kubernetes.io/component":  "token-issuer",
+			"app.kubernetes.io/part-of":    "maas-api",
+			"maas.opendatahub.io/instance": fixtures.TestTenant,
+			"maas.opendatahub.io/tier":     "free",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-instance-user-4e5f6a7b", Namespace: "other-tier-free", Labels: map[string]string{
+			"app.kubernetes.io/component":  "token-issuer",
+			"app.kubernetes.io/part-of":    "maas-api",
+			"maas.opendatahub.io/instance": "other",
+			"maas.opendatahub.io/tier":     "free",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: moved.Name, Namespace: premiumNamespace, Labels: map[string]string{
+			"app.kubernetes.io/component":  "token-issuer",
+			"app.kubernetes.io/part-of":    "maas-api",
+			"maas.opendatahub.io/instance": fixtures.TestTenant,
+			"maas.opendatahub.io/tier":     "premium",
+		}}},
+	} {
+		_, err := fakeClient.CoreV1().ServiceAccounts(sa.Namespace).Create(t.Context(), sa, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	reconcile := func(t *testing.T, query string, groups string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/admin/reconcile-sa"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "cluster-admin")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	serviceAccounts := func(t *testing.T) []string {
+		t.Helper()
+
+		list, err := fakeClient.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, sa := range list.Items {
+			names = append(names, sa.Namespace+"/"+sa.Name)
+		}
+		return names
+	}
+
+	adminGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+	before := serviceAccounts(t)
+	require.Len(t, before, 6)
+
+	expectedOrphans := []string{
+		tierNamespace + "/removed-user-0a1b2c3d",
+		tierNamespace + "/" + revoked.Name,
+		premiumNamespace + "/" + moved.Name,
+	}
+
+	orphanNames := func(t *testing.T, w *httptest.ResponseRecorder, dryRun bool) []string {
+		t.Helper()
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response api_keys.ReconcileResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dryRun, response.DryRun)
+
+		names := []string{}
+		for _, sa := range response.ServiceAccounts {
+			assert.NotEmpty(t, sa.Tier)
+			names = append(names, sa.Namespace+"/"+sa.Name)
+		}
+		return names
+	}
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		w := reconcile(t, "?dryRun=false", `["system:authenticated"]`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.ElementsMatch(t, before, serviceAccounts(t))
+	})
+
+	t.Run("InvalidDryRun", func(t *testing.T) {
+		w := reconcile(t, "?dryRun=maybe", adminGroups)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("DryRunByDefault", func(t *testing.T) {
+		orphans := orphanNames(t, reconcile(t, "", adminGroups), true)
+		assert.ElementsMatch(t, expectedOrphans, orphans)
+		assert.ElementsMatch(t, before, serviceAccounts(t), "nothing must be deleted in dry-run mode")
+	})
+
+	t.Run("DeletesOnlyOrphans", func(t *testing.T) {
+		orphans := orphanNames(t, reconcile(t, "?dryRun=false", adminGroups), false)
+		assert.ElementsMatch(t, expectedOrphans, orphans)
+
+		remaining := serviceAccounts(t)
+		assert.Len(t, remaining, 3)
+		assert.Contains(t, remaining, "other-tier-free/other-instance-user-4e5f6a7b", "service accounts of other instances must be kept")
+		assert.Contains(t, remaining, tierNamespace+"/"+moved.Name, "the service account of the current tier must be kept")
+		assert.Contains(t, remaining, tierNamespace+"/"+serviceAccountOf(t, tierNamespace, "ephemeral-user-").Name,
+			"service accounts with valid ephemeral tokens must be kept")
+		for _, orphan := range orphans {
+			assert.NotContains(t, remaining, orphan)
+		}
+
+		// The Service Account of the user with an active key still issues tokens.
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "active-user", map[string]any{"name": "another-key"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Len(t, serviceAccounts(t), 3)
+	})
+}
+
+func TestGetUserIdentity(t *testing.T) {
+	testLogger := logger.Development()
+
+	const tierNamespace = fixtures.TestTenant + "-tier-free"
+
+	existing := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alice-example-com-fc2398a7",
+			Namespace: tierNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  "token-issuer",
+				"app.kubernetes.io/part-of":    "maas-api",
+				"maas.opendatahub.io/instance": fixtures.TestTenant,
+				"maas.opendatahub.io/tier":     "free",
+			},
+		},
+	}
+	fakeClient := k8sfake.NewClientset(existing)
+	fixtures.StubServiceAccountTokenCreation(fakeClient)
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+		fakeClient,
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(existing),
+		token.ManagerOptions{},
+	)
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	for _, name := range []string{"first-key", "second-key"} {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", "alice@example.com", map[string]any{"name": name})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	identity := func(t *testing.T, username, groups string, query url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+
+		path := "/v1/admin/users/" + url.PathEscape(username) + "/identity?" + query.Encode()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "cluster-admin")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	adminGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		w := identity(t, "alice@example.com", `["system:authenticated"]`, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("UserWithTokens", func(t *testing.T) {
+		w := identity(t, "alice@example.com", adminGroups, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.UserIdentity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "alice@example.com", response.Username)
+		assert.Equal(t, "alice-example-com-fc2398a7", response.Name)
+		assert.Equal(t, tierNamespace, response.Namespace)
+		assert.Equal(t, "free", response.Tier)
+		assert.True(t, response.Exists)
+		assert.Equal(t, 2, response.ActiveTokens)
+	})
+
+	t.Run("UserWithoutServiceAccount", func(t *testing.T) {
+		w := identity(t, "bob@example.com", adminGroups, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.UserIdentity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "bob@example.com", response.Username)
+		assert.NotEmpty(t, response.Name)
+		assert.Empty(t, response.Namespace)
+		assert.Empty(t, response.Tier)
+		assert.False(t, response.Exists)
+		assert.Zero(t, response.ActiveTokens)
+	})
+
+	t.Run("UserWithoutServiceAccountResolvedFromGroups", func(t *testing.T) {
+		w := identity(t, "bob@example.com", adminGroups, url.Values{"groups": {"premium-users"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.UserIdentity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "premium", response.Tier)
+		assert.Equal(t, fixtures.TestTenant+"-tier-premium", response.Namespace)
+		assert.False(t, response.Exists)
+		assert.Zero(t, response.ActiveTokens)
+	})
+
+	t.Run("UnknownGroup", func(t *testing.T) {
+		w := identity(t, "bob@example.com", adminGroups, url.Values{"groups": {"unknown-group"}})
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	})
+}
+
+func TestRevokeIssuedBefore(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	revoke := func(t *testing.T, query string, groups string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/admin/revoke"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "security-admin")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	status := func(t *testing.T, username, id string) string {
+		t.Helper()
+
+		w := performRequest(t, router, http.MethodGet, "/v1/api-keys/"+id, username, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return rawField(t, w.Body.Bytes(), "status")
+	}
+
+	keys := make(map[string]string)
+	for _, username := range []string{"user-a", "user-b"} {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": "key"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		keys[username] = created.JTI
+	}
+
+	adminGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+
+	t.Run("InvalidCutoff", func(t *testing.T) {
+		for _, query := range []string{"", "?issued_before=yesterday", "?issued_before=2025-01-01"} {
+			w := revoke(t, query, adminGroups)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("RequiresAdmin", func(t *testing.T) {
+		w := revoke(t, "?issued_before="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339), `["system:authenticated"]`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		for username, id := range keys {
+			assert.JSONEq(t, `"`+api_keys.TokenStatusActive+`"`, status(t, username, id))
+		}
+	})
+
+	t.Run("KeepsTokensIssuedAfterCutoff", func(t *testing.T) {
+		cutoff := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		w := revoke(t, "?issued_before="+cutoff.Format(time.RFC3339), adminGroups)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response api_keys.RevokeIssuedBeforeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(0), response.Revoked)
+		assert.True(t, cutoff.Equal(response.IssuedBefore))
+		for username, id := range keys {
+			assert.JSONEq(t, `"`+api_keys.TokenStatusActive+`"`, status(t, username, id))
+		}
+	})
+
+	t.Run("RevokesTokensOfAllUsersIssuedBeforeCutoff", func(t *testing.T) {
+		w := revoke(t, "?issued_before="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339), adminGroups)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `2`, rawField(t, w.Body.Bytes(), "revoked"))
+		for username, id := range keys {
+			assert.JSONEq(t, `"`+api_keys.TokenStatusExpired+`"`, status(t, username, id))
+		}
+	})
+}
+
+func TestImportTokens(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	importTokens := func(t *testing.T, groups string, tokens ...api_keys.ImportedToken) *httptest.ResponseRecorder {
+		t.Helper()
+
+		payload, err := json.Marshal(api_keys.ImportRequest{Tokens: tokens})
+		require.NoError(t, err)
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/admin/tokens/import", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(constant.HeaderUsername, "cluster-admin")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	keyStatus := func(t *testing.T, owner, id string) int {
+		t.Helper()
+
+		return performRequest(t, router, http.MethodGet, "/v1/api-keys/"+id, owner, nil).Code
+	}
+
+	adminGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+	now := time.Now().UTC()
+	imported := func(jti, username string) api_keys.ImportedToken {
+		return api_keys.ImportedToken{
+			JTI:            jti,
+			Username:       username,
+			Namespace:      fixtures.TestTenant + "-tier-free",
+			Name:           "restored-key",
+			CreationDate:   now.Add(-24 * time.Hour).Format(time.RFC3339),
+			ExpirationDate: now.Add(24 * time.Hour).Format(time.RFC3339),
+		}
+	}
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		w := importTokens(t, `["system:authenticated"]`, imported("denied-jti", "alice"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, http.StatusNotFound, keyStatus(t, "alice", "denied-jti"))
+	})
+
+	t.Run("ValidTokens", func(t *testing.T) {
+		withToken := imported("known-token-jti", "bob")
+		var err error
+		withToken.Token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": withToken.JTI}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		w := importTokens(t, adminGroups, imported("restored-jti", "alice"), withToken)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response api_keys.ImportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Imported)
+
+		w = performRequest(t, router, http.MethodGet, "/v1/api-keys/restored-jti", "alice", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var meta api_keys.ApiKeyMetadata
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+		assert.Equal(t, "restored-key", meta.Name)
+		assert.Equal(t, api_keys.TokenStatusActive, meta.Status)
+		assert.Equal(t, now.Add(-24*time.Hour).Format(time.RFC3339), meta.CreationDate)
+		assert.Equal(t, now.Add(24*time.Hour).Format(time.RFC3339), meta.ExpirationDate)
+
+		assert.Equal(t, http.StatusOK, keyStatus(t, "bob", "known-token-jti"))
+	})
+
+	t.Run("DuplicateTokens", func(t *testing.T) {
+		w := importTokens(t, adminGroups, imported("batch-duplicate-jti", "alice"), imported("batch-duplicate-jti", "bob"))
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "tokens[1].jti")
+
+		w = importTokens(t, adminGroups, imported("fresh-jti", "alice"), imported("restored-jti", "alice"))
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		assert.Equal(t, http.StatusNotFound, keyStatus(t, "alice", "fresh-jti"), "no token of the batch must be imported")
+	})
+
+	t.Run("InvalidDates", func(t *testing.T) {
+		malformed := imported("malformed-jti", "alice")
+		malformed.CreationDate = "yesterday"
+		expired := imported("expired-jti", "alice")
+		expired.ExpirationDate = now.Add(-time.Hour).Format(time.RFC3339)
+		future := imported("future-jti", "alice")
+		future.CreationDate = now.Add(time.Hour).Format(time.RFC3339)
+
+		w := importTokens(t, adminGroups, malformed, expired, future)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		details, ok := response.Error.Details.(map[string]any)
+		require.True(t, ok)
+		assert.Contains(t, details, "tokens[0].creationDate")
+		assert.Contains(t, details, "tokens[1].expirationDate")
+		assert.Contains(t, details, "tokens[2].creationDate")
+
+		for _, jti := range []string{"malformed-jti", "expired-jti", "future-jti"} {
+			assert.Equal(t, http.StatusNotFound, keyStatus(t, "alice", jti))
+		}
+	})
+
+	t.Run("TokenNotMatchingJTI", func(t *testing.T) {
+		mismatched := imported("mismatched-jti", "alice")
+		var err error
+		mismatched.Token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "other-jti"}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		w := importTokens(t, adminGroups, mismatched)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "tokens[0].token")
+	})
+}
+
+func TestSearchTokens(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	search := func(t *testing.T, query string, groups string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/admin/tokens"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "support-engineer")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	keys := make(map[string]string)
+	for _, username := range []string{"user-a", "user-b"} {
+		w := performRequest(t, router, http.MethodPost, "/v1/api-keys", username, map[string]any{"name": "key"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var created api_keys.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		keys[username] = created.JTI
+	}
+
+	adminGroups := `["` + fixtures.TestIntrospectionGroup + `"]`
+
+	t.Run("RequiresPrefix", func(t *testing.T) {
+		w := search(t, "?jti_prefix=%20", adminGroups)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("RequiresAdmin", func(t *testing.T) {
+		w := search(t, "?jti_prefix="+keys["user-a"][:8], `["system:authenticated"]`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ReturnsMatchingTokensOfAnyUser", func(t *testing.T) {
+		for username, jti := range keys {
+			w := search(t, "?jti_prefix="+url.QueryEscape(jti), adminGroups)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response api_keys.TokenSearchResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.Len(t, response.Data, 1, "the token of the other user must not match")
+			assert.Equal(t, jti, response.Data[0].ID)
+			assert.Equal(t, username, response.Data[0].Username)
+			assert.False(t, response.HasMore)
+		}
+	})
+
+	t.Run("ExcludesNonMatchingTokens", func(t *testing.T) {
+		w := search(t, "?jti_prefix=not-a-jti", adminGroups)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `[]`, rawField(t, w.Body.Bytes(), "data"))
+	})
+
+	t.Run("Paginates", func(t *testing.T) {
+		// The JTIs of the stubbed tokens share the mock-jti- prefix.
+		w := search(t, "?jti_prefix=mock-jti-&limit=1", adminGroups)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var first api_keys.TokenSearchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+		require.Len(t, first.Data, 1)
+		require.True(t, first.HasMore)
+
+		w = search(t, "?jti_prefix=mock-jti-&limit=1&cursor="+first.NextCursor, adminGroups)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var second api_keys.TokenSearchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+		require.Len(t, second.Data, 1)
+		assert.False(t, second.HasMore)
+		assert.ElementsMatch(t, []string{keys["user-a"], keys["user-b"]}, []string{first.Data[0].ID, second.Data[0].ID})
+	})
+}
+
+// rawField returns the raw JSON of a top-level field of the document.
+func rawField(t *testing.T, document []byte, field string) string {
+	t.Helper()
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(document, &fields))
+	require.Contains(t, fields, field)
+	return string(fields[field])
+}
@@ -0,0 +1,234 @@
+package api_keys
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// migration is a versioned schema change. Steps must be idempotent: databases created before versioned
+// migrations were introduced already have some of the changes applied, without them being recorded.
+type migration struct {
+	version     int
+	description string
+	apply       func(ctx context.Context, s *SQLStore) error
+}
+
+// migrations are applied in order, a new schema change is appended with the next version.
+// Applied migrations must never be modified.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create tokens table",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			// Use TEXT for timestamps - works for both SQLite and PostgreSQL
+			// SQLite doesn't have TIMESTAMPTZ, and TEXT is portable
+			createTableQuery := `
+			CREATE TABLE IF NOT EXISTS tokens (
+				id TEXT PRIMARY KEY,
+				username TEXT NOT NULL,
+				name TEXT NOT NULL,
+				description TEXT,
+				creation_date TEXT NOT NULL,
+				expiration_date TEXT NOT NULL
+			)`
+			if _, err := s.db.ExecContext(ctx, createTableQuery); err != nil {
+				return fmt.Errorf("failed to create table: %w", err)
+			}
+
+			if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_tokens_username ON tokens(username)`); err != nil {
+				return fmt.Errorf("failed to create username index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version:     2,
+		description: "add rotated_from to tokens for key rotation",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "rotated_from", "TEXT")
+		},
+	},
+	{
+		version:     3,
+		description: "add token_hash to tokens for token introspection",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "token_hash", "TEXT")
+		},
+	},
+	{
+		version:     4,
+		description: "add models to tokens for model-scoped keys",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "models", "TEXT")
+		},
+	},
+	{
+		version:     5,
+		description: "add token_jti to tokens for key renewal",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "token_jti", "TEXT")
+		},
+	},
+	{
+		version:     6,
+		description: "add metadata to tokens for owner-defined key/value pairs",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "metadata", "TEXT")
+		},
+	},
+	{
+		version:     7,
+		description: "index token ids for JTI prefix search",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			// LIKE 'prefix%' only uses an index matching its semantics: pattern operators in PostgreSQL,
+			// whatever the database collation, and a case-insensitive one in SQLite, where LIKE ignores case.
+			query := `CREATE INDEX IF NOT EXISTS idx_tokens_id_prefix ON tokens(id COLLATE NOCASE)`
+			if s.dbType == DBTypePostgres {
+				query = `CREATE INDEX IF NOT EXISTS idx_tokens_id_prefix ON tokens(id text_pattern_ops)`
+			}
+			if _, err := s.db.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("failed to create id prefix index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version:     8,
+		description: "add source to tokens for the client keys are created from",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "source", "TEXT")
+		},
+	},
+	{
+		version:     9,
+		description: "add id_prefix to tokens to tell legacy IDs apart from the ones of other issuers",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "id_prefix", "TEXT")
+		},
+	},
+	{
+		version:     10,
+		description: "add renewed_at to tokens for the issue date of the token of extended keys",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "renewed_at", "TEXT")
+		},
+	},
+	{
+		version:     11,
+		description: "create pending_revocations table for service account recreations deferred by the grace period",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			createTableQuery := `
+			CREATE TABLE IF NOT EXISTS pending_revocations (
+				username TEXT PRIMARY KEY,
+				user_groups TEXT,
+				due_at TEXT NOT NULL
+			)`
+			if _, err := s.db.ExecContext(ctx, createTableQuery); err != nil {
+				return fmt.Errorf("failed to create table: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version:     12,
+		description: "add namespace to tokens for the Service Account they were issued for",
+		apply: func(ctx context.Context, s *SQLStore) error {
+			return s.ensureColumn(ctx, "tokens", "namespace", "TEXT")
+		},
+	},
+}
+
+// migrate applies the migrations that are not recorded in the schema_migrations table yet, in order.
+func (s *SQLStore) migrate(ctx context.Context) error {
+	createTableQuery := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`
+	if _, err := s.db.ExecContext(ctx, createTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	recordQuery := fmt.Sprintf(`INSERT INTO schema_migrations (version, description, applied_at) VALUES (%s, %s, %s)`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3))
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		if err := m.apply(ctx, s); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+
+		appliedAt := time.Now().UTC().Format(time.RFC3339)
+		_, err := s.db.ExecContext(ctx, recordQuery, m.version, m.description, appliedAt)
+		// Another replica sharing the database may have applied and recorded the migration concurrently.
+		if err != nil && !isUniqueViolation(err) {
+			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+
+		s.logger.Info("Applied schema migration", "version", m.version, "description", m.description)
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the versions recorded in the schema_migrations table.
+func (s *SQLStore) appliedMigrations(ctx context.Context) (map[int]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// ensureColumn adds the column to the table if it does not exist yet.
+func (s *SQLStore) ensureColumn(ctx context.Context, table, column, definition string) error {
+	if s.dbType == DBTypePostgres {
+		//nolint:gosec // G201: Safe - table and column names are constants, not user input
+		query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, table, column, definition)
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to add column %s to %s: %w", column, table, err)
+		}
+		return nil
+	}
+
+	var count int
+	row := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column)
+	if err := row.Scan(&count); err != nil {
+		return fmt.Errorf("failed to inspect columns of %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	//nolint:gosec // G201: Safe - table and column names are constants, not user input
+	query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to add column %s to %s: %w", column, table, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package api_keys
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func appliedVersions(t *testing.T, db *sql.DB) []int {
+	t.Helper()
+
+	rows, err := db.QueryContext(t.Context(), `SELECT version FROM schema_migrations ORDER BY version`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		require.NoError(t, rows.Scan(&version))
+		versions = append(versions, version)
+	}
+	require.NoError(t, rows.Err())
+	return versions
+}
+
+func latestVersions() []int {
+	versions := make([]int, 0, len(migrations))
+	for _, m := range migrations {
+		versions = append(versions, m.version)
+	}
+	return versions
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("FreshDatabase", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+
+		store, err := NewSQLiteStore(t.Context(), logger.Development(), dbPath, StoreOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, latestVersions(), appliedVersions(t, store.db))
+		require.NoError(t, store.Close())
+
+		// Reopening the database applies nothing new.
+		store, err = NewSQLiteStore(t.Context(), logger.Development(), dbPath, StoreOptions{})
+		require.NoError(t, err)
+		defer store.Close()
+		assert.Equal(t, latestVersions(), appliedVersions(t, store.db))
+	})
+
+	t.Run("OlderSchema", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+
+		// Schema created before key rotation was introduced, without versioned migrations.
+		db, err := sql.Open(driverSQLite, dbPath)
+		require.NoError(t, err)
+		_, err = db.ExecContext(t.Context(), `
+		CREATE TABLE tokens (
+			id TEXT PRIMARY KEY,
+			username TEXT NOT NULL,
+			name TEXT NOT NULL,
+			description TEXT,
+			creation_date TEXT NOT NULL,
+			expiration_date TEXT NOT NULL
+		)`)
+		require.NoError(t, err)
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		_, err = db.ExecContext(t.Context(),
+			`INSERT INTO tokens (id, username, name, creation_date, expiration_date) VALUES ('jti-old', 'user1', 'old', ?, ?)`,
+			time.Now().UTC().Format(time.RFC3339), expiresAt)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		store, err := NewSQLiteStore(t.Context(), logger.Development(), dbPath, StoreOptions{})
+		require.NoError(t, err)
+		defer store.Close()
+		assert.Equal(t, latestVersions(), appliedVersions(t, store.db))
+
+		old, err := store.Get(t.Context(), "jti-old")
+		require.NoError(t, err)
+		assert.Equal(t, "old", old.Name)
+
+		require.NoError(t, store.Add(t.Context(), "user1", &APIKey{
+			Token:       token.Token{Token: "new-token", JTI: "jti-new", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			Name:        "new",
+			RotatedFrom: "jti-old",
+			Models:      []string{"llm/facebook-opt-125m-simulated"},
+		}))
+		rotated, err := store.Get(t.Context(), "jti-new")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-old", rotated.RotatedFrom)
+		assert.Equal(t, []string{"llm/facebook-opt-125m-simulated"}, rotated.Models)
+	})
+
+	t.Run("PartiallyRecorded", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+
+		store, err := NewSQLiteStore(t.Context(), logger.Development(), dbPath, StoreOptions{})
+		require.NoError(t, err)
+
+		// Columns already present but not recorded, e.g. added before versioned migrations, are left untouched.
+		_, err = store.db.ExecContext(t.Context(), `DELETE FROM schema_migrations WHERE version > 1`)
+		require.NoError(t, err)
+		require.NoError(t, store.migrate(t.Context()))
+		assert.Equal(t, latestVersions(), appliedVersions(t, store.db))
+		require.NoError(t, store.Close())
+	})
+}
@@ -0,0 +1,106 @@
+package api_keys
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultListLimit is the number of API keys listed per page when no limit is requested,
+	// unless ServiceOptions.DefaultPageSize is set.
+	DefaultListLimit = 50
+	// MaxListLimit is the maximum number of API keys listed per page, unless ServiceOptions.MaxPageSize is set.
+	MaxListLimit = 200
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// keysetSeparator separates the creation date from the ID in keyset cursors. It occurs in neither.
+const keysetSeparator = "|"
+
+// listPage returns the offset and limit requested by the cursor and limit query parameters.
+// A limit exceeding maxLimit is clamped to it, which is reported in a Warning header.
+func listPage(c *gin.Context, defaultLimit, maxLimit int) (int, int, error) {
+	limit := defaultLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			return 0, 0, fmt.Errorf("invalid limit %q, expected a positive number", value)
+		}
+	}
+	if limit > maxLimit {
+		c.Header("Warning", fmt.Sprintf(`299 - "limit %d exceeds the maximum page size, clamped to %d"`, limit, maxLimit))
+		limit = maxLimit
+	}
+
+	offset := 0
+	if value := c.Query("cursor"); value != "" {
+		var err error
+		if offset, err = decodeCursor(value); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return offset, limit, nil
+}
+
+// listFilter returns the filter requested by the status and name_contains query parameters.
+func listFilter(c *gin.Context) (ListFilter, error) {
+	filter := ListFilter{NameContains: strings.TrimSpace(c.Query("name_contains"))}
+
+	switch status := c.Query("status"); status {
+	case "", TokenStatusActive, TokenStatusExpired:
+		filter.Status = status
+	default:
+		return ListFilter{}, fmt.Errorf("invalid status %q, expected %s or %s", status, TokenStatusActive, TokenStatusExpired)
+	}
+
+	return filter, nil
+}
+
+// encodeCursor returns the opaque cursor of the page starting at offset.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// encodeKeysetCursor returns the opaque cursor resuming a listing after the key.
+func encodeKeysetCursor(key ApiKeyMetadata) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key.CreationDate + keysetSeparator + key.ID))
+}
+
+func decodeKeysetCursor(cursor string) (*KeysetCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	creationDate, id, found := strings.Cut(string(raw), keysetSeparator)
+	if !found || id == "" {
+		return nil, errInvalidCursor
+	}
+	if _, err := time.Parse(time.RFC3339, creationDate); err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &KeysetCursor{CreationDate: creationDate, ID: id}, nil
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, errInvalidCursor
+	}
+
+	return offset, nil
+}
@@ -2,7 +2,14 @@ package api_keys
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
@@ -11,18 +18,89 @@ import (
 type Service struct {
 	tokenManager *token.Manager
 	store        MetadataStore
+	options      ServiceOptions
 }
 
-func NewService(tokenManager *token.Manager, store MetadataStore) *Service {
+// ServiceOptions configures the behavior of the Service.
+type ServiceOptions struct {
+	// EnforceUniqueNames rejects the creation of an API key when the user already has an active key with the same name.
+	EnforceUniqueNames bool
+	// RevocationGracePeriod defers the recreation of the Service Account in RevokeAll, so that in-flight requests
+	// with ephemeral tokens are not interrupted. API keys are expired in the store right away, and the recreation is
+	// recorded in the store until RevokeDue performs it. 0 disables it.
+	RevocationGracePeriod time.Duration
+	// DefaultPageSize is the number of API keys listed per page when no limit is requested. 0 uses DefaultListLimit.
+	DefaultPageSize int
+	// MaxPageSize bounds the number of API keys listed per page, larger limits are clamped. 0 uses MaxListLimit.
+	MaxPageSize int
+	// MaxActiveKeysPerUser rejects the creation of an API key when the user already has that many active keys.
+	// 0 disables the cap.
+	MaxActiveKeysPerUser int
+	// RevokeConfirmationTTL requires revoking all tokens of a user in two steps: the first request gets a
+	// confirmation token valid for that long, which must be sent back to revoke. 0 revokes right away.
+	RevokeConfirmationTTL time.Duration
+	// RevokeConfirmationSecret keys the confirmation tokens, so that they cannot be computed without requesting them.
+	// Replicas must share it to accept the confirmation tokens issued by one another. A random secret is generated
+	// when empty, so that confirmation tokens are only accepted by the process that issued them.
+	RevokeConfirmationSecret []byte
+}
+
+// ErrInvalidConfirmation is returned when revoking with a confirmation token that is invalid, issued for another user
+// or scope, or expired.
+var ErrInvalidConfirmation = errors.New("confirmation token is invalid or expired")
+
+// ErrDuplicateName is returned when unique names are enforced and the user already has an active key with the name.
+var ErrDuplicateName = errors.New("an active api key with this name already exists")
+
+// KeyLimitError is returned when the user already has the maximum number of active API keys.
+type KeyLimitError struct {
+	Max int
+}
+
+func (e *KeyLimitError) Error() string {
+	return fmt.Sprintf("user already has %d active api keys, the maximum", e.Max)
+}
+
+func NewService(tokenManager *token.Manager, store MetadataStore, options ServiceOptions) *Service {
+	if len(options.RevokeConfirmationSecret) == 0 {
+		options.RevokeConfirmationSecret = make([]byte, 32)
+		// Never fails, see crypto/rand.Read.
+		_, _ = rand.Read(options.RevokeConfirmationSecret)
+	}
+
 	return &Service{
 		tokenManager: tokenManager,
 		store:        store,
+		options:      options,
 	}
 }
 
-func (s *Service) CreateAPIKey(ctx context.Context, user *token.UserContext, name string, description string, expiration time.Duration) (*APIKey, error) {
+// CreateAPIKey issues a new API key for the user. The models it is intended for, the key/value pairs attached
+// to it and the source of the request are recorded with its metadata.
+func (s *Service) CreateAPIKey(ctx context.Context, user *token.UserContext, name string, description string, models []string, metadata map[string]string, expiration time.Duration) (*APIKey, error) {
+	if s.options.EnforceUniqueNames {
+		_, err := s.store.GetActiveByName(ctx, user.Username, name)
+		if err == nil {
+			return nil, ErrDuplicateName
+		}
+		if !errors.Is(err, ErrTokenNotFound) {
+			return nil, fmt.Errorf("failed to check for api keys named %q: %w", name, err)
+		}
+	}
+
+	// Concurrent requests of the same user may each pass the check, exceeding the cap by the number of racing requests.
+	if s.options.MaxActiveKeysPerUser > 0 {
+		active, err := s.store.CountActive(ctx, user.Username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count active api keys: %w", err)
+		}
+		if active >= s.options.MaxActiveKeysPerUser {
+			return nil, &KeyLimitError{Max: s.options.MaxActiveKeysPerUser}
+		}
+	}
+
 	// Generate token
-	tok, err := s.tokenManager.GenerateToken(ctx, user, expiration, "")
+	tok, err := s.generateToken(ctx, user, expiration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -32,6 +110,9 @@ func (s *Service) CreateAPIKey(ctx context.Context, user *token.UserContext, nam
 		Token:       *tok,
 		Name:        name,
 		Description: description,
+		Models:      models,
+		Metadata:    metadata,
+		Source:      user.Source,
 	}
 
 	if err := s.store.Add(ctx, user.Username, apiKey); err != nil {
@@ -41,26 +122,554 @@ func (s *Service) CreateAPIKey(ctx context.Context, user *token.UserContext, nam
 	return apiKey, nil
 }
 
-func (s *Service) ListAPIKeys(ctx context.Context, user *token.UserContext) ([]ApiKeyMetadata, error) {
-	return s.store.List(ctx, user.Username)
+// generateToken mints a token for an API key of the user. When the recreation of the Service Account of the user is
+// still pending from RevokeAll, it is performed first: the token would otherwise be minted for the Service Account
+// about to be recreated, and revoked once the recreation is due while its metadata stays active.
+func (s *Service) generateToken(ctx context.Context, user *token.UserContext, expiration time.Duration) (*token.Token, error) {
+	revocation, err := s.store.GetRevocation(ctx, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if revocation != nil {
+		if err := s.revokePending(ctx, *revocation); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.tokenManager.GenerateToken(ctx, user, expiration, "")
+}
+
+// pageSizes returns the default and maximum number of API keys listed per page.
+func (s *Service) pageSizes() (int, int) {
+	maxSize := s.options.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = MaxListLimit
+	}
+
+	defaultSize := s.options.DefaultPageSize
+	if defaultSize <= 0 {
+		defaultSize = DefaultListLimit
+	}
+
+	return min(defaultSize, maxSize), maxSize
+}
+
+// ListAPIKeys returns a page of at most limit API keys of the user matching the filter, newest first, starting at offset.
+func (s *Service) ListAPIKeys(ctx context.Context, user *token.UserContext, filter ListFilter, offset, limit int) (*ListResponse, error) {
+	// One more key than requested tells whether there is a next page.
+	keys, err := s.store.ListPage(ctx, user.Username, filter, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ListResponse{
+		Object: "list",
+		Data:   keys,
+	}
+	if len(keys) > limit {
+		page.Data = keys[:limit]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(offset + limit)
+	}
+
+	return page, nil
+}
+
+// SearchTokens returns a page of at most limit tokens of any user whose ID starts with the prefix, ordered by ID,
+// starting at offset.
+func (s *Service) SearchTokens(ctx context.Context, prefix string, offset, limit int) (*TokenSearchResponse, error) {
+	// One more token than requested tells whether there is a next page.
+	tokens, err := s.store.ListByIDPrefix(ctx, prefix, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TokenSearchResponse{
+		Object: "list",
+		Data:   make([]TokenSearchResult, 0, min(len(tokens), limit)),
+	}
+	if len(tokens) > limit {
+		tokens = tokens[:limit]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(offset + limit)
+	}
+	for _, t := range tokens {
+		page.Data = append(page.Data, TokenSearchResult{ApiKeyMetadata: t, Username: t.Username})
+	}
+
+	return page, nil
+}
+
+// StreamAPIKeys calls fn with the API keys of the user listed after the cursor, newest first, in batches of at most
+// the maximum page size. The next batch is only read from the store once fn returned, and the iteration stops
+// when the context is canceled.
+func (s *Service) StreamAPIKeys(ctx context.Context, user *token.UserContext, after *KeysetCursor, fn func([]ApiKeyMetadata) error) error {
+	_, batchSize := s.pageSizes()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		keys, err := s.store.ListAfter(ctx, user.Username, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		if err := fn(keys); err != nil {
+			return err
+		}
+		if len(keys) < batchSize {
+			return nil
+		}
+
+		last := keys[len(keys)-1]
+		after = &KeysetCursor{CreationDate: last.CreationDate, ID: last.ID}
+	}
+}
+
+// ErrTokenNotActive is returned when an operation requires an active API key but the key has expired.
+var ErrTokenNotActive = errors.New("token is not active")
+
+// RotateAPIKey replaces the user's API key with a newly minted one carrying the same name, description, models
+// and metadata. The new key keeps the lifetime of the current token of the original one, see keyLifetime, and
+// references it via RotatedFrom, while the original key is marked as expired. The token of the original key no
+// longer passes introspection, but is still accepted by the cluster until its own expiration: only RevokeAll,
+// recreating the Service Account, invalidates it.
+func (s *Service) RotateAPIKey(ctx context.Context, user *token.UserContext, id string) (*APIKey, error) {
+	old, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not disclose the existence of keys owned by other users.
+	if old.Username != user.Username {
+		return nil, ErrTokenNotFound
+	}
+
+	if old.Status != TokenStatusActive {
+		return nil, ErrTokenNotActive
+	}
+
+	expiration, err := keyLifetime(old)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := s.generateToken(ctx, user, expiration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	apiKey := &APIKey{
+		Token:       *tok,
+		Name:        old.Name,
+		Description: old.Description,
+		RotatedFrom: old.ID,
+		Models:      old.Models,
+		Metadata:    old.Metadata,
+		Source:      user.Source,
+	}
+
+	if err := s.store.Replace(ctx, user.Username, old.ID, apiKey); err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			// The key expired or was rotated concurrently.
+			return nil, ErrTokenNotActive
+		}
+		return nil, fmt.Errorf("failed to persist api key metadata: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// ExtendAPIKey renews the user's API key with a newly minted token valid for the given expiration,
+// keeping the ID, name, description, models and metadata of the key. The token previously issued for the key
+// no longer passes introspection, but is still accepted by the cluster until its own expiration.
+func (s *Service) ExtendAPIKey(ctx context.Context, user *token.UserContext, id string, expiration time.Duration) (*APIKey, error) {
+	meta, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not disclose the existence of keys owned by other users, nor look keys up by a renewed token JTI.
+	if meta.Username != user.Username || meta.ID != id {
+		return nil, ErrTokenNotFound
+	}
+
+	if meta.Status != TokenStatusActive {
+		return nil, ErrTokenNotActive
+	}
+
+	tok, err := s.generateToken(ctx, user, expiration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if err := s.store.Renew(ctx, meta.ID, tok); err != nil {
+		return nil, fmt.Errorf("failed to persist api key metadata: %w", err)
+	}
+
+	// The key keeps its ID, the JTI of the new token is an implementation detail.
+	tok.JTI = meta.ID
+
+	return &APIKey{
+		Token:       *tok,
+		Name:        meta.Name,
+		Description: meta.Description,
+		RotatedFrom: meta.RotatedFrom,
+		Models:      meta.Models,
+		Metadata:    meta.Metadata,
+		Source:      meta.Source,
+	}, nil
+}
+
+// keyLifetime returns the lifetime the current token of the API key was issued with: the one the key was created
+// with or, once extended, the one it was last extended with.
+func keyLifetime(meta *ApiKeyMetadata) (time.Duration, error) {
+	issued := meta.CreationDate
+	if meta.RenewedAt != "" {
+		issued = meta.RenewedAt
+	}
+	created, err := time.Parse(time.RFC3339, issued)
+	if err != nil {
+		return 0, fmt.Errorf("invalid issue date for api key %s: %w", meta.ID, err)
+	}
+
+	expires, err := time.Parse(time.RFC3339, meta.ExpirationDate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expiration date for api key %s: %w", meta.ID, err)
+	}
+
+	return expires.Sub(created), nil
 }
 
 func (s *Service) GetAPIKey(ctx context.Context, id string) (*ApiKeyMetadata, error) {
 	return s.store.Get(ctx, id)
 }
 
+// Introspection describes an API key presented for introspection. Inactive keys carry no other detail.
+type Introspection struct {
+	Active    bool   `json:"active"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Tier      string `json:"tier,omitempty"`
+}
+
+// Introspect reports whether the given token is an active API key, based on the stored metadata.
+// Tokens that cannot be parsed, are unknown, do not match the issued key, or have expired are all reported
+// as inactive alike, so that introspection does not disclose why a token was rejected.
+func (s *Service) Introspect(ctx context.Context, tokenString string) (*Introspection, error) {
+	inactive := &Introspection{Active: false}
+
+	jti, err := token.ExtractJTI(tokenString)
+	if err != nil {
+		return inactive, nil //nolint:nilerr // Malformed tokens are reported as inactive.
+	}
+
+	meta, err := s.store.Get(ctx, jti)
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return inactive, nil
+		}
+		return nil, err
+	}
+
+	// The jti alone is not secret, make sure the presented token is the one that was issued.
+	if meta.TokenHash == "" || subtle.ConstantTimeCompare([]byte(meta.TokenHash), []byte(hashToken(tokenString))) != 1 {
+		return inactive, nil
+	}
+
+	if meta.Status != TokenStatusActive {
+		return inactive, nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, meta.ExpirationDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration date for api key %s: %w", meta.ID, err)
+	}
+
+	// The tier is informational, an unresolvable tier (e.g. after a tier config change) does not deactivate the key.
+	tier, _ := s.tokenManager.TokenTier(tokenString)
+
+	return &Introspection{
+		Active:    true,
+		ExpiresAt: expiresAt.Unix(),
+		Username:  meta.Username,
+		Tier:      tier,
+	}, nil
+}
+
+// RevokeResult reports which steps of revoking all tokens of a user succeeded, see Service.RevokeAll.
+type RevokeResult struct {
+	// SARecreated is set once the Service Account of the user was recreated, invalidating all their tokens,
+	// ephemeral ones included. It is also set when the user had no Service Account, hence no token.
+	SARecreated bool `json:"saRecreated"`
+	// MetadataMarked is set once the API key metadata of the user was marked as expired.
+	MetadataMarked bool `json:"metadataMarked"`
+}
+
+// Partial reports whether the tokens were revoked but their metadata was not marked as expired, so that listings
+// still show the API keys as active.
+func (r RevokeResult) Partial() bool {
+	return r.SARecreated && !r.MetadataMarked
+}
+
 // RevokeAll invalidates all tokens for the user (ephemeral and persistent).
 // It recreates the Service Account (invalidating all tokens) and marks API key metadata as expired.
-func (s *Service) RevokeAll(ctx context.Context, user *token.UserContext) error {
+// With a revocation grace period, the metadata is marked as expired first, so that introspection rejects the API keys
+// right away, and the recreation of the Service Account is recorded in the store, for RevokeDue to perform it once the
+// grace period elapsed. It survives restarts of maas-api. Creating an API key in the meantime recreates the Service
+// Account right away, cutting the grace period short, see generateToken. Ephemeral tokens issued in the meantime are
+// revoked along with the Service Account once the recreation is due.
+//
+// The result reports the steps that succeeded even when an error is returned: when marking the metadata fails after
+// the Service Account was recreated, the tokens are revoked nonetheless, see RevokeResult.Partial.
+func (s *Service) RevokeAll(ctx context.Context, user *token.UserContext) (RevokeResult, error) {
+	var result RevokeResult
+
+	if grace := s.options.RevocationGracePeriod; grace > 0 {
+		if err := s.store.InvalidateAll(ctx, user.Username); err != nil {
+			return result, fmt.Errorf("failed to mark metadata as expired: %w", err)
+		}
+		result.MetadataMarked = true
+		if err := s.store.ScheduleRevocation(ctx, user.Username, user.Groups, time.Now().Add(grace)); err != nil {
+			return result, fmt.Errorf("failed to schedule the service account recreation: %w", err)
+		}
+		return result, nil
+	}
+
 	// Revoke in K8s (recreate SA) - this invalidates all tokens
 	if err := s.tokenManager.RevokeTokens(ctx, user); err != nil {
-		return fmt.Errorf("failed to revoke tokens in k8s: %w", err)
+		return result, fmt.Errorf("failed to revoke tokens in k8s: %w", err)
 	}
+	result.SARecreated = true
 
 	// Mark API key metadata as expired (preserves history)
 	if err := s.store.InvalidateAll(ctx, user.Username); err != nil {
-		return fmt.Errorf("tokens revoked but failed to mark metadata as expired: %w", err)
+		return result, fmt.Errorf("tokens revoked but failed to mark metadata as expired: %w", err)
+	}
+	result.MetadataMarked = true
+
+	return result, nil
+}
+
+// RevokeDue recreates the Service Accounts whose recreation was deferred by RevokeAll and is due at the given time,
+// and returns the number of recreated ones. Failed recreations stay pending, to be retried by the next call.
+// Replicas sharing the store may both recreate the same Service Account, which only revokes tokens issued in between.
+func (s *Service) RevokeDue(ctx context.Context, now time.Time) (int, error) {
+	revocations, err := s.store.DueRevocations(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	revoked := 0
+	for _, revocation := range revocations {
+		if err := s.revokePending(ctx, revocation); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		revoked++
+	}
+
+	return revoked, errors.Join(errs...)
+}
+
+// revokePending recreates the Service Account of a pending revocation and removes it from the store.
+func (s *Service) revokePending(ctx context.Context, revocation PendingRevocation) error {
+	user := &token.UserContext{Username: revocation.Username, Groups: revocation.Groups}
+	if err := s.tokenManager.RevokeTokens(ctx, user); err != nil {
+		return fmt.Errorf("failed to revoke tokens of %s in k8s: %w", revocation.Username, err)
+	}
+	return s.store.CompleteRevocation(ctx, revocation.Username, revocation.DueAt)
+}
+
+// RevokeScope selects the tokens of a user revoked by Service.Revoke.
+type RevokeScope string
+
+const (
+	// RevokeScopeAll revokes all tokens of the user, see Service.RevokeAll.
+	RevokeScopeAll RevokeScope = "all"
+	// RevokeScopeNamed marks the API keys of the user as expired only, see Service.RevokeAPIKeys. Their tokens are
+	// still accepted by the gateway until they expire.
+	RevokeScopeNamed RevokeScope = "named"
+)
+
+// ParseRevokeScope parses a revocation scope, an empty value is RevokeScopeAll.
+func ParseRevokeScope(value string) (RevokeScope, error) {
+	switch scope := RevokeScope(value); scope {
+	case "":
+		return RevokeScopeAll, nil
+	case RevokeScopeAll, RevokeScopeNamed:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q, expected %s or %s", value, RevokeScopeAll, RevokeScopeNamed)
+	}
+}
+
+// Revoke revokes the tokens of the user in the scope.
+func (s *Service) Revoke(ctx context.Context, user *token.UserContext, scope RevokeScope) (RevokeResult, error) {
+	switch scope {
+	case RevokeScopeNamed:
+		return s.RevokeAPIKeys(ctx, user)
+	default:
+		return s.RevokeAll(ctx, user)
+	}
+}
+
+// RevokeAPIKeys marks the API key metadata of the user as expired, so that introspection rejects their API keys.
+// It does not revoke them at the gateway: the Service Account is left untouched, so that ephemeral tokens and the
+// tokens of the API keys, validated by the cluster, keep working until they expire.
+func (s *Service) RevokeAPIKeys(ctx context.Context, user *token.UserContext) (RevokeResult, error) {
+	if err := s.store.InvalidateAll(ctx, user.Username); err != nil {
+		return RevokeResult{}, fmt.Errorf("failed to mark metadata as expired: %w", err)
+	}
+	return RevokeResult{MetadataMarked: true}, nil
+}
+
+// RevokeConfirmation is returned instead of revoking, when confirmation is required, see
+// ServiceOptions.RevokeConfirmationTTL.
+type RevokeConfirmation struct {
+	// Confirm is the confirmation token to send back in the confirm query parameter.
+	Confirm string `json:"confirm"`
+	// Scope is the scope the confirmation token is valid for, it must be sent back along with it.
+	Scope     RevokeScope `json:"scope"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+	// ActiveAPIKeys is the number of API keys that would be revoked. Ephemeral tokens are not recorded.
+	ActiveAPIKeys int `json:"activeApiKeys"`
+}
+
+// RevokeConfirmationRequired reports whether revoking all tokens of a user must be confirmed.
+func (s *Service) RevokeConfirmationRequired() bool {
+	return s.options.RevokeConfirmationTTL > 0
+}
+
+// RequestRevokeConfirmation returns a confirmation token for revoking the tokens of the user in the scope, along with
+// the number of API keys that would be revoked.
+//
+// The token is an HMAC of the username, the scope and its expiration keyed by
+// ServiceOptions.RevokeConfirmationSecret, so that it is stateless yet cannot be computed without requesting it.
+func (s *Service) RequestRevokeConfirmation(ctx context.Context, user *token.UserContext, scope RevokeScope) (*RevokeConfirmation, error) {
+	count, err := s.store.CountActive(ctx, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active api keys: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.options.RevokeConfirmationTTL).Truncate(time.Second)
+	return &RevokeConfirmation{
+		Confirm:       s.revokeConfirmation(user.Username, scope, expiresAt.Unix()),
+		Scope:         scope,
+		ExpiresAt:     expiresAt.UTC(),
+		ActiveAPIKeys: count,
+	}, nil
+}
+
+// RevokeConfirmed revokes the tokens of the user in the scope, see Revoke, provided the confirmation token was issued
+// to them for the same scope by RequestRevokeConfirmation and has not expired. Returns ErrInvalidConfirmation
+// otherwise.
+func (s *Service) RevokeConfirmed(ctx context.Context, user *token.UserContext, scope RevokeScope, confirm string) (RevokeResult, error) {
+	expires, _, found := strings.Cut(confirm, ".")
+	if !found {
+		return RevokeResult{}, ErrInvalidConfirmation
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() >= expiresAt {
+		return RevokeResult{}, ErrInvalidConfirmation
+	}
+	if !hmac.Equal([]byte(confirm), []byte(s.revokeConfirmation(user.Username, scope, expiresAt))) {
+		return RevokeResult{}, ErrInvalidConfirmation
+	}
+
+	return s.Revoke(ctx, user, scope)
+}
+
+// revokeConfirmation derives the confirmation token of the user for the scope, expiring at the given Unix time.
+func (s *Service) revokeConfirmation(username string, scope RevokeScope, expiresAt int64) string {
+	expires := strconv.FormatInt(expiresAt, 10)
+	mac := hmac.New(sha256.New, s.options.RevokeConfirmationSecret)
+	mac.Write([]byte("revoke\x00" + username + "\x00" + string(scope) + "\x00" + expires))
+	return expires + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RevokeIssuedBefore marks the API keys of all users created before the cutoff as expired, so that introspection
+// rejects them. Service Accounts are left untouched: ephemeral tokens stay valid until they expire.
+func (s *Service) RevokeIssuedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	count, err := s.store.ExpireTokensIssuedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark metadata as expired: %w", err)
+	}
+
+	return count, nil
+}
+
+// ReconcileServiceAccounts prunes the Service Accounts no active API key was issued for, see
+// token.Manager.PruneServiceAccounts. In dry-run mode, the orphaned Service Accounts are only reported.
+func (s *Service) ReconcileServiceAccounts(ctx context.Context, dryRun bool) ([]token.ServiceAccountRef, error) {
+	active, err := s.store.ActiveServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenManager.PruneServiceAccounts(ctx, active, dryRun)
+}
+
+// UserIdentity is the identity the tokens of a user are issued for, see Service.UserIdentity.
+type UserIdentity struct {
+	token.ServiceAccountPreview
+	// ActiveTokens is the number of active API keys of the user. Ephemeral tokens are not recorded.
+	ActiveTokens int `json:"activeTokens"`
+}
+
+// UserIdentity returns the tier, namespace and Service Account the tokens of the user are issued for, resolved as
+// in token.Manager.PreviewServiceAccount, along with the number of their active API keys.
+func (s *Service) UserIdentity(ctx context.Context, username string, groups []string) (*UserIdentity, error) {
+	preview, err := s.tokenManager.PreviewServiceAccount(username, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.store.CountActive(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active api keys: %w", err)
+	}
+
+	return &UserIdentity{
+		ServiceAccountPreview: *preview,
+		ActiveTokens:          count,
+	}, nil
+}
+
+// ImportTokens adds the metadata of tokens issued outside of maas-api to the store, without issuing any token,
+// e.g. to rebuild the store after its loss. The tokens must have been validated, see ImportRequest.Validate.
+// Either all of them are imported, or none is.
+func (s *Service) ImportTokens(ctx context.Context, tokens []ImportedToken) error {
+	keys := make([]ImportedAPIKey, 0, len(tokens))
+	for _, imported := range tokens {
+		created, err := time.Parse(time.RFC3339, imported.CreationDate)
+		if err != nil {
+			return fmt.Errorf("invalid creation date for token %s: %w", imported.JTI, err)
+		}
+		expires, err := time.Parse(time.RFC3339, imported.ExpirationDate)
+		if err != nil {
+			return fmt.Errorf("invalid expiration date for token %s: %w", imported.JTI, err)
+		}
+
+		keys = append(keys, ImportedAPIKey{
+			APIKey: APIKey{
+				Token: token.Token{
+					Token:     imported.Token,
+					JTI:       imported.JTI,
+					IssuedAt:  created.Unix(),
+					ExpiresAt: expires.Unix(),
+					Namespace: imported.Namespace,
+				},
+				Name: imported.Name,
+			},
+			Username: imported.Username,
+		})
 	}
 
-	return nil
+	return s.store.Import(ctx, keys)
 }
@@ -3,24 +3,106 @@ package api_keys
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
 
 var ErrTokenNotFound = errors.New("token not found")
 
+// ErrDuplicateToken is returned by Add when a token with the same JTI is already stored.
+var ErrDuplicateToken = errors.New("token with this JTI already exists")
+
 const (
 	TokenStatusActive  = "active"
 	TokenStatusExpired = "expired"
 )
 
+// ListFilter restricts the tokens listed by ListPage. Zero values do not filter.
+type ListFilter struct {
+	// Status is TokenStatusActive or TokenStatusExpired. Revoked tokens are expired.
+	Status string
+	// NameContains matches tokens whose name contains it, ignoring case.
+	NameContains string
+}
+
+// KeysetCursor is the position of a token in the listing of the tokens of a user, newest first.
+type KeysetCursor struct {
+	CreationDate string
+	ID           string
+}
+
+// PendingRevocation is a recreation of the Service Account of a user deferred by the revocation grace period,
+// see Service.RevokeAll.
+type PendingRevocation struct {
+	Username string
+	// Groups are the groups of the user when they revoked their tokens, resolving the tier namespace of their
+	// Service Account.
+	Groups []string
+	DueAt  time.Time
+}
+
 type MetadataStore interface {
 	Add(ctx context.Context, username string, apiKey *APIKey) error
+	// Import adds the metadata of API keys issued outside of maas-api, all of them or none. Returns
+	// ErrDuplicateToken when one of them is already stored.
+	Import(ctx context.Context, keys []ImportedAPIKey) error
 
 	List(ctx context.Context, username string) ([]ApiKeyMetadata, error)
 
+	// ListPage returns at most limit tokens of a user matching the filter, newest first, skipping the first offset ones.
+	ListPage(ctx context.Context, username string, filter ListFilter, offset, limit int) ([]ApiKeyMetadata, error)
+
+	// ListAfter returns at most limit tokens of a user listed after the cursor, newest first, or from the newest token
+	// when after is nil. Unlike ListPage, it does not degrade with the number of tokens already listed.
+	ListAfter(ctx context.Context, username string, after *KeysetCursor, limit int) ([]ApiKeyMetadata, error)
+
+	// ListByIDPrefix returns at most limit tokens of any user whose ID starts with the prefix, ordered by ID,
+	// skipping the first offset ones. Tokens stored by other issuers, with another ID prefix, are not listed.
+	ListByIDPrefix(ctx context.Context, prefix string, offset, limit int) ([]ApiKeyMetadata, error)
+
+	// Get returns a token by its ID, or by the JTI of the token it was renewed with.
 	Get(ctx context.Context, jti string) (*ApiKeyMetadata, error)
 
+	// GetActiveByName returns the newest active token of a user with the given name.
+	// Returns ErrTokenNotFound if the user has no active token with that name.
+	GetActiveByName(ctx context.Context, username, name string) (*ApiKeyMetadata, error)
+
+	// Renew replaces the token of an active key with the given one, keeping the key ID, and extends
+	// its expiration to the one of the new token. Get finds the key by the JTI of the new token afterwards.
+	// Returns ErrTokenNotFound if no active token with the given ID exists.
+	Renew(ctx context.Context, id string, tok *token.Token) error
+
+	// Replace adds the new key of a user and marks the active key with the given ID, which it replaces, as expired,
+	// both or neither. Returns ErrTokenNotFound if no active token with that ID exists.
+	Replace(ctx context.Context, username, oldID string, apiKey *APIKey) error
+
+	// Invalidate marks a single active token as expired.
+	// Returns ErrTokenNotFound if no token with the given JTI exists.
+	Invalidate(ctx context.Context, jti string) error
 	// InvalidateAll marks all active tokens for a user as expired.
 	InvalidateAll(ctx context.Context, username string) error
+	// ExpireTokensIssuedBefore marks the active tokens of all users created before the cutoff as expired.
+	// Only the tokens stored with the ID prefix of the store are expired, tokens of other issuers are left untouched.
+	// Returns the number of tokens marked as expired.
+	ExpireTokensIssuedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// CountActive returns the number of active tokens of a user.
+	CountActive(ctx context.Context, username string) (int, error)
+
+	// ActiveServiceAccounts returns the Service Accounts active tokens were issued for, by user and namespace.
+	// The namespace is empty for tokens stored before it was recorded.
+	ActiveServiceAccounts(ctx context.Context) ([]token.ActiveServiceAccount, error)
+
+	// ScheduleRevocation records that the Service Account of the user must be recreated once dueAt passed,
+	// replacing the revocation already pending for the user, if any.
+	ScheduleRevocation(ctx context.Context, username string, groups []string, dueAt time.Time) error
+	// GetRevocation returns the revocation pending for the user, or nil when there is none.
+	GetRevocation(ctx context.Context, username string) (*PendingRevocation, error)
+	// DueRevocations returns the revocations pending for the users that are due at the given time, oldest first.
+	DueRevocations(ctx context.Context, now time.Time) ([]PendingRevocation, error)
+	// CompleteRevocation removes the revocation pending for the user, unless it was rescheduled after dueAt.
+	CompleteRevocation(ctx context.Context, username string, dueAt time.Time) error
 
 	Close() error
 }
@@ -2,13 +2,17 @@ package api_keys
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
 
 var (
@@ -20,21 +24,41 @@ type SQLStore struct {
 	db     *sql.DB
 	dbType DBType
 	logger *logger.Logger
+
+	// expirationGrace tolerates clock skew between the API server minting tokens and maas-api,
+	// a token is only considered expired once its expiration date is older than the grace window.
+	expirationGrace time.Duration
+
+	// idPrefix is prepended to the IDs of stored tokens and stripped from the ones read, so that the IDs
+	// of different issuers sharing or merging a database do not collide.
+	idPrefix string
 }
 
 var _ MetadataStore = (*SQLStore)(nil)
 
+// StoreOptions configures how the SQLStore stores and expires tokens.
+type StoreOptions struct {
+	// ExpirationGrace is the clock skew tolerated when deciding whether a token has expired.
+	ExpirationGrace time.Duration
+	// IDPrefix is the issuer prefix of stored token IDs. Tokens stored without any prefix, before it was set, are
+	// still found by their ID, while the ones stored by other issuers are not.
+	IDPrefix string
+}
+
 // NewSQLiteStore creates a SQLite store with a file path.
 // Use ":memory:" for an in-memory database (ephemeral, for testing).
 // Use a file path like "/data/maas-api.db" for persistent storage.
-func NewSQLiteStore(ctx context.Context, log *logger.Logger, dbPath string) (*SQLStore, error) {
+func NewSQLiteStore(ctx context.Context, log *logger.Logger, dbPath string, options StoreOptions) (*SQLStore, error) {
 	if dbPath == "" {
 		dbPath = sqliteMemory
 	}
 
 	dsn := dbPath
 	if dbPath != sqliteMemory {
-		dsn = fmt.Sprintf("%s?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000", dbPath)
+		var err error
+		if dsn, err = sqliteDSN(dbPath); err != nil {
+			return nil, err
+		}
 	}
 
 	db, err := sql.Open(driverSQLite, dsn)
@@ -49,10 +73,16 @@ func NewSQLiteStore(ctx context.Context, log *logger.Logger, dbPath string) (*SQ
 		return nil, fmt.Errorf("failed to ping SQLite database: %w", err)
 	}
 
-	s := &SQLStore{db: db, dbType: DBTypeSQLite, logger: log}
-	if err := s.initSchema(ctx); err != nil {
+	s := &SQLStore{
+		db:              db,
+		dbType:          DBTypeSQLite,
+		logger:          log,
+		expirationGrace: options.ExpirationGrace,
+		idPrefix:        options.IDPrefix,
+	}
+	if err := s.migrate(ctx); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	if dbPath == sqliteMemory {
@@ -63,32 +93,33 @@ func NewSQLiteStore(ctx context.Context, log *logger.Logger, dbPath string) (*SQ
 	return s, nil
 }
 
-func (s *SQLStore) Close() error {
-	return s.db.Close()
+// storedID returns the primary key of the token with the given ID.
+func (s *SQLStore) storedID(id string) string {
+	return s.idPrefix + id
 }
 
-func (s *SQLStore) initSchema(ctx context.Context) error {
-	// Use TEXT for timestamps - works for both SQLite and PostgreSQL
-	// SQLite doesn't have TIMESTAMPTZ, and TEXT is portable
-	createTableQuery := `
-	CREATE TABLE IF NOT EXISTS tokens (
-		id TEXT PRIMARY KEY,
-		username TEXT NOT NULL,
-		name TEXT NOT NULL,
-		description TEXT,
-		creation_date TEXT NOT NULL,
-		expiration_date TEXT NOT NULL
-	)`
+// publicID returns the ID of the token stored with the given primary key.
+func (s *SQLStore) publicID(id string) string {
+	return strings.TrimPrefix(id, s.idPrefix)
+}
 
-	if _, err := s.db.ExecContext(ctx, createTableQuery); err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
-	}
+// idMatch returns the condition matching the token with the given ID, and its arguments numbered from the first
+// placeholder. Tokens of this issuer match by their prefixed ID and legacy tokens, stored without any prefix, by the
+// ID itself. Tokens stored by other issuers never match, even when the ID carries their prefix.
+func (s *SQLStore) idMatch(id string, first int) (string, []any) {
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	condition := fmt.Sprintf(`((id = %s AND COALESCE(id_prefix, '') = %s) OR (id = %s AND COALESCE(id_prefix, '') = ''))`,
+		s.placeholder(first), s.placeholder(first+1), s.placeholder(first+2))
+	return condition, []any{s.storedID(id), s.idPrefix, id}
+}
 
-	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_tokens_username ON tokens(username)`); err != nil {
-		return fmt.Errorf("failed to create username index: %w", err)
-	}
+// activeCutoff returns the instant a token must expire after to be considered active.
+func (s *SQLStore) activeCutoff(now time.Time) time.Time {
+	return now.Add(-s.expirationGrace)
+}
 
-	return nil
+func (s *SQLStore) Close() error {
+	return s.db.Close()
 }
 
 // placeholder returns the appropriate placeholder for the database type.
@@ -97,7 +128,39 @@ func (s *SQLStore) placeholder(index int) string {
 	return placeholder(s.dbType, index)
 }
 
+// execer runs statements on the database, or within a transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
 func (s *SQLStore) Add(ctx context.Context, username string, apiKey *APIKey) error {
+	return s.insert(ctx, s.db, username, apiKey)
+}
+
+// Import adds the metadata of API keys issued outside of maas-api in a single transaction: either all of them are
+// added, or none is.
+func (s *SQLStore) Import(ctx context.Context, keys []ImportedAPIKey) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op once committed.
+	}()
+
+	for i := range keys {
+		if err := s.insert(ctx, tx, keys[i].Username, &keys[i].APIKey); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit imported token metadata: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) insert(ctx context.Context, db execer, username string, apiKey *APIKey) error {
 	jti := strings.TrimSpace(apiKey.JTI)
 	if jti == "" {
 		return ErrEmptyJTI
@@ -122,12 +185,46 @@ func (s *SQLStore) Add(ctx context.Context, username string, apiKey *APIKey) err
 
 	//nolint:gosec // G201: Safe - using placeholder indices, not user input
 	query := fmt.Sprintf(`
-	INSERT INTO tokens (id, username, name, description, creation_date, expiration_date)
-	VALUES (%s, %s, %s, %s, %s, %s)
-	`, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), s.placeholder(6))
+	INSERT INTO tokens (id, username, name, description, creation_date, expiration_date, rotated_from, token_hash, models, metadata, source,
+		id_prefix, namespace)
+	VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+	`, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), s.placeholder(6), s.placeholder(7), s.placeholder(8), s.placeholder(9),
+		s.placeholder(10), s.placeholder(11), s.placeholder(12), s.placeholder(13))
 
 	description := strings.TrimSpace(apiKey.Description)
-	_, err := s.db.ExecContext(ctx, query, jti, username, name, description, creationStr, expirationStr)
+	var rotatedFrom sql.NullString
+	if id := strings.TrimSpace(apiKey.RotatedFrom); id != "" {
+		rotatedFrom = sql.NullString{String: id, Valid: true}
+	}
+	var tokenHash sql.NullString
+	if apiKey.Token.Token != "" {
+		tokenHash = sql.NullString{String: hashToken(apiKey.Token.Token), Valid: true}
+	}
+	models, err := encodeModels(apiKey.Models)
+	if err != nil {
+		return err
+	}
+	metadata, err := encodeMetadata(apiKey.Metadata)
+	if err != nil {
+		return err
+	}
+	var source sql.NullString
+	if apiKey.Source != "" {
+		source = sql.NullString{String: apiKey.Source, Valid: true}
+	}
+	var idPrefix sql.NullString
+	if s.idPrefix != "" {
+		idPrefix = sql.NullString{String: s.idPrefix, Valid: true}
+	}
+	var namespace sql.NullString
+	if apiKey.Namespace != "" {
+		namespace = sql.NullString{String: apiKey.Namespace, Valid: true}
+	}
+	_, err = db.ExecContext(ctx, query, s.storedID(jti), username, name, description, creationStr, expirationStr, rotatedFrom, tokenHash, models, metadata,
+		source, idPrefix, namespace)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", ErrDuplicateToken, jti)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert token metadata: %w", err)
 	}
@@ -135,13 +232,14 @@ func (s *SQLStore) Add(ctx context.Context, username string, apiKey *APIKey) err
 }
 
 func (s *SQLStore) InvalidateAll(ctx context.Context, username string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	// Backdate by the grace window, so that the tokens are reported as expired right away.
+	cutoff := s.activeCutoff(time.Now()).UTC().Format(time.RFC3339)
 
 	//nolint:gosec // G201: Safe - using placeholder indices, not user input
 	query := fmt.Sprintf(`UPDATE tokens SET expiration_date = %s WHERE username = %s AND expiration_date > %s`,
 		s.placeholder(1), s.placeholder(2), s.placeholder(3))
 
-	result, err := s.db.ExecContext(ctx, query, now, username, now)
+	result, err := s.db.ExecContext(ctx, query, cutoff, username, cutoff)
 	if err != nil {
 		return fmt.Errorf("failed to mark tokens as expired: %w", err)
 	}
@@ -154,74 +252,587 @@ func (s *SQLStore) InvalidateAll(ctx context.Context, username string) error {
 	return nil
 }
 
+func (s *SQLStore) ExpireTokensIssuedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	// Backdate by the grace window, so that the tokens are reported as expired right away.
+	expiredAt := s.activeCutoff(time.Now()).UTC().Format(time.RFC3339)
+
+	// Tokens of other issuers sharing the database are not theirs to revoke. Legacy tokens, stored without any prefix,
+	// are only revoked by stores without a prefix either.
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`UPDATE tokens SET expiration_date = %s
+		WHERE creation_date < %s AND expiration_date > %s AND COALESCE(id_prefix, '') = %s`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4))
+
+	result, err := s.db.ExecContext(ctx, query, expiredAt, cutoff.UTC().Format(time.RFC3339), expiredAt, s.idPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark tokens as expired: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+func (s *SQLStore) Invalidate(ctx context.Context, jti string) error {
+	rows, err := s.expire(ctx, s.db, jti)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		// Either the token does not exist or it has already expired.
+		if _, err := s.Get(ctx, jti); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replace adds the new key and marks the key it replaces as expired in a single transaction: either both are
+// stored, or neither is.
+func (s *SQLStore) Replace(ctx context.Context, username, oldID string, apiKey *APIKey) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // No-op once committed.
+	}()
+
+	if err := s.insert(ctx, tx, username, apiKey); err != nil {
+		return err
+	}
+
+	rows, err := s.expire(ctx, tx, oldID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTokenNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit replaced token metadata: %w", err)
+	}
+	return nil
+}
+
+// expire marks the active token with the given ID as expired, and returns the number of tokens marked.
+func (s *SQLStore) expire(ctx context.Context, db execer, jti string) (int64, error) {
+	// Backdate by the grace window, so that the token is reported as expired right away.
+	cutoff := s.activeCutoff(time.Now()).UTC().Format(time.RFC3339)
+
+	match, matchArgs := s.idMatch(jti, 2)
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`UPDATE tokens SET expiration_date = %s WHERE %s AND expiration_date > %s`,
+		s.placeholder(1), match, s.placeholder(2+len(matchArgs)))
+
+	args := append(append([]any{cutoff}, matchArgs...), cutoff)
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark token as expired: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+func (s *SQLStore) Renew(ctx context.Context, id string, tok *token.Token) error {
+	if strings.TrimSpace(tok.JTI) == "" {
+		return ErrEmptyJTI
+	}
+
+	cutoff := s.activeCutoff(time.Now()).UTC().Format(time.RFC3339)
+	expirationStr := time.Unix(tok.ExpiresAt, 0).UTC().Format(time.RFC3339)
+	renewedAt := time.Now()
+	if tok.IssuedAt > 0 {
+		renewedAt = time.Unix(tok.IssuedAt, 0)
+	}
+	renewedStr := renewedAt.UTC().Format(time.RFC3339)
+	var namespace sql.NullString
+	if tok.Namespace != "" {
+		namespace = sql.NullString{String: tok.Namespace, Valid: true}
+	}
+
+	match, matchArgs := s.idMatch(id, 6)
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`UPDATE tokens SET expiration_date = %s, token_hash = %s, token_jti = %s, renewed_at = %s, namespace = %s
+		WHERE %s AND expiration_date > %s`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), match, s.placeholder(6+len(matchArgs)))
+
+	args := append(append([]any{expirationStr, hashToken(tok.Token), tok.JTI, renewedStr, namespace}, matchArgs...), cutoff)
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to renew token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLStore) CountActive(ctx context.Context, username string) (int, error) {
+	cutoff := s.activeCutoff(time.Now()).UTC()
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM tokens WHERE username = %s AND expiration_date > %s`, s.placeholder(1), s.placeholder(2))
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, query, username, cutoff.Format(time.RFC3339)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active tokens: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SQLStore) ActiveServiceAccounts(ctx context.Context) ([]token.ActiveServiceAccount, error) {
+	cutoff := s.activeCutoff(time.Now()).UTC().Format(time.RFC3339)
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`SELECT DISTINCT username, namespace FROM tokens WHERE expiration_date > %s ORDER BY username, namespace`,
+		s.placeholder(1))
+
+	rows, err := s.db.QueryContext(ctx, query, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts with active tokens: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []token.ActiveServiceAccount{}
+	for rows.Next() {
+		var account token.ActiveServiceAccount
+		var namespace sql.NullString
+		if err := rows.Scan(&account.Username, &namespace); err != nil {
+			return nil, err
+		}
+		account.Namespace = namespace.String
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (s *SQLStore) ScheduleRevocation(ctx context.Context, username string, groups []string, dueAt time.Time) error {
+	encodedGroups, err := json.Marshal(groups)
+	if err != nil {
+		return fmt.Errorf("failed to encode groups: %w", err)
+	}
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`INSERT INTO pending_revocations (username, user_groups, due_at) VALUES (%s, %s, %s)
+		ON CONFLICT (username) DO UPDATE SET user_groups = excluded.user_groups, due_at = excluded.due_at`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3))
+
+	if _, err := s.db.ExecContext(ctx, query, username, string(encodedGroups), dueAt.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to schedule revocation: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) DueRevocations(ctx context.Context, now time.Time) ([]PendingRevocation, error) {
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`SELECT username, user_groups, due_at FROM pending_revocations WHERE due_at <= %s ORDER BY due_at, username`,
+		s.placeholder(1))
+
+	rows, err := s.db.QueryContext(ctx, query, now.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending revocations: %w", err)
+	}
+	defer rows.Close()
+
+	revocations := []PendingRevocation{}
+	for rows.Next() {
+		revocation, err := scanRevocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		revocations = append(revocations, *revocation)
+	}
+
+	return revocations, rows.Err()
+}
+
+func (s *SQLStore) GetRevocation(ctx context.Context, username string) (*PendingRevocation, error) {
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`SELECT username, user_groups, due_at FROM pending_revocations WHERE username = %s`,
+		s.placeholder(1))
+
+	revocation, err := scanRevocation(s.db.QueryRowContext(ctx, query, username))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending revocation: %w", err)
+	}
+	return revocation, nil
+}
+
+// scanRevocation scans a pending revocation from a row of pending_revocations.
+func scanRevocation(row interface{ Scan(dest ...any) error }) (*PendingRevocation, error) {
+	var revocation PendingRevocation
+	var groups sql.NullString
+	var dueAt string
+	if err := row.Scan(&revocation.Username, &groups, &dueAt); err != nil {
+		return nil, err
+	}
+
+	if groups.String != "" {
+		if err := json.Unmarshal([]byte(groups.String), &revocation.Groups); err != nil {
+			return nil, fmt.Errorf("failed to decode groups of pending revocation for %s: %w", revocation.Username, err)
+		}
+	}
+	var err error
+	if revocation.DueAt, err = time.Parse(time.RFC3339, dueAt); err != nil {
+		return nil, fmt.Errorf("failed to parse due date of pending revocation for %s: %w", revocation.Username, err)
+	}
+	return &revocation, nil
+}
+
+func (s *SQLStore) CompleteRevocation(ctx context.Context, username string, dueAt time.Time) error {
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`DELETE FROM pending_revocations WHERE username = %s AND due_at <= %s`,
+		s.placeholder(1), s.placeholder(2))
+
+	if _, err := s.db.ExecContext(ctx, query, username, dueAt.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to complete revocation: %w", err)
+	}
+	return nil
+}
+
 func (s *SQLStore) List(ctx context.Context, username string) ([]ApiKeyMetadata, error) {
+	tokens := []ApiKeyMetadata{}
+	err := s.each(ctx, username, ListFilter{}, nil, 0, 0, func(t ApiKeyMetadata) error {
+		tokens = append(tokens, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *SQLStore) ListPage(ctx context.Context, username string, filter ListFilter, offset, limit int) ([]ApiKeyMetadata, error) {
+	tokens := []ApiKeyMetadata{}
+	err := s.each(ctx, username, filter, nil, offset, limit, func(t ApiKeyMetadata) error {
+		tokens = append(tokens, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *SQLStore) ListAfter(ctx context.Context, username string, after *KeysetCursor, limit int) ([]ApiKeyMetadata, error) {
+	tokens := []ApiKeyMetadata{}
+	err := s.each(ctx, username, ListFilter{}, after, 0, limit, func(t ApiKeyMetadata) error {
+		tokens = append(tokens, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// each calls fn for the tokens of a user matching the filter, newest first, starting after the keyset cursor when set
+// and skipping the first offset ones. A limit of 0 iterates over all remaining tokens.
+func (s *SQLStore) each(ctx context.Context, username string, filter ListFilter, after *KeysetCursor, offset, limit int, fn func(ApiKeyMetadata) error) error {
+	cutoff := s.activeCutoff(time.Now())
+
+	args := []any{username}
+	conditions := []string{"username = " + s.placeholder(1)}
+	switch filter.Status {
+	case TokenStatusActive:
+		args = append(args, cutoff.UTC().Format(time.RFC3339))
+		conditions = append(conditions, "expiration_date > "+s.placeholder(len(args)))
+	case TokenStatusExpired:
+		args = append(args, cutoff.UTC().Format(time.RFC3339))
+		conditions = append(conditions, "expiration_date <= "+s.placeholder(len(args)))
+	}
+	if filter.NameContains != "" {
+		args = append(args, "%"+escapeLike(strings.ToLower(filter.NameContains))+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(name) LIKE %s ESCAPE '\'`, s.placeholder(len(args))))
+	}
+	if after != nil {
+		args = append(args, after.CreationDate, s.storedID(after.ID))
+		conditions = append(conditions, fmt.Sprintf("(creation_date, id) < (%s, %s)", s.placeholder(len(args)-1), s.placeholder(len(args))))
+	}
+
+	page := ""
+	if limit > 0 {
+		args = append(args, limit, offset)
+		page = fmt.Sprintf("LIMIT %s OFFSET %s", s.placeholder(len(args)-1), s.placeholder(len(args)))
+	}
+
 	//nolint:gosec // G201: Safe - using placeholder indices, not user input
 	query := fmt.Sprintf(`
-	SELECT id, name, COALESCE(description, ''), creation_date, expiration_date
+	SELECT id, name, COALESCE(description, ''), creation_date, expiration_date, COALESCE(rotated_from, ''), COALESCE(models, ''),
+		COALESCE(metadata, ''), COALESCE(source, '')
 	FROM tokens 
-	WHERE username = %s
-	ORDER BY creation_date DESC
-	`, s.placeholder(1))
+	WHERE %s
+	ORDER BY creation_date DESC, id DESC
+	%s
+	`, strings.Join(conditions, " AND "), page)
 
-	rows, err := s.db.QueryContext(ctx, query, username)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	now := time.Now()
-	tokens := []ApiKeyMetadata{}
-
 	for rows.Next() {
 		var t ApiKeyMetadata
-		var creationStr, expirationStr string
-		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &creationStr, &expirationStr); err != nil {
-			return nil, err
+		var creationStr, expirationStr, modelsStr, metadataStr string
+		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &creationStr, &expirationStr, &t.RotatedFrom, &modelsStr, &metadataStr,
+			&t.Source); err != nil {
+			return err
+		}
+		t.ID = s.publicID(t.ID)
+		t.Username = username
+		if t.Models, err = decodeModels(modelsStr); err != nil {
+			return fmt.Errorf("invalid models for token %s: %w", t.ID, err)
+		}
+		if t.Metadata, err = decodeMetadata(metadataStr); err != nil {
+			return fmt.Errorf("invalid metadata for token %s: %w", t.ID, err)
 		}
 
 		t.CreationDate = creationStr
 		t.ExpirationDate = expirationStr
-		t.Status = computeTokenStatus(expirationStr, now)
+		t.Status = computeTokenStatus(expirationStr, cutoff)
 
-		tokens = append(tokens, t)
+		if err := fn(t); err != nil {
+			return err
+		}
 	}
 
-	if err := rows.Err(); err != nil {
+	return rows.Err()
+}
+
+func (s *SQLStore) ListByIDPrefix(ctx context.Context, prefix string, offset, limit int) ([]ApiKeyMetadata, error) {
+	cutoff := s.activeCutoff(time.Now())
+
+	// Like idMatch, tokens of this issuer match by their prefixed ID and legacy tokens, stored without any prefix,
+	// by the ID itself. Tokens stored by other issuers never match.
+	args := []any{escapeLike(s.storedID(prefix)) + "%", s.idPrefix}
+	condition := fmt.Sprintf(`(id LIKE %s ESCAPE '\' AND COALESCE(id_prefix, '') = %s)`, s.placeholder(1), s.placeholder(2))
+	if s.idPrefix != "" {
+		args = append(args, escapeLike(prefix)+"%")
+		condition += fmt.Sprintf(` OR (id LIKE %s ESCAPE '\' AND COALESCE(id_prefix, '') = '')`, s.placeholder(3))
+	}
+	args = append(args, limit, offset)
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`
+	SELECT id, username, name, COALESCE(description, ''), creation_date, expiration_date, COALESCE(rotated_from, ''),
+		COALESCE(models, ''), COALESCE(metadata, ''), COALESCE(source, '')
+	FROM tokens
+	WHERE %s
+	ORDER BY id
+	LIMIT %s OFFSET %s
+	`, condition, s.placeholder(len(args)-1), s.placeholder(len(args)))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return tokens, nil
+	tokens := []ApiKeyMetadata{}
+	for rows.Next() {
+		var t ApiKeyMetadata
+		var modelsStr, metadataStr string
+		if err := rows.Scan(&t.ID, &t.Username, &t.Name, &t.Description, &t.CreationDate, &t.ExpirationDate, &t.RotatedFrom,
+			&modelsStr, &metadataStr, &t.Source); err != nil {
+			return nil, err
+		}
+		t.ID = s.publicID(t.ID)
+		if t.Models, err = decodeModels(modelsStr); err != nil {
+			return nil, fmt.Errorf("invalid models for token %s: %w", t.ID, err)
+		}
+		if t.Metadata, err = decodeMetadata(metadataStr); err != nil {
+			return nil, fmt.Errorf("invalid metadata for token %s: %w", t.ID, err)
+		}
+		t.Status = computeTokenStatus(t.ExpirationDate, cutoff)
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
 }
 
 func (s *SQLStore) Get(ctx context.Context, jti string) (*ApiKeyMetadata, error) {
+	match, args := s.idMatch(jti, 1)
 	//nolint:gosec // G201: Safe - using placeholder indices, not user input
 	query := fmt.Sprintf(`
-	SELECT id, name, COALESCE(description, ''), creation_date, expiration_date
+	SELECT id, username, name, COALESCE(description, ''), creation_date, expiration_date, COALESCE(rotated_from, ''), COALESCE(token_hash, ''),
+		COALESCE(models, ''), COALESCE(metadata, ''), COALESCE(source, ''), COALESCE(renewed_at, '')
 	FROM tokens 
-	WHERE id = %s
-	`, s.placeholder(1))
+	WHERE %s OR (token_jti = %s AND COALESCE(id_prefix, '') IN (%s, ''))
+	ORDER BY CASE WHEN id = %s THEN 0 ELSE 1 END
+	LIMIT 1
+	`, match, s.placeholder(4), s.placeholder(5), s.placeholder(6))
 
-	row := s.db.QueryRowContext(ctx, query, jti)
+	// Tokens stored with the prefix take precedence over legacy ones stored without it.
+	args = append(args, jti, s.idPrefix, s.storedID(jti))
+	row := s.db.QueryRowContext(ctx, query, args...)
 
 	var t ApiKeyMetadata
-	var creationStr, expirationStr string
-	if err := row.Scan(&t.ID, &t.Name, &t.Description, &creationStr, &expirationStr); err != nil {
+	var creationStr, expirationStr, modelsStr, metadataStr string
+	if err := row.Scan(&t.ID, &t.Username, &t.Name, &t.Description, &creationStr, &expirationStr, &t.RotatedFrom, &t.TokenHash, &modelsStr, &metadataStr,
+		&t.Source, &t.RenewedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTokenNotFound
 		}
 		return nil, err
 	}
+	t.ID = s.publicID(t.ID)
+
+	models, err := decodeModels(modelsStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid models for token %s: %w", t.ID, err)
+	}
+	t.Models = models
+
+	metadata, err := decodeMetadata(metadataStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata for token %s: %w", t.ID, err)
+	}
+	t.Metadata = metadata
 
 	t.CreationDate = creationStr
 	t.ExpirationDate = expirationStr
-	t.Status = computeTokenStatus(expirationStr, time.Now())
+	t.Status = computeTokenStatus(expirationStr, s.activeCutoff(time.Now()))
 
 	return &t, nil
 }
 
-func computeTokenStatus(expirationStr string, now time.Time) string {
+func (s *SQLStore) GetActiveByName(ctx context.Context, username, name string) (*ApiKeyMetadata, error) {
+	cutoff := s.activeCutoff(time.Now()).UTC()
+
+	//nolint:gosec // G201: Safe - using placeholder indices, not user input
+	query := fmt.Sprintf(`
+	SELECT id, COALESCE(description, ''), creation_date, expiration_date, COALESCE(rotated_from, ''), COALESCE(token_hash, ''),
+		COALESCE(models, ''), COALESCE(metadata, ''), COALESCE(source, '')
+	FROM tokens 
+	WHERE username = %s AND name = %s AND expiration_date > %s
+	ORDER BY creation_date DESC
+	LIMIT 1
+	`, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+
+	row := s.db.QueryRowContext(ctx, query, username, name, cutoff.Format(time.RFC3339))
+
+	t := ApiKeyMetadata{Username: username, Name: name}
+	var creationStr, expirationStr, modelsStr, metadataStr string
+	if err := row.Scan(&t.ID, &t.Description, &creationStr, &expirationStr, &t.RotatedFrom, &t.TokenHash, &modelsStr, &metadataStr, &t.Source); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	t.ID = s.publicID(t.ID)
+
+	models, err := decodeModels(modelsStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid models for token %s: %w", t.ID, err)
+	}
+	t.Models = models
+
+	metadata, err := decodeMetadata(metadataStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata for token %s: %w", t.ID, err)
+	}
+	t.Metadata = metadata
+
+	t.CreationDate = creationStr
+	t.ExpirationDate = expirationStr
+	t.Status = computeTokenStatus(expirationStr, cutoff)
+
+	return &t, nil
+}
+
+// encodeModels encodes the model scope of a token as a JSON array, or NULL when the token is not scoped.
+func encodeModels(models []string) (sql.NullString, error) {
+	if len(models) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	encoded, err := json.Marshal(models)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode models: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+// decodeModels decodes a model scope stored by encodeModels.
+func decodeModels(encoded string) ([]string, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	var models []string
+	if err := json.Unmarshal([]byte(encoded), &models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// encodeMetadata encodes the key/value pairs attached to a token as a JSON object, or NULL when there are none.
+func encodeMetadata(metadata map[string]string) (sql.NullString, error) {
+	if len(metadata) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+// decodeMetadata decodes key/value pairs stored by encodeMetadata.
+func decodeMetadata(encoded string) (map[string]string, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(encoded), &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// likeEscaper escapes the LIKE wildcards, so that a pattern matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// hashToken returns the hex-encoded SHA-256 digest of the token, so that tokens can be matched without being stored.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// computeTokenStatus reports a token as expired when its expiration date lies before the cutoff, see activeCutoff.
+func computeTokenStatus(expirationStr string, cutoff time.Time) string {
 	expirationDate, err := time.Parse(time.RFC3339, expirationStr)
-	if err != nil || now.After(expirationDate) {
+	if err != nil || cutoff.After(expirationDate) {
 		return TokenStatusExpired
 	}
 	return TokenStatusActive
@@ -2,6 +2,7 @@ package api_keys_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ func createTestStore(t *testing.T) api_keys.MetadataStore {
 	t.Helper()
 	ctx := context.Background()
 	testLogger := logger.Development()
-	store, err := api_keys.NewSQLiteStore(ctx, testLogger, ":memory:")
+	store, err := api_keys.NewSQLiteStore(ctx, testLogger, ":memory:", api_keys.StoreOptions{})
 	require.NoError(t, err, "failed to create test store")
 	return store
 }
@@ -126,6 +127,945 @@ func TestStore(t *testing.T) {
 	})
 }
 
+func TestStoreListPage(t *testing.T) {
+	ctx := t.Context()
+
+	store := createTestStore(t)
+	defer store.Close()
+
+	for _, jti := range []string{"jti-a", "jti-b", "jti-c"} {
+		require.NoError(t, store.Add(ctx, "paging-user", &api_keys.APIKey{
+			Token: token.Token{JTI: jti, ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			Name:  jti,
+		}))
+	}
+
+	all, err := store.List(ctx, "paging-user")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	first, err := store.ListPage(ctx, "paging-user", api_keys.ListFilter{}, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, all[:2], first)
+
+	rest, err := store.ListPage(ctx, "paging-user", api_keys.ListFilter{}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, all[2:], rest)
+
+	beyond, err := store.ListPage(ctx, "paging-user", api_keys.ListFilter{}, 3, 2)
+	require.NoError(t, err)
+	assert.Empty(t, beyond)
+}
+
+func TestStoreListAfter(t *testing.T) {
+	ctx := t.Context()
+
+	store := createTestStore(t)
+	defer store.Close()
+
+	// Keys created in the same second are ordered by ID.
+	issuedAt := time.Now().Add(-time.Hour)
+	for i, jti := range []string{"jti-a", "jti-b", "jti-c", "jti-d", "jti-e"} {
+		require.NoError(t, store.Add(ctx, "keyset-user", &api_keys.APIKey{
+			Token: token.Token{JTI: jti, IssuedAt: issuedAt.Add(time.Duration(i/2) * time.Minute).Unix(), ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			Name:  jti,
+		}))
+	}
+	require.NoError(t, store.Add(ctx, "other-user", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-other", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		Name:  "other",
+	}))
+
+	all, err := store.List(ctx, "keyset-user")
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	var listed []api_keys.ApiKeyMetadata
+	var after *api_keys.KeysetCursor
+	for range 4 {
+		page, err := store.ListAfter(ctx, "keyset-user", after, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		listed = append(listed, page...)
+
+		last := page[len(page)-1]
+		after = &api_keys.KeysetCursor{CreationDate: last.CreationDate, ID: last.ID}
+	}
+
+	assert.Equal(t, all, listed, "every key must be listed exactly once, in the order of List")
+	assert.Equal(t, "jti-e", listed[0].ID)
+}
+
+func TestStoreListPageFilter(t *testing.T) {
+	ctx := t.Context()
+
+	store := createTestStore(t)
+	defer store.Close()
+
+	for _, apiKey := range []*api_keys.APIKey{
+		{Token: token.Token{JTI: "jti-prod-search", ExpiresAt: time.Now().Add(time.Hour).Unix()}, Name: "prod-search"},
+		{Token: token.Token{JTI: "jti-prod-ads", ExpiresAt: time.Now().Add(-time.Hour).Unix()}, Name: "Prod-Ads"},
+		{Token: token.Token{JTI: "jti-staging", ExpiresAt: time.Now().Add(time.Hour).Unix()}, Name: "staging"},
+		{Token: token.Token{JTI: "jti-wildcard", ExpiresAt: time.Now().Add(time.Hour).Unix()}, Name: "100%_prod"},
+	} {
+		require.NoError(t, store.Add(ctx, "filter-user", apiKey))
+	}
+
+	ids := func(t *testing.T, filter api_keys.ListFilter) []string {
+		t.Helper()
+
+		tokens, err := store.ListPage(ctx, "filter-user", filter, 0, 10)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(tokens))
+		for _, tok := range tokens {
+			ids = append(ids, tok.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		filter   api_keys.ListFilter
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			expected: []string{"jti-prod-ads", "jti-prod-search", "jti-staging", "jti-wildcard"},
+		},
+		{
+			name:     "active",
+			filter:   api_keys.ListFilter{Status: api_keys.TokenStatusActive},
+			expected: []string{"jti-prod-search", "jti-staging", "jti-wildcard"},
+		},
+		{
+			name:     "expired",
+			filter:   api_keys.ListFilter{Status: api_keys.TokenStatusExpired},
+			expected: []string{"jti-prod-ads"},
+		},
+		{
+			name:     "name contains, ignoring case",
+			filter:   api_keys.ListFilter{NameContains: "PROD"},
+			expected: []string{"jti-prod-ads", "jti-prod-search", "jti-wildcard"},
+		},
+		{
+			name:     "name contains wildcards matched literally",
+			filter:   api_keys.ListFilter{NameContains: "%_"},
+			expected: []string{"jti-wildcard"},
+		},
+		{
+			name:     "status and name",
+			filter:   api_keys.ListFilter{Status: api_keys.TokenStatusActive, NameContains: "prod-"},
+			expected: []string{"jti-prod-search"},
+		},
+		{
+			name:     "no match",
+			filter:   api_keys.ListFilter{Status: api_keys.TokenStatusExpired, NameContains: "staging"},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.expected, ids(t, tt.filter))
+		})
+	}
+}
+
+func TestStoreInvalidate(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	for _, apiKey := range []*api_keys.APIKey{
+		{Token: token.Token{JTI: "jti-old", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()}, Name: "rotated"},
+		{Token: token.Token{JTI: "jti-new", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()}, Name: "rotated", RotatedFrom: "jti-old"},
+	} {
+		require.NoError(t, store.Add(ctx, "user1", apiKey))
+	}
+
+	t.Run("MarksOnlyGivenTokenExpired", func(t *testing.T) {
+		require.NoError(t, store.Invalidate(ctx, "jti-old"))
+
+		oldKey, err := store.Get(ctx, "jti-old")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusExpired, oldKey.Status)
+
+		newKey, err := store.Get(ctx, "jti-new")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusActive, newKey.Status)
+		assert.Equal(t, "jti-old", newKey.RotatedFrom)
+		assert.Equal(t, "user1", newKey.Username)
+	})
+
+	t.Run("AlreadyExpired", func(t *testing.T) {
+		require.NoError(t, store.Invalidate(ctx, "jti-old"))
+	})
+
+	t.Run("TokenNotFound", func(t *testing.T) {
+		err := store.Invalidate(ctx, "nonexistent-jti")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+}
+
+func TestStoreExpireTokensIssuedBefore(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	for _, k := range []struct {
+		username string
+		jti      string
+		issuedAt time.Time
+	}{
+		{"user1", "jti-before-1", cutoff.Add(-time.Hour)},
+		{"user2", "jti-before-2", cutoff.Add(-time.Minute)},
+		{"user1", "jti-after-1", cutoff.Add(time.Minute)},
+		{"user2", "jti-after-2", time.Now()},
+	} {
+		require.NoError(t, store.Add(ctx, k.username, &api_keys.APIKey{
+			Token: token.Token{JTI: k.jti, IssuedAt: k.issuedAt.Unix(), ExpiresAt: expiresAt},
+			Name:  k.jti,
+		}))
+	}
+
+	count, err := store.ExpireTokensIssuedBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	for jti, status := range map[string]string{
+		"jti-before-1": api_keys.TokenStatusExpired,
+		"jti-before-2": api_keys.TokenStatusExpired,
+		"jti-after-1":  api_keys.TokenStatusActive,
+		"jti-after-2":  api_keys.TokenStatusActive,
+	} {
+		key, err := store.Get(ctx, jti)
+		require.NoError(t, err)
+		assert.Equal(t, status, key.Status, jti)
+	}
+
+	// Tokens that are already expired are not counted again.
+	count, err = store.ExpireTokensIssuedBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestStoreExpireTokensIssuedBefore_IDPrefix(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+	issuedAt := time.Now().Add(-time.Hour).Unix()
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	stores := map[string]*api_keys.SQLStore{}
+	for _, prefix := range []string{"", "cluster-a:", "cluster-b:"} {
+		store, err := api_keys.NewSQLiteStore(ctx, logger.Development(), dbPath, api_keys.StoreOptions{IDPrefix: prefix})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		stores[prefix] = store
+
+		require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+			Token: token.Token{JTI: "jti", IssuedAt: issuedAt, ExpiresAt: expiresAt},
+			Name:  "key-" + prefix,
+		}))
+	}
+
+	status := func(t *testing.T, prefix string) string {
+		t.Helper()
+
+		key, err := stores[prefix].Get(ctx, "jti")
+		require.NoError(t, err)
+		return key.Status
+	}
+
+	count, err := stores["cluster-a:"].ExpireTokensIssuedBefore(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "only the token of cluster-a must be expired")
+	assert.Equal(t, api_keys.TokenStatusExpired, status(t, "cluster-a:"))
+	assert.Equal(t, api_keys.TokenStatusActive, status(t, "cluster-b:"), "tokens of other issuers must be left untouched")
+	assert.Equal(t, api_keys.TokenStatusActive, status(t, ""), "legacy tokens must be left untouched by prefixed stores")
+
+	count, err = stores[""].ExpireTokensIssuedBefore(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "only the legacy token must be expired")
+	assert.Equal(t, api_keys.TokenStatusExpired, status(t, ""))
+	assert.Equal(t, api_keys.TokenStatusActive, status(t, "cluster-b:"))
+}
+
+func TestStoreListByIDPrefix(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	for jti, username := range map[string]string{
+		"3f2a9c10-aaaa": "user1",
+		"3f2a9c10-bbbb": "user2",
+		"3f2a1111-cccc": "user1",
+		"9d3f2a9c-dddd": "user2",
+		"3f2a_c10-eeee": "user3",
+	} {
+		require.NoError(t, store.Add(ctx, username, &api_keys.APIKey{
+			Token: token.Token{JTI: jti, ExpiresAt: expiresAt},
+			Name:  "key-" + jti,
+		}))
+	}
+
+	ids := func(tokens []api_keys.ApiKeyMetadata) []string {
+		result := make([]string, 0, len(tokens))
+		for _, t := range tokens {
+			result = append(result, t.ID)
+		}
+		return result
+	}
+
+	tokens, err := store.ListByIDPrefix(ctx, "3f2a9c", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3f2a9c10-aaaa", "3f2a9c10-bbbb"}, ids(tokens), "tokens of all users whose ID starts with the prefix")
+	assert.Equal(t, "user1", tokens[0].Username)
+	assert.Equal(t, "user2", tokens[1].Username)
+	assert.Equal(t, api_keys.TokenStatusActive, tokens[0].Status)
+
+	tokens, err = store.ListByIDPrefix(ctx, "3f2a", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3f2a9c10-aaaa", "3f2a9c10-bbbb"}, ids(tokens), "pages are ordered by ID")
+
+	tokens, err = store.ListByIDPrefix(ctx, "3f2a_", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3f2a_c10-eeee"}, ids(tokens), "wildcards in the prefix match literally")
+
+	tokens, err = store.ListByIDPrefix(ctx, "ffff", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func TestStoreCountActive(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	for _, jti := range []string{"jti-1", "jti-2", "jti-3"} {
+		require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+			Token: token.Token{JTI: jti, ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			Name:  jti,
+		}))
+	}
+	require.NoError(t, store.Add(ctx, "user2", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-4", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		Name:  "jti-4",
+	}))
+	require.NoError(t, store.Invalidate(ctx, "jti-3"))
+
+	count, err := store.CountActive(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "revoked tokens and tokens of other users are not counted")
+
+	count, err = store.CountActive(ctx, "user3")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestStoreImport(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	imported := func(jti, username string) api_keys.ImportedAPIKey {
+		return api_keys.ImportedAPIKey{
+			APIKey: api_keys.APIKey{
+				Token: token.Token{
+					JTI:       jti,
+					IssuedAt:  time.Now().Add(-time.Hour).Unix(),
+					ExpiresAt: time.Now().Add(time.Hour).Unix(),
+				},
+				Name: "imported",
+			},
+			Username: username,
+		}
+	}
+
+	require.NoError(t, store.Import(ctx, []api_keys.ImportedAPIKey{imported("jti-1", "user1"), imported("jti-2", "user2")}))
+
+	for username, jti := range map[string]string{"user1": "jti-1", "user2": "jti-2"} {
+		meta, err := store.Get(ctx, jti)
+		require.NoError(t, err)
+		assert.Equal(t, username, meta.Username)
+		assert.Equal(t, api_keys.TokenStatusActive, meta.Status)
+	}
+
+	err := store.Import(ctx, []api_keys.ImportedAPIKey{imported("jti-3", "user1"), imported("jti-1", "user1")})
+	require.ErrorIs(t, err, api_keys.ErrDuplicateToken)
+
+	_, err = store.Get(ctx, "jti-3")
+	require.ErrorIs(t, err, api_keys.ErrTokenNotFound, "the batch must be rolled back")
+}
+
+func TestStoreActiveServiceAccounts(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	for username, jti := range map[string]string{"user1": "jti-1", "user2": "jti-2", "revoked-user": "jti-3"} {
+		require.NoError(t, store.Add(ctx, username, &api_keys.APIKey{
+			Token: token.Token{JTI: jti, ExpiresAt: time.Now().Add(1 * time.Hour).Unix(), Namespace: "tier-free"},
+			Name:  "key",
+		}))
+	}
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-4", ExpiresAt: time.Now().Add(2 * time.Hour).Unix(), Namespace: "tier-free"},
+		Name:  "other-key",
+	}))
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-5", ExpiresAt: time.Now().Add(2 * time.Hour).Unix(), Namespace: "tier-premium"},
+		Name:  "premium-key",
+	}))
+	require.NoError(t, store.Add(ctx, "legacy-user", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-6", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:  "key",
+	}))
+	require.NoError(t, store.InvalidateAll(ctx, "revoked-user"))
+
+	accounts, err := store.ActiveServiceAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []token.ActiveServiceAccount{
+		{Username: "legacy-user"},
+		{Username: "user1", Namespace: "tier-free"},
+		{Username: "user1", Namespace: "tier-premium"},
+		{Username: "user2", Namespace: "tier-free"},
+	}, accounts)
+
+	t.Run("renewed keys move to the namespace of their new token", func(t *testing.T) {
+		require.NoError(t, store.Renew(ctx, "jti-2", &token.Token{
+			Token:     "renewed",
+			JTI:       "jti-2-renewed",
+			ExpiresAt: time.Now().Add(3 * time.Hour).Unix(),
+			Namespace: "tier-premium",
+		}))
+
+		accounts, err := store.ActiveServiceAccounts(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, accounts, token.ActiveServiceAccount{Username: "user2", Namespace: "tier-premium"})
+		assert.NotContains(t, accounts, token.ActiveServiceAccount{Username: "user2", Namespace: "tier-free"})
+	})
+}
+
+func TestStoreReplace(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	expiresAt := time.Now().Add(1 * time.Hour).Unix()
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-old", ExpiresAt: expiresAt},
+		Name:  "rotated",
+	}))
+
+	t.Run("AddsNewAndExpiresOld", func(t *testing.T) {
+		require.NoError(t, store.Replace(ctx, "user1", "jti-old", &api_keys.APIKey{
+			Token:       token.Token{JTI: "jti-new", ExpiresAt: expiresAt},
+			Name:        "rotated",
+			RotatedFrom: "jti-old",
+		}))
+
+		oldKey, err := store.Get(ctx, "jti-old")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusExpired, oldKey.Status)
+
+		newKey, err := store.Get(ctx, "jti-new")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusActive, newKey.Status)
+		assert.Equal(t, "jti-old", newKey.RotatedFrom)
+	})
+
+	t.Run("OldKeyNotActive", func(t *testing.T) {
+		err := store.Replace(ctx, "user1", "jti-old", &api_keys.APIKey{
+			Token: token.Token{JTI: "jti-concurrent", ExpiresAt: expiresAt},
+			Name:  "rotated",
+		})
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+
+		_, err = store.Get(ctx, "jti-concurrent")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound, "the new key must not be stored without expiring the old one")
+	})
+
+	t.Run("DuplicateNewKey", func(t *testing.T) {
+		err := store.Replace(ctx, "user1", "jti-new", &api_keys.APIKey{
+			Token: token.Token{JTI: "jti-old", ExpiresAt: expiresAt},
+			Name:  "rotated",
+		})
+		require.ErrorIs(t, err, api_keys.ErrDuplicateToken)
+
+		newKey, err := store.Get(ctx, "jti-new")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusActive, newKey.Status, "the old key must not be expired without storing the new one")
+	})
+}
+
+func TestStoreRenew(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{Token: "first-token", JTI: "jti-key", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:  "renewed",
+	}))
+
+	expiresAt := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	require.NoError(t, store.Renew(ctx, "jti-key", &token.Token{Token: "second-token", JTI: "jti-renewed", ExpiresAt: expiresAt.Unix()}))
+
+	t.Run("KeepsID", func(t *testing.T) {
+		key, err := store.Get(ctx, "jti-key")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-key", key.ID)
+		assert.Equal(t, "renewed", key.Name)
+		assert.Equal(t, expiresAt.UTC().Format(time.RFC3339), key.ExpirationDate)
+		assert.Equal(t, api_keys.TokenStatusActive, key.Status)
+	})
+
+	t.Run("FoundByRenewedJTI", func(t *testing.T) {
+		key, err := store.Get(ctx, "jti-renewed")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-key", key.ID)
+	})
+
+	t.Run("ExpiredKey", func(t *testing.T) {
+		require.NoError(t, store.Invalidate(ctx, "jti-key"))
+
+		err := store.Renew(ctx, "jti-key", &token.Token{Token: "third-token", JTI: "jti-third", ExpiresAt: expiresAt.Unix()})
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+
+	t.Run("TokenNotFound", func(t *testing.T) {
+		err := store.Renew(ctx, "nonexistent-jti", &token.Token{Token: "token", JTI: "jti-other", ExpiresAt: expiresAt.Unix()})
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+}
+
+func TestStoreGetActiveByName(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	for _, apiKey := range []*api_keys.APIKey{
+		{Token: token.Token{JTI: "jti-prod", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()}, Name: "prod"},
+		{Token: token.Token{JTI: "jti-staging", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()}, Name: "staging"},
+	} {
+		require.NoError(t, store.Add(ctx, "user1", apiKey))
+	}
+
+	t.Run("ActiveKey", func(t *testing.T) {
+		key, err := store.GetActiveByName(ctx, "user1", "prod")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-prod", key.ID)
+		assert.Equal(t, api_keys.TokenStatusActive, key.Status)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		_, err := store.GetActiveByName(ctx, "user2", "prod")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+
+	t.Run("ExpiredKey", func(t *testing.T) {
+		require.NoError(t, store.Invalidate(ctx, "jti-staging"))
+
+		_, err := store.GetActiveByName(ctx, "user1", "staging")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+}
+
+func TestStoreExpirationGrace(t *testing.T) {
+	ctx := t.Context()
+
+	newStore := func(t *testing.T, grace time.Duration) *api_keys.SQLStore {
+		t.Helper()
+
+		store, err := api_keys.NewSQLiteStore(ctx, logger.Development(), ":memory:", api_keys.StoreOptions{ExpirationGrace: grace})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+
+		for _, apiKey := range []*api_keys.APIKey{
+			{Token: token.Token{JTI: "jti-just-expired", ExpiresAt: time.Now().Add(-10 * time.Second).Unix()}, Name: "just-expired"},
+			{Token: token.Token{JTI: "jti-long-expired", ExpiresAt: time.Now().Add(-time.Minute).Unix()}, Name: "long-expired"},
+		} {
+			require.NoError(t, store.Add(ctx, "user1", apiKey))
+		}
+		return store
+	}
+
+	statuses := func(t *testing.T, store *api_keys.SQLStore) map[string]string {
+		t.Helper()
+
+		tokens, err := store.List(ctx, "user1")
+		require.NoError(t, err)
+
+		result := make(map[string]string, len(tokens))
+		for _, tok := range tokens {
+			result[tok.ID] = tok.Status
+		}
+		return result
+	}
+
+	t.Run("WithoutGrace", func(t *testing.T) {
+		store := newStore(t, 0)
+
+		assert.Equal(t, map[string]string{
+			"jti-just-expired": api_keys.TokenStatusExpired,
+			"jti-long-expired": api_keys.TokenStatusExpired,
+		}, statuses(t, store))
+
+		_, err := store.GetActiveByName(ctx, "user1", "just-expired")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+
+	t.Run("WithinGrace", func(t *testing.T) {
+		store := newStore(t, 30*time.Second)
+
+		assert.Equal(t, map[string]string{
+			"jti-just-expired": api_keys.TokenStatusActive,
+			"jti-long-expired": api_keys.TokenStatusExpired,
+		}, statuses(t, store))
+
+		key, err := store.Get(ctx, "jti-just-expired")
+		require.NoError(t, err)
+		assert.Equal(t, api_keys.TokenStatusActive, key.Status)
+
+		_, err = store.GetActiveByName(ctx, "user1", "just-expired")
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidatedWithinGrace", func(t *testing.T) {
+		store := newStore(t, 30*time.Second)
+		require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+			Token: token.Token{JTI: "jti-revoked", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			Name:  "revoked",
+		}))
+
+		require.NoError(t, store.InvalidateAll(ctx, "user1"))
+
+		for id, status := range statuses(t, store) {
+			assert.Equal(t, api_keys.TokenStatusExpired, status, "%s must be reported as expired right after revocation", id)
+		}
+	})
+}
+
+func TestStoreIDPrefix(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	openStore := func(t *testing.T, prefix string) *api_keys.SQLStore {
+		t.Helper()
+
+		store, err := api_keys.NewSQLiteStore(ctx, logger.Development(), dbPath, api_keys.StoreOptions{IDPrefix: prefix})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+
+	// Stored before the prefix was configured.
+	legacy := openStore(t, "")
+	require.NoError(t, legacy.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-legacy", ExpiresAt: expiresAt},
+		Name:  "legacy",
+	}))
+	require.NoError(t, legacy.Close())
+
+	store := openStore(t, "cluster-a:")
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-new", ExpiresAt: expiresAt},
+		Name:  "new",
+	}))
+
+	t.Run("StoresPrefixedIDs", func(t *testing.T) {
+		unprefixed := openStore(t, "")
+
+		// The prefixed token is neither found by its ID, nor by its stored ID, by stores without the prefix.
+		for _, prefix := range []string{"jti-new", "cluster-a:"} {
+			tokens, err := unprefixed.ListByIDPrefix(ctx, prefix, 0, 10)
+			require.NoError(t, err)
+			assert.Empty(t, tokens, prefix)
+		}
+
+		_, err := unprefixed.Get(ctx, "jti-new")
+		require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+	})
+
+	t.Run("ReadsUnprefixedIDs", func(t *testing.T) {
+		tokens, err := store.List(ctx, "user1")
+		require.NoError(t, err)
+		ids := make([]string, 0, len(tokens))
+		for _, tok := range tokens {
+			ids = append(ids, tok.ID)
+		}
+		assert.ElementsMatch(t, []string{"jti-legacy", "jti-new"}, ids)
+
+		key, err := store.Get(ctx, "jti-new")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-new", key.ID)
+
+		key, err = store.GetActiveByName(ctx, "user1", "new")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-new", key.ID)
+	})
+
+	t.Run("ResolvesLegacyIDs", func(t *testing.T) {
+		key, err := store.Get(ctx, "jti-legacy")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-legacy", key.ID)
+		assert.Equal(t, "legacy", key.Name)
+	})
+
+	t.Run("SearchesBothByPrefix", func(t *testing.T) {
+		tokens, err := store.ListByIDPrefix(ctx, "jti-", 0, 10)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(tokens))
+		for _, tok := range tokens {
+			ids = append(ids, tok.ID)
+		}
+		assert.ElementsMatch(t, []string{"jti-legacy", "jti-new"}, ids)
+	})
+
+	t.Run("RenewsAndInvalidates", func(t *testing.T) {
+		require.NoError(t, store.Renew(ctx, "jti-new", &token.Token{Token: "renewed", JTI: "jti-renewed", ExpiresAt: expiresAt}))
+		key, err := store.Get(ctx, "jti-renewed")
+		require.NoError(t, err)
+		assert.Equal(t, "jti-new", key.ID)
+
+		require.NoError(t, store.Invalidate(ctx, "jti-new"))
+		require.NoError(t, store.Invalidate(ctx, "jti-legacy"))
+
+		for _, id := range []string{"jti-new", "jti-legacy"} {
+			key, err := store.Get(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, api_keys.TokenStatusExpired, key.Status, id)
+		}
+	})
+}
+
+func TestStoreIDPrefix_OtherIssuers(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "maas-api.db")
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	openStore := func(t *testing.T, prefix string) *api_keys.SQLStore {
+		t.Helper()
+
+		store, err := api_keys.NewSQLiteStore(ctx, logger.Development(), dbPath, api_keys.StoreOptions{IDPrefix: prefix})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+
+	clusterB := openStore(t, "cluster-b:")
+	require.NoError(t, clusterB.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-b", ExpiresAt: expiresAt},
+		Name:  "issued-by-b",
+	}))
+
+	// The stored ID of the token of cluster-b must not be mistaken for a legacy ID by other issuers.
+	for name, store := range map[string]*api_keys.SQLStore{
+		"Prefixed":   openStore(t, "cluster-a:"),
+		"Unprefixed": openStore(t, ""),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get(ctx, "cluster-b:jti-b")
+			require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+
+			err = store.Invalidate(ctx, "cluster-b:jti-b")
+			require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+
+			err = store.Renew(ctx, "cluster-b:jti-b", &token.Token{Token: "renewed", JTI: "jti-renewed", ExpiresAt: expiresAt})
+			require.ErrorIs(t, err, api_keys.ErrTokenNotFound)
+
+			tokens, err := store.ListByIDPrefix(ctx, "cluster-b:", 0, 10)
+			require.NoError(t, err)
+			assert.Empty(t, tokens, "the token of cluster-b must not be listed")
+		})
+	}
+
+	key, err := clusterB.Get(ctx, "jti-b")
+	require.NoError(t, err)
+	assert.Equal(t, api_keys.TokenStatusActive, key.Status, "the token of cluster-b must be left untouched")
+}
+
+func TestStoreModels(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	scope := []string{"gpt-3-turbo", "meta-llama/Llama-3.1-8B-Instruct"}
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token:  token.Token{JTI: "jti-scoped", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:   "scoped",
+		Models: scope,
+	}))
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-unscoped", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:  "unscoped",
+	}))
+
+	scoped, err := store.Get(ctx, "jti-scoped")
+	require.NoError(t, err)
+	assert.Equal(t, scope, scoped.Models)
+
+	unscoped, err := store.Get(ctx, "jti-unscoped")
+	require.NoError(t, err)
+	assert.Nil(t, unscoped.Models)
+
+	byName, err := store.GetActiveByName(ctx, "user1", "scoped")
+	require.NoError(t, err)
+	assert.Equal(t, scope, byName.Models)
+
+	tokens, err := store.List(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	for _, tok := range tokens {
+		if tok.ID == "jti-scoped" {
+			assert.Equal(t, scope, tok.Models)
+		} else {
+			assert.Nil(t, tok.Models)
+		}
+	}
+}
+
+func TestStoreMetadata(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	metadata := map[string]string{"env": "prod", "team": "search"}
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token:    token.Token{JTI: "jti-tagged", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:     "tagged",
+		Metadata: metadata,
+	}))
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-untagged", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:  "untagged",
+	}))
+
+	tagged, err := store.Get(ctx, "jti-tagged")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, tagged.Metadata)
+
+	untagged, err := store.Get(ctx, "jti-untagged")
+	require.NoError(t, err)
+	assert.Nil(t, untagged.Metadata)
+
+	byName, err := store.GetActiveByName(ctx, "user1", "tagged")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, byName.Metadata)
+
+	tokens, err := store.List(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	for _, tok := range tokens {
+		if tok.ID == "jti-tagged" {
+			assert.Equal(t, metadata, tok.Metadata)
+		} else {
+			assert.Nil(t, tok.Metadata)
+		}
+	}
+}
+
+func TestStoreSource(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token:  token.Token{JTI: "jti-ui", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:   "from-ui",
+		Source: "ui",
+	}))
+	require.NoError(t, store.Add(ctx, "user1", &api_keys.APIKey{
+		Token: token.Token{JTI: "jti-unknown", ExpiresAt: time.Now().Add(1 * time.Hour).Unix()},
+		Name:  "unknown",
+	}))
+
+	fromUI, err := store.Get(ctx, "jti-ui")
+	require.NoError(t, err)
+	assert.Equal(t, "ui", fromUI.Source)
+
+	byName, err := store.GetActiveByName(ctx, "user1", "from-ui")
+	require.NoError(t, err)
+	assert.Equal(t, "ui", byName.Source)
+
+	found, err := store.ListByIDPrefix(ctx, "jti-ui", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "ui", found[0].Source)
+
+	tokens, err := store.List(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	for _, tok := range tokens {
+		if tok.ID == "jti-ui" {
+			assert.Equal(t, "ui", tok.Source)
+		} else {
+			assert.Empty(t, tok.Source)
+		}
+	}
+}
+
+func TestStorePendingRevocations(t *testing.T) {
+	ctx := t.Context()
+	store := createTestStore(t)
+	defer store.Close()
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, store.ScheduleRevocation(ctx, "user1", []string{"team-a"}, now.Add(time.Hour)))
+	require.NoError(t, store.ScheduleRevocation(ctx, "user2", nil, now.Add(2*time.Hour)))
+
+	due, err := store.DueRevocations(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = store.DueRevocations(ctx, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "user1", due[0].Username)
+	assert.Equal(t, []string{"team-a"}, due[0].Groups)
+	assert.True(t, due[0].DueAt.Equal(now.Add(time.Hour)))
+	assert.Equal(t, "user2", due[1].Username)
+
+	t.Run("pending revocations are found by user", func(t *testing.T) {
+		revocation, err := store.GetRevocation(ctx, "user1")
+		require.NoError(t, err)
+		require.NotNil(t, revocation)
+		assert.Equal(t, []string{"team-a"}, revocation.Groups)
+		assert.True(t, revocation.DueAt.Equal(now.Add(time.Hour)))
+
+		revocation, err = store.GetRevocation(ctx, "user3")
+		require.NoError(t, err)
+		assert.Nil(t, revocation)
+	})
+
+	t.Run("rescheduled revocations are not completed early", func(t *testing.T) {
+		require.NoError(t, store.ScheduleRevocation(ctx, "user1", []string{"team-b"}, now.Add(4*time.Hour)))
+		require.NoError(t, store.CompleteRevocation(ctx, "user1", now.Add(time.Hour)))
+
+		due, err := store.DueRevocations(ctx, now.Add(4*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assert.Equal(t, "user2", due[0].Username)
+		assert.Equal(t, "user1", due[1].Username)
+		assert.Equal(t, []string{"team-b"}, due[1].Groups)
+	})
+
+	t.Run("completed revocations are no longer due", func(t *testing.T) {
+		require.NoError(t, store.CompleteRevocation(ctx, "user1", now.Add(4*time.Hour)))
+		require.NoError(t, store.CompleteRevocation(ctx, "user2", now.Add(2*time.Hour)))
+
+		due, err := store.DueRevocations(ctx, now.Add(4*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+}
+
 func TestStoreValidation(t *testing.T) {
 	ctx := t.Context()
 	store := createTestStore(t)
@@ -157,6 +1097,26 @@ func TestStoreValidation(t *testing.T) {
 		assert.ErrorIs(t, err, api_keys.ErrEmptyName)
 	})
 
+	t.Run("DuplicateJTI", func(t *testing.T) {
+		apiKey := &api_keys.APIKey{
+			Token: token.Token{
+				JTI:       "duplicate-jti",
+				ExpiresAt: time.Now().Add(1 * time.Hour).Unix(),
+			},
+			Name: "first",
+		}
+		require.NoError(t, store.Add(ctx, "user1", apiKey))
+
+		apiKey.Name = "second"
+		err := store.Add(ctx, "user2", apiKey)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, api_keys.ErrDuplicateToken)
+
+		tokens, err := store.List(ctx, "user2")
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+	})
+
 	t.Run("TokenNotFound", func(t *testing.T) {
 		_, err := store.Get(ctx, "nonexistent-jti")
 		require.Error(t, err)
@@ -169,7 +1129,7 @@ func TestSQLiteStore(t *testing.T) {
 	testLogger := logger.Development()
 
 	t.Run("InMemory", func(t *testing.T) {
-		store, err := api_keys.NewSQLiteStore(ctx, testLogger, ":memory:")
+		store, err := api_keys.NewSQLiteStore(ctx, testLogger, ":memory:", api_keys.StoreOptions{})
 		require.NoError(t, err)
 		defer store.Close()
 
@@ -180,7 +1140,7 @@ func TestSQLiteStore(t *testing.T) {
 
 	t.Run("EmptyPath", func(t *testing.T) {
 		// Empty path should default to in-memory
-		store, err := api_keys.NewSQLiteStore(ctx, testLogger, "")
+		store, err := api_keys.NewSQLiteStore(ctx, testLogger, "", api_keys.StoreOptions{})
 		require.NoError(t, err)
 		defer store.Close()
 
@@ -195,13 +1155,13 @@ func TestExternalStore(t *testing.T) {
 	testLogger := logger.Development()
 
 	t.Run("InvalidURL", func(t *testing.T) {
-		_, err := api_keys.NewExternalStore(ctx, testLogger, "mysql://localhost:3306/db")
+		_, err := api_keys.NewExternalStore(ctx, testLogger, "mysql://localhost:3306/db", api_keys.StoreOptions{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported external database URL")
 	})
 
 	t.Run("EmptyURL", func(t *testing.T) {
-		_, err := api_keys.NewExternalStore(ctx, testLogger, "")
+		_, err := api_keys.NewExternalStore(ctx, testLogger, "", api_keys.StoreOptions{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported external database URL")
 	})
@@ -9,15 +9,73 @@ type APIKey struct {
 
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	// RotatedFrom is the ID of the API key this key replaced, if it was created by rotation.
+	RotatedFrom string `json:"rotatedFrom,omitempty"`
+	// Models lists the models the key is intended for. Empty means the key is meant for all models.
+	Models []string `json:"models,omitempty"`
+	// Metadata holds arbitrary key/value pairs attached to the key by its owner, for their own bookkeeping.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Source is the client the key was created from, e.g. ui, cli or ci, see token.UserContext.Source.
+	Source string `json:"source,omitempty"`
+}
+
+// ImportedAPIKey is an API key issued outside of maas-api, e.g. by a previous deployment, whose metadata is imported
+// into the store. Its token is only set when known, so that introspection accepts it.
+type ImportedAPIKey struct {
+	APIKey
+
+	Username string
 }
 
 // ApiKeyMetadata represents metadata for a single API key (without the token itself).
 // Used for listing and retrieving API key metadata from the database.
 type ApiKeyMetadata struct {
 	ID             string `json:"id"`
+	Username       string `json:"-"`
 	Name           string `json:"name"`
 	Description    string `json:"description,omitempty"`
 	CreationDate   string `json:"creationDate"`
 	ExpirationDate string `json:"expirationDate"`
 	Status         string `json:"status"` // "active", "expired"
+	RotatedFrom    string `json:"rotatedFrom,omitempty"`
+	// Models lists the models the key is intended for, see APIKey.Models.
+	Models []string `json:"models,omitempty"`
+	// Metadata holds the key/value pairs attached to the key, see APIKey.Metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Source is the client the key was created from, see APIKey.Source.
+	Source string `json:"source,omitempty"`
+	// TokenHash is the SHA-256 digest of the issued token, empty for keys created before it was recorded.
+	TokenHash string `json:"-"`
+	// RenewedAt is the issue date of the token of an extended key, in RFC 3339 format. It is only read by Get,
+	// and is empty for keys that were never extended.
+	RenewedAt string `json:"-"`
+}
+
+// StreamedAPIKey is a line of the NDJSON stream of API keys. Cursor resumes the stream after the key.
+type StreamedAPIKey struct {
+	ApiKeyMetadata
+	Cursor string `json:"cursor"`
+}
+
+// ListResponse is a page of API key metadata, in the list envelope of the models endpoint.
+// NextCursor is set when HasMore is true, and must be passed as the cursor query parameter to fetch the next page.
+type ListResponse struct {
+	Object     string           `json:"object"`
+	Data       []ApiKeyMetadata `json:"data"`
+	HasMore    bool             `json:"has_more"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// TokenSearchResult is a token found by JTI prefix, along with the user it was issued to.
+type TokenSearchResult struct {
+	ApiKeyMetadata
+	Username string `json:"username"`
+}
+
+// TokenSearchResponse is a page of tokens found by JTI prefix, in the envelope of ListResponse.
+type TokenSearchResponse struct {
+	Object     string              `json:"object"`
+	Data       []TokenSearchResult `json:"data"`
+	HasMore    bool                `json:"has_more"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
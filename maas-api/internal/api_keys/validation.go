@@ -0,0 +1,208 @@
+package api_keys
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+const (
+	MaxNameLength        = 128
+	MaxDescriptionLength = 1024
+	MaxModels            = 32
+
+	MaxMetadataEntries     = 16
+	MaxMetadataKeyLength   = 63
+	MaxMetadataValueLength = 256
+
+	MinExpiration = 10 * time.Minute
+	MaxExpiration = 365 * 24 * time.Hour
+)
+
+// namePattern allows letters, digits, spaces, dots, underscores and dashes, starting with a letter or a digit.
+var namePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} ._-]*$`)
+
+// ValidationErrors maps the JSON field name of an invalid request field to the reason it was rejected.
+type ValidationErrors map[string]string
+
+// Validate checks the create request fields, returning nil when all of them are valid.
+// A nil Expiration is considered valid, as the handler applies the default before issuing the key.
+func (r *CreateRequest) Validate() ValidationErrors {
+	errs := ValidationErrors{}
+
+	if reason := validateName(r.Name); reason != "" {
+		errs["name"] = reason
+	}
+
+	if utf8.RuneCountInString(r.Description) > MaxDescriptionLength {
+		errs["description"] = fmt.Sprintf("must not exceed %d characters", MaxDescriptionLength)
+	}
+
+	if reason := validateModels(r.Models); reason != "" {
+		errs["models"] = reason
+	}
+
+	if reason := validateMetadata(r.Metadata); reason != "" {
+		errs["metadata"] = reason
+	}
+
+	if r.Expiration != nil {
+		if reason := validateExpiration(r.Expiration.Duration); reason != "" {
+			errs["expiration"] = reason
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate checks the extend request fields, returning nil when all of them are valid.
+func (r *ExtendRequest) Validate() ValidationErrors {
+	if r.Expiration == nil {
+		return ValidationErrors{"expiration": "is required"}
+	}
+	if reason := validateExpiration(r.Expiration.Duration); reason != "" {
+		return ValidationErrors{"expiration": reason}
+	}
+	return nil
+}
+
+// Validate checks the imported tokens, returning nil when all of them are valid. Errors are keyed by the index of
+// the token and the name of its field, e.g. tokens[0].expirationDate.
+func (r *ImportRequest) Validate(now time.Time) ValidationErrors {
+	if len(r.Tokens) == 0 {
+		return ValidationErrors{"tokens": "must not be empty"}
+	}
+
+	errs := ValidationErrors{}
+	seen := make(map[string]struct{}, len(r.Tokens))
+	for i, imported := range r.Tokens {
+		field := func(name string) string {
+			return fmt.Sprintf("tokens[%d].%s", i, name)
+		}
+
+		switch _, duplicate := seen[imported.JTI]; {
+		case imported.JTI == "":
+			errs[field("jti")] = "is required"
+		case duplicate:
+			errs[field("jti")] = fmt.Sprintf("must not be imported more than once, %q is", imported.JTI)
+		}
+		seen[imported.JTI] = struct{}{}
+
+		if strings.TrimSpace(imported.Username) == "" {
+			errs[field("username")] = "is required"
+		}
+
+		if imported.Namespace != "" {
+			if msgs := k8svalidation.IsDNS1123Label(imported.Namespace); len(msgs) > 0 {
+				errs[field("namespace")] = "must be a valid namespace name: " + strings.Join(msgs, "; ")
+			}
+		}
+
+		if reason := validateName(imported.Name); reason != "" {
+			errs[field("name")] = reason
+		}
+
+		created, err := time.Parse(time.RFC3339, imported.CreationDate)
+		switch {
+		case err != nil:
+			errs[field("creationDate")] = "must be an RFC 3339 timestamp"
+		case created.After(now):
+			errs[field("creationDate")] = "must not be in the future"
+		}
+
+		expires, err := time.Parse(time.RFC3339, imported.ExpirationDate)
+		switch {
+		case err != nil:
+			errs[field("expirationDate")] = "must be an RFC 3339 timestamp"
+		case !expires.After(now):
+			errs[field("expirationDate")] = "must be in the future, expired tokens are not imported"
+		}
+
+		if imported.Token != "" {
+			if jti, err := token.ExtractJTI(imported.Token); err != nil || jti != imported.JTI {
+				errs[field("token")] = "must be a token whose jti claim is the imported jti"
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateName returns why the name of a key is invalid, or an empty string when it is valid.
+func validateName(name string) string {
+	switch {
+	case name == "":
+		return "is required"
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return fmt.Sprintf("must not exceed %d characters", MaxNameLength)
+	case !namePattern.MatchString(name):
+		return "must start with a letter or a digit and contain only letters, digits, spaces, '.', '_' or '-'"
+	}
+	return ""
+}
+
+// validateExpiration returns why the key lifetime is invalid, or an empty string when it is valid.
+// The maximum set for the tier of the user is enforced when the token is issued.
+func validateExpiration(d time.Duration) string {
+	if err := token.ValidateExpiration(d, MinExpiration); err != nil {
+		return err.Error()
+	}
+	if d > MaxExpiration {
+		return "must not exceed " + MaxExpiration.String()
+	}
+	return ""
+}
+
+// validateModels returns why the model scope is invalid, or an empty string when it is valid.
+func validateModels(models []string) string {
+	if len(models) > MaxModels {
+		return fmt.Sprintf("must not list more than %d models", MaxModels)
+	}
+
+	seen := make(map[string]struct{}, len(models))
+	for _, model := range models {
+		if strings.TrimSpace(model) == "" {
+			return "must not contain empty model IDs"
+		}
+		if _, duplicate := seen[model]; duplicate {
+			return fmt.Sprintf("must not list model %q more than once", model)
+		}
+		seen[model] = struct{}{}
+	}
+
+	return ""
+}
+
+// validateMetadata returns why the key/value pairs attached to a key are invalid, or an empty string when they are valid.
+func validateMetadata(metadata map[string]string) string {
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Sprintf("must not contain more than %d entries", MaxMetadataEntries)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		switch {
+		case strings.TrimSpace(key) == "":
+			return "must not contain empty keys"
+		case utf8.RuneCountInString(key) > MaxMetadataKeyLength:
+			return fmt.Sprintf("key %q must not exceed %d characters", key, MaxMetadataKeyLength)
+		case utf8.RuneCountInString(metadata[key]) > MaxMetadataValueLength:
+			return fmt.Sprintf("value of %q must not exceed %d characters", key, MaxMetadataValueLength)
+		}
+	}
+
+	return ""
+}
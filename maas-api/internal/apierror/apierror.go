@@ -0,0 +1,95 @@
+// Package apierror writes the error envelope shared by all maas-api handlers:
+//
+//	{"error": {"code": "NOT_FOUND", "message": "API key not found", "type": "not_found_error", "requestId": "..."}}
+package apierror
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+// Machine-readable error codes carried in the code field of the envelope.
+const (
+	CodeInvalidRequest       = "INVALID_REQUEST"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeRequestTooLarge      = "REQUEST_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeAuthFailure          = "AUTH_FAILURE"
+	CodeTimeout              = "TIMEOUT"
+	CodeInternal             = "INTERNAL_ERROR"
+)
+
+// Response is the body of every error response.
+type Response struct {
+	Error Error `json:"error"`
+}
+
+// Error describes what went wrong. Type is derived from the HTTP status, following the OpenAI error types.
+type Error struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Type      string `json:"type"`
+	RequestID string `json:"requestId"`
+	// Details optionally carries structured information, such as per-field validation errors.
+	Details any `json:"details,omitempty"`
+}
+
+// Write writes an error response with the given status.
+func Write(c *gin.Context, status int, code, message string) {
+	WriteWithDetails(c, status, code, message, nil)
+}
+
+// WriteWithDetails writes an error response carrying additional details.
+func WriteWithDetails(c *gin.Context, status int, code, message string, details any) {
+	c.JSON(status, newResponse(c, status, code, message, details))
+}
+
+// Abort writes an error response and stops the handler chain, for use in middlewares.
+func Abort(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, newResponse(c, status, code, message, nil))
+}
+
+func newResponse(c *gin.Context, status int, code, message string, details any) Response {
+	return Response{
+		Error: Error{
+			Code:      code,
+			Message:   message,
+			Type:      typeForStatus(status),
+			RequestID: requestID(c),
+			Details:   details,
+		},
+	}
+}
+
+// requestID returns the ID of the request, as set by the gateway, or generates one.
+// The ID is echoed in the response header so that clients can report it.
+func requestID(c *gin.Context) string {
+	id := c.GetHeader(constant.HeaderRequestID)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	c.Header(constant.HeaderRequestID, id)
+	return id
+}
+
+func typeForStatus(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "authentication_error"
+	case status == http.StatusForbidden:
+		return "permission_error"
+	case status == http.StatusNotFound:
+		return "not_found_error"
+	case status >= http.StatusInternalServerError:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
@@ -0,0 +1,87 @@
+package apierror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		status       int
+		code         string
+		expectedType string
+	}{
+		{http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid_request_error"},
+		{http.StatusUnprocessableEntity, apierror.CodeValidationFailed, "invalid_request_error"},
+		{http.StatusForbidden, apierror.CodeForbidden, "permission_error"},
+		{http.StatusNotFound, apierror.CodeNotFound, "not_found_error"},
+		{http.StatusConflict, apierror.CodeConflict, "invalid_request_error"},
+		{http.StatusInternalServerError, apierror.CodeInternal, "server_error"},
+		{http.StatusGatewayTimeout, apierror.CodeTimeout, "server_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set(constant.HeaderRequestID, "req-123")
+
+			apierror.Write(c, tt.status, tt.code, "something went wrong")
+
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "req-123", w.Header().Get(constant.HeaderRequestID))
+			assert.JSONEq(t, `{"error": {
+				"code": "`+tt.code+`",
+				"message": "something went wrong",
+				"type": "`+tt.expectedType+`",
+				"requestId": "req-123"
+			}}`, w.Body.String())
+		})
+	}
+}
+
+func TestWrite_GeneratesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	apierror.WriteWithDetails(c, http.StatusBadRequest, apierror.CodeValidationFailed, "Validation failed", map[string]string{"name": "is required"})
+
+	var response apierror.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Error.RequestID)
+	assert.Equal(t, response.Error.RequestID, w.Header().Get(constant.HeaderRequestID))
+	assert.Equal(t, map[string]any{"name": "is required"}, response.Error.Details)
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		apierror.Abort(c, http.StatusForbidden, apierror.CodeForbidden, "Insufficient permissions")
+	}, func(c *gin.Context) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, called, "handler chain must stop after Abort")
+}
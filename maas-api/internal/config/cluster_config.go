@@ -29,18 +29,57 @@ type ClusterConfig struct {
 	InferenceServiceLister    kservelistersv1beta1.InferenceServiceLister
 	LLMInferenceServiceLister kservelistersv1alpha1.LLMInferenceServiceLister
 
+	GatewayLister   gatewaylisters.GatewayLister
 	HTTPRouteLister gatewaylisters.HTTPRouteLister
 
-	informersSynced []cache.InformerSynced
-	startFuncs      []func(<-chan struct{})
+	resyncPeriod time.Duration
+	informers    []namedInformer
+	startFuncs   []func(<-chan struct{})
 }
 
+// namedInformer is an informer along with the resource it caches, as reported by InformerStatuses.
+type namedInformer struct {
+	resource string
+	informer cache.SharedIndexInformer
+}
+
+// InformerStatus reports the state of the informer cache of a resource.
+type InformerStatus struct {
+	Resource string `json:"resource"`
+	Synced   bool   `json:"synced"`
+	// LastSyncResourceVersion is the resource version observed by the latest list or watch, empty before the first list.
+	LastSyncResourceVersion string `json:"lastSyncResourceVersion"`
+	Objects                 int    `json:"objects"`
+}
+
+// NewInformerStatus reports the state of the informer caching the resource.
+func NewInformerStatus(resource string, informer cache.SharedInformer) InformerStatus {
+	return InformerStatus{
+		Resource:                resource,
+		Synced:                  informer.HasSynced(),
+		LastSyncResourceVersion: informer.LastSyncResourceVersion(),
+		Objects:                 len(informer.GetStore().ListKeys()),
+	}
+}
+
+// NewClusterConfig creates the clients and informers for the cluster found through LoadRestConfig.
+// A resyncPeriod of 0 disables periodic resync of the informers.
 func NewClusterConfig(namespace string, resyncPeriod time.Duration) (*ClusterConfig, error) {
 	restConfig, err := LoadRestConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes config: %w", err)
 	}
 
+	return NewClusterConfigForRestConfig(restConfig, namespace, resyncPeriod)
+}
+
+// NewClusterConfigForRestConfig creates the clients and informers for the cluster reachable through restConfig.
+// A resyncPeriod of 0 disables periodic resync of the informers.
+func NewClusterConfigForRestConfig(restConfig *rest.Config, namespace string, resyncPeriod time.Duration) (*ClusterConfig, error) {
+	if resyncPeriod < 0 {
+		return nil, fmt.Errorf("resync period must not be negative, got %s", resyncPeriod)
+	}
+
 	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
@@ -66,6 +105,7 @@ func NewClusterConfig(namespace string, resyncPeriod time.Duration) (*ClusterCon
 	saInformer := coreFactory.Core().V1().ServiceAccounts()
 	isvcInformer := kserveFactory.Serving().V1beta1().InferenceServices()
 	llmIsvcInformer := kserveFactory.Serving().V1alpha1().LLMInferenceServices()
+	gatewayInformer := gatewayFactory.Gateway().V1().Gateways()
 	httpRouteInformer := gatewayFactory.Gateway().V1().HTTPRoutes()
 
 	return &ClusterConfig{
@@ -78,15 +118,18 @@ func NewClusterConfig(namespace string, resyncPeriod time.Duration) (*ClusterCon
 		InferenceServiceLister:    isvcInformer.Lister(),
 		LLMInferenceServiceLister: llmIsvcInformer.Lister(),
 
+		GatewayLister:   gatewayInformer.Lister(),
 		HTTPRouteLister: httpRouteInformer.Lister(),
 
-		informersSynced: []cache.InformerSynced{
-			cmInformer.Informer().HasSynced,
-			nsInformer.Informer().HasSynced,
-			saInformer.Informer().HasSynced,
-			isvcInformer.Informer().HasSynced,
-			llmIsvcInformer.Informer().HasSynced,
-			httpRouteInformer.Informer().HasSynced,
+		resyncPeriod: resyncPeriod,
+		informers: []namedInformer{
+			{resource: "configmaps", informer: cmInformer.Informer()},
+			{resource: "namespaces", informer: nsInformer.Informer()},
+			{resource: "serviceaccounts", informer: saInformer.Informer()},
+			{resource: "inferenceservices", informer: isvcInformer.Informer()},
+			{resource: "llminferenceservices", informer: llmIsvcInformer.Informer()},
+			{resource: "gateways", informer: gatewayInformer.Informer()},
+			{resource: "httproutes", informer: httpRouteInformer.Informer()},
 		},
 		startFuncs: []func(<-chan struct{}){
 			coreFactory.Start,
@@ -101,7 +144,35 @@ func (c *ClusterConfig) StartAndWaitForSync(stopCh <-chan struct{}) bool {
 	for _, start := range c.startFuncs {
 		start(stopCh)
 	}
-	return cache.WaitForCacheSync(stopCh, c.informersSynced...)
+	informersSynced := make([]cache.InformerSynced, 0, len(c.informers))
+	for _, named := range c.informers {
+		informersSynced = append(informersSynced, named.informer.HasSynced)
+	}
+	return cache.WaitForCacheSync(stopCh, informersSynced...)
+}
+
+// ResyncPeriod returns the period at which informers resync, 0 meaning periodic resync is disabled.
+func (c *ClusterConfig) ResyncPeriod() time.Duration {
+	return c.resyncPeriod
+}
+
+// HasSynced reports whether all informer caches have synced.
+func (c *ClusterConfig) HasSynced() bool {
+	for _, named := range c.informers {
+		if !named.informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// InformerStatuses reports the state of each informer cache, in a stable order.
+func (c *ClusterConfig) InformerStatuses() []InformerStatus {
+	statuses := make([]InformerStatus, 0, len(c.informers))
+	for _, named := range c.informers {
+		statuses = append(statuses, NewInformerStatus(named.resource, named.informer))
+	}
+	return statuses
 }
 
 // LoadRestConfig creates a *rest.Config using client-go loading rules.
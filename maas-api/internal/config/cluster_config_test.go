@@ -0,0 +1,98 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+)
+
+func TestNewClusterConfigForRestConfig_ResyncPeriod(t *testing.T) {
+	// Creating clients and informers does not contact the API server.
+	restConfig := &rest.Config{Host: "https://127.0.0.1:6443"}
+
+	t.Run("custom period is applied", func(t *testing.T) {
+		cluster, err := config.NewClusterConfigForRestConfig(restConfig, "maas-api", 30*time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Minute, cluster.ResyncPeriod())
+	})
+
+	t.Run("zero disables periodic resync", func(t *testing.T) {
+		cluster, err := config.NewClusterConfigForRestConfig(restConfig, "maas-api", 0)
+		require.NoError(t, err)
+		assert.Zero(t, cluster.ResyncPeriod())
+	})
+
+	t.Run("negative period is rejected", func(t *testing.T) {
+		_, err := config.NewClusterConfigForRestConfig(restConfig, "maas-api", -time.Minute)
+		require.Error(t, err)
+	})
+}
+
+func TestConfigValidate_RejectsNegativeResyncPeriod(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       config.DefaultReadTimeout,
+		WriteTimeout:      config.DefaultWriteTimeout,
+		IdleTimeout:       config.DefaultIdleTimeout,
+		ResyncPeriod:      -time.Second,
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "informer-resync-period")
+}
+
+func TestNewInformerStatus(t *testing.T) {
+	clientset := k8sfake.NewClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "maas-api"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "model-serving"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "free-user", Namespace: "maas-api-tier-free"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "premium-user", Namespace: "maas-api-tier-premium"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "enterprise-user", Namespace: "maas-api-tier-enterprise"}},
+	)
+
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	nsInformer := factory.Core().V1().Namespaces().Informer()
+	saInformer := factory.Core().V1().ServiceAccounts().Informer()
+
+	assert.Equal(t, config.InformerStatus{Resource: "namespaces"}, config.NewInformerStatus("namespaces", nsInformer),
+		"an informer that is not started has not synced")
+
+	factory.Start(t.Context().Done())
+	require.True(t, cache.WaitForCacheSync(t.Context().Done(), nsInformer.HasSynced, saInformer.HasSynced))
+
+	namespaces := config.NewInformerStatus("namespaces", nsInformer)
+	assert.Equal(t, "namespaces", namespaces.Resource)
+	assert.True(t, namespaces.Synced)
+	assert.Equal(t, 2, namespaces.Objects)
+	assert.Equal(t, nsInformer.LastSyncResourceVersion(), namespaces.LastSyncResourceVersion)
+
+	serviceAccounts := config.NewInformerStatus("serviceaccounts", saInformer)
+	assert.True(t, serviceAccounts.Synced)
+	assert.Equal(t, 3, serviceAccounts.Objects)
+}
+
+func TestClusterConfig_InformerStatuses(t *testing.T) {
+	cluster, err := config.NewClusterConfigForRestConfig(&rest.Config{Host: "https://127.0.0.1:6443"}, "maas-api", 0)
+	require.NoError(t, err)
+
+	resources := make([]string, 0)
+	for _, status := range cluster.InformerStatuses() {
+		resources = append(resources, status.Resource)
+		assert.False(t, status.Synced, "%s informer is not started", status.Resource)
+		assert.Zero(t, status.Objects)
+	}
+	assert.Equal(t, []string{
+		"configmaps", "namespaces", "serviceaccounts", "inferenceservices", "llminferenceservices", "gateways", "httproutes",
+	}, resources)
+}
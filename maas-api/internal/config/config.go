@@ -1,8 +1,14 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"k8s.io/utils/env"
 
@@ -37,8 +43,97 @@ func (s *StorageMode) Set(value string) error {
 	}
 }
 
+// StringList is a comma-separated list of values usable as a flag.
+type StringList []string
+
+// ParseStringList splits a comma-separated string into trimmed, non-empty values.
+func ParseStringList(value string) StringList {
+	var list StringList
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// String implements flag.Value interface.
+func (l *StringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *StringList) Set(value string) error {
+	*l = ParseStringList(value)
+	return nil
+}
+
+// GroupMapping maps keys to the groups they require, usable as a flag.
+// It is written as comma-separated key=group1|group2 entries.
+type GroupMapping map[string][]string
+
+// String implements flag.Value interface.
+func (m *GroupMapping) String() string {
+	entries := make([]string, 0, len(*m))
+	for _, key := range slices.Sorted(maps.Keys(*m)) {
+		entries = append(entries, key+"="+strings.Join((*m)[key], "|"))
+	}
+	return strings.Join(entries, ",")
+}
+
+func (m *GroupMapping) Set(value string) error {
+	mapping := GroupMapping{}
+	for _, entry := range ParseStringList(value) {
+		key, groups, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return fmt.Errorf("invalid group mapping entry %q: expected key=group1|group2", entry)
+		}
+
+		for group := range strings.SplitSeq(groups, "|") {
+			if group = strings.TrimSpace(group); group != "" {
+				mapping[key] = append(mapping[key], group)
+			}
+		}
+		if len(mapping[key]) == 0 {
+			return fmt.Errorf("invalid group mapping entry %q: at least one group is required", entry)
+		}
+	}
+
+	*m = mapping
+	return nil
+}
+
 const DefaultDataPath = "/data/maas-api.db"
 
+// DefaultExpirationGrace is the default clock skew tolerated when deciding whether an API key has expired.
+const DefaultExpirationGrace = 30 * time.Second
+
+// DefaultCompressionMinSize is the default size, in bytes, from which JSON responses are gzip-compressed.
+const DefaultCompressionMinSize = 1024
+
+// MinRevokeConfirmationSecretLength is the minimum length, in bytes, of the secret keying revoke confirmation tokens.
+const MinRevokeConfirmationSecretLength = 32
+
+// DefaultMaxRequestBodySize is the default size limit, in bytes, of the body of token and API key requests.
+const DefaultMaxRequestBodySize = 16 << 10
+
+// Default page sizes of listings.
+const (
+	DefaultPageSize    = 50
+	DefaultMaxPageSize = 200
+)
+
+// Default HTTP server timeouts.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 60 * time.Second
+
+	// DefaultMaxRequestTimeout bounds the deadline clients set in the timeout header, as long as the write timeout.
+	DefaultMaxRequestTimeout = DefaultWriteTimeout
+)
+
 type Config struct {
 	Name      string
 	Namespace string
@@ -46,10 +141,81 @@ type Config struct {
 	GatewayName      string
 	GatewayNamespace string
 
+	// Gateways lists all MaaS-enabled gateways as namespace/name entries.
+	// When empty, only the gateway identified by GatewayName and GatewayNamespace is used.
+	Gateways StringList
+
+	// ManagedRouteLabels are key=value label templates selecting the HTTPRoutes KServe manages for
+	// LLMInferenceServices. Values may reference {name}. When empty, the labels set by KServe are used.
+	ManagedRouteLabels StringList
+
+	// ModelIDStrategy determines how model IDs are derived: model-name (default), resource-name or namespaced.
+	ModelIDStrategy string
+
 	Port string
 
 	DebugMode bool
 
+	// LogFormat is the output format of the logs, json or console. Defaults to console in debug mode and json otherwise.
+	LogFormat string
+	// LogLevel is the minimum level logged, independently of DebugMode. Defaults to debug in debug mode and info otherwise.
+	LogLevel string
+
+	// AdminGroups lists the groups whose members can see models with internal visibility.
+	AdminGroups StringList
+
+	// ImpersonationGroups lists the groups whose members can issue tokens on behalf of other users.
+	ImpersonationGroups StringList
+
+	// MaxGroups caps the number of distinct groups accepted in the group header set by the auth policy. 0 disables the cap.
+	MaxGroups int
+
+	// TrustedProxies lists the addresses and CIDRs of the proxies trusted to report the client IP
+	// in the Forwarded and X-Forwarded-For headers.
+	TrustedProxies StringList
+
+	// ModelAccessGroups maps model IDs to the groups allowed to list them.
+	// Models without an entry are listed for every caller.
+	ModelAccessGroups GroupMapping
+	// modelAccessGroupsErr holds the error parsing MODEL_ACCESS_GROUPS, reported by Validate.
+	modelAccessGroupsErr error
+
+	// TierNamespaceLabels are key=value label templates added to tier namespaces when they are created.
+	// Values may reference {instance} and {tier}.
+	TierNamespaceLabels StringList
+
+	// ManageNamespaces enables the creation of tier namespaces. When disabled, they must be pre-created.
+	ManageNamespaces bool
+	// TierNamespaceFallback is the namespace used for tiers whose namespace maas-api is not allowed to create.
+	// Empty fails token requests for these tiers.
+	TierNamespaceFallback string
+	// MaxTierNamespaces caps the number of tier namespaces maas-api creates, guarding against a tier configuration
+	// generating spurious tiers. 0 disables the cap.
+	MaxTierNamespaces int
+	// SystemUsers are shell-style username patterns of system identities, e.g. a platform operator, whose tokens
+	// are issued in SystemUserNamespace without resolving their tier. It is unrelated to any authorization bypass.
+	SystemUsers StringList
+	// SystemUserNamespace is the existing namespace of the Service Accounts of SystemUsers.
+	SystemUserNamespace string
+
+	// PublicCatalog enables the unauthenticated GET /v1/catalog endpoint.
+	PublicCatalog bool
+
+	// ListNotReadyModels lists models that are not ready in GET /v1/models by default.
+	ListNotReadyModels bool
+
+	// ExcludeModelsWithoutURL leaves models without a URL out of the model listings, instead of listing them
+	// with endpointPending set.
+	ExcludeModelsWithoutURL bool
+
+	// AuthzDebugHeader sends admins the X-MaaS-Authz-Debug header summarizing the authorization of listed models.
+	AuthzDebugHeader bool
+
+	// EnforceUniqueKeyNames rejects API keys named like another active key of the same user.
+	EnforceUniqueKeyNames bool
+	// MaxActiveKeysPerUser caps the number of active API keys of a user. 0 disables the cap.
+	MaxActiveKeysPerUser int
+
 	// StorageMode specifies the storage backend type:
 	//   - "in-memory" (default): Ephemeral storage, data lost on restart
 	//   - "disk": Persistent local storage using a file (single replica only)
@@ -62,25 +228,155 @@ type Config struct {
 	// DataPath is the path to the database file for disk mode.
 	// Default: /data/maas-api.db
 	DataPath string
+
+	// ExpirationGrace is the clock skew tolerated when deciding whether an API key has expired.
+	ExpirationGrace time.Duration
+
+	// TokenIDPrefix is the issuer prefix of the IDs of stored API keys, keeping them unique across issuers
+	// sharing or merging a database. Empty stores the JTIs as they are.
+	TokenIDPrefix string
+
+	// TokenExpirationJitterPercent spreads the expiration of issued tokens randomly by up to ±percent of the
+	// requested one, so that clients do not all renew their tokens at once. 0 grants the requested expiration.
+	TokenExpirationJitterPercent int
+
+	// RevocationGracePeriod defers the recreation of the Service Account of a user revoking their tokens.
+	// API keys are expired in the store right away. 0 recreates the Service Account immediately.
+	RevocationGracePeriod time.Duration
+
+	// RevokeConfirmationTTL requires users revoking their tokens to confirm with a confirmation token valid for that
+	// long. 0 revokes right away.
+	RevokeConfirmationTTL time.Duration
+
+	// RevokeConfirmationSecret keys the confirmation tokens and must be shared by all replicas. A random secret is
+	// generated at startup when empty, so that confirmation tokens are only accepted by the replica that issued them.
+	RevokeConfirmationSecret string
+
+	// ResyncPeriod is the period at which informers resync their caches. 0 disables periodic resync.
+	ResyncPeriod time.Duration
+
+	// HTTP server timeouts, see net/http.Server for their semantics.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxRequestTimeout bounds the deadline clients set for their request in the X-MaaS-Timeout header, longer ones
+	// are shortened to it. 0 ignores the header.
+	MaxRequestTimeout time.Duration
+
+	// CompressionMinSize is the size, in bytes, from which JSON responses are gzip-compressed for clients
+	// accepting it. 0 disables compression.
+	CompressionMinSize int
+
+	// MaxRequestBodySize is the size limit, in bytes, of the body of token and API key requests. 0 disables the limit.
+	MaxRequestBodySize int64
+	// RequireJSONContentType rejects token, API key, introspection and tier lookup requests with a body whose
+	// Content-Type is not application/json.
+	RequireJSONContentType bool
+
+	// DefaultPageSize is the number of items listed per page when no limit is requested. 0 uses the built-in default.
+	DefaultPageSize int
+	// MaxPageSize bounds the number of items listed per page, larger limits are clamped. 0 uses the built-in maximum.
+	MaxPageSize int
+
+	// ValidateOnly validates the configuration and the tier mapping, then exits without starting the server.
+	ValidateOnly bool
+	// TierConfigFile is the tier mapping ConfigMap manifest checked by ValidateOnly.
+	// When empty, the ConfigMap is read from the cluster.
+	TierConfigFile string
 }
 
 // Load loads configuration from environment variables.
 func Load() *Config {
 	debugMode, _ := env.GetBool("DEBUG_MODE", false)
+	publicCatalog, _ := env.GetBool("PUBLIC_CATALOG", false)
+	listNotReadyModels, _ := env.GetBool("LIST_NOT_READY_MODELS", false)
+	excludeModelsWithoutURL, _ := env.GetBool("EXCLUDE_MODELS_WITHOUT_URL", false)
+	authzDebugHeader, _ := env.GetBool("AUTHZ_DEBUG_HEADER", false)
+	manageNamespaces, _ := env.GetBool("MANAGE_NAMESPACES", true)
+	enforceUniqueKeyNames, _ := env.GetBool("ENFORCE_UNIQUE_KEY_NAMES", false)
+	maxActiveKeysPerUser, _ := env.GetInt("MAX_ACTIVE_KEYS_PER_USER", 0)
+	maxTierNamespaces, _ := env.GetInt("MAX_TIER_NAMESPACES", 0)
+	maxGroups, _ := env.GetInt("MAX_GROUPS", constant.DefaultMaxGroups)
+	tokenExpirationJitterPercent, _ := env.GetInt("TOKEN_EXPIRATION_JITTER_PERCENT", 0)
+	readHeaderTimeout, _ := getDuration("HTTP_READ_HEADER_TIMEOUT", DefaultReadHeaderTimeout)
+	readTimeout, _ := getDuration("HTTP_READ_TIMEOUT", DefaultReadTimeout)
+	writeTimeout, _ := getDuration("HTTP_WRITE_TIMEOUT", DefaultWriteTimeout)
+	idleTimeout, _ := getDuration("HTTP_IDLE_TIMEOUT", DefaultIdleTimeout)
+	maxRequestTimeout, _ := getDuration("MAX_REQUEST_TIMEOUT", DefaultMaxRequestTimeout)
+	resyncPeriod, _ := getDuration("INFORMER_RESYNC_PERIOD", constant.DefaultResyncPeriod)
+	expirationGrace, _ := getDuration("EXPIRATION_GRACE", DefaultExpirationGrace)
+	revocationGracePeriod, _ := getDuration("REVOCATION_GRACE_PERIOD", 0)
+	revokeConfirmationTTL, _ := getDuration("REVOKE_CONFIRMATION_TTL", 0)
+	compressionMinSize, _ := env.GetInt("COMPRESSION_MIN_SIZE", DefaultCompressionMinSize)
+	maxRequestBodySize, _ := env.GetInt("MAX_REQUEST_BODY_SIZE", DefaultMaxRequestBodySize)
+	requireJSONContentType, _ := env.GetBool("REQUIRE_JSON_CONTENT_TYPE", false)
+	defaultPageSize, _ := env.GetInt("DEFAULT_PAGE_SIZE", DefaultPageSize)
+	maxPageSize, _ := env.GetInt("MAX_PAGE_SIZE", DefaultMaxPageSize)
 	gatewayName := env.GetString("GATEWAY_NAME", constant.DefaultGatewayName)
 
 	c := &Config{
-		Name:             env.GetString("INSTANCE_NAME", gatewayName),
-		Namespace:        env.GetString("NAMESPACE", constant.DefaultNamespace),
-		GatewayName:      env.GetString("GATEWAY_NAME", gatewayName),
-		GatewayNamespace: env.GetString("GATEWAY_NAMESPACE", constant.DefaultGatewayNamespace),
-		Port:             env.GetString("PORT", "8080"),
-		DebugMode:        debugMode,
-		StorageMode:      StorageModeInMemory,
-		DBConnectionURL:  env.GetString("DB_CONNECTION_URL", ""),
-		DataPath:         env.GetString("DATA_PATH", DefaultDataPath),
+		Name:                    env.GetString("INSTANCE_NAME", gatewayName),
+		Namespace:               env.GetString("NAMESPACE", constant.DefaultNamespace),
+		GatewayName:             env.GetString("GATEWAY_NAME", gatewayName),
+		GatewayNamespace:        env.GetString("GATEWAY_NAMESPACE", constant.DefaultGatewayNamespace),
+		Gateways:                ParseStringList(env.GetString("GATEWAYS", "")),
+		ManagedRouteLabels:      ParseStringList(env.GetString("MANAGED_ROUTE_LABELS", "")),
+		ModelIDStrategy:         env.GetString("MODEL_ID_STRATEGY", "model-name"),
+		Port:                    env.GetString("PORT", "8080"),
+		DebugMode:               debugMode,
+		AdminGroups:             ParseStringList(env.GetString("ADMIN_GROUPS", "")),
+		PublicCatalog:           publicCatalog,
+		ListNotReadyModels:      listNotReadyModels,
+		ExcludeModelsWithoutURL: excludeModelsWithoutURL,
+		AuthzDebugHeader:        authzDebugHeader,
+		StorageMode:             StorageModeInMemory,
+		DBConnectionURL:         env.GetString("DB_CONNECTION_URL", ""),
+		DataPath:                env.GetString("DATA_PATH", DefaultDataPath),
+		ExpirationGrace:         expirationGrace,
+		TokenIDPrefix:           env.GetString("TOKEN_ID_PREFIX", ""),
+		ResyncPeriod:            resyncPeriod,
+
+		ImpersonationGroups: ParseStringList(env.GetString("IMPERSONATION_GROUPS", "")),
+		MaxGroups:           maxGroups,
+
+		TrustedProxies: ParseStringList(env.GetString("TRUSTED_PROXIES", "")),
+
+		TierNamespaceLabels:   ParseStringList(env.GetString("TIER_NAMESPACE_LABELS", "")),
+		ManageNamespaces:      manageNamespaces,
+		TierNamespaceFallback: env.GetString("TIER_NAMESPACE_FALLBACK", ""),
+		MaxTierNamespaces:     maxTierNamespaces,
+		SystemUsers:           ParseStringList(env.GetString("SYSTEM_USERS", "")),
+		SystemUserNamespace:   env.GetString("SYSTEM_USER_NAMESPACE", ""),
+		EnforceUniqueKeyNames: enforceUniqueKeyNames,
+		MaxActiveKeysPerUser:  maxActiveKeysPerUser,
+
+		RevocationGracePeriod:    revocationGracePeriod,
+		RevokeConfirmationTTL:    revokeConfirmationTTL,
+		RevokeConfirmationSecret: env.GetString("REVOKE_CONFIRMATION_SECRET", ""),
+
+		TokenExpirationJitterPercent: tokenExpirationJitterPercent,
+
+		LogFormat: env.GetString("LOG_FORMAT", ""),
+		LogLevel:  env.GetString("LOG_LEVEL", ""),
+
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxRequestTimeout: maxRequestTimeout,
+
+		CompressionMinSize:     compressionMinSize,
+		MaxRequestBodySize:     int64(maxRequestBodySize),
+		RequireJSONContentType: requireJSONContentType,
+
+		DefaultPageSize: defaultPageSize,
+		MaxPageSize:     maxPageSize,
 	}
 
+	c.modelAccessGroupsErr = c.ModelAccessGroups.Set(env.GetString("MODEL_ACCESS_GROUPS", ""))
+
 	// Validate STORAGE_MODE env var through Set() to ensure consistent validation
 	if err := c.StorageMode.Set(env.GetString("STORAGE_MODE", "")); err != nil {
 		// Log warning and fall back to default (in-memory)
@@ -98,9 +394,183 @@ func (c *Config) bindFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.Namespace, "namespace", c.Namespace, "Namespace of the MaaS instance")
 	fs.StringVar(&c.GatewayName, "gateway-name", c.GatewayName, "Name of the Gateway that has MaaS capabilities")
 	fs.StringVar(&c.GatewayNamespace, "gateway-namespace", c.GatewayNamespace, "Namespace where MaaS-enabled Gateway is deployed")
+	fs.Var(&c.Gateways, "gateways", "Comma-separated list of MaaS-enabled Gateways as namespace/name[=audience] (defaults to --gateway-namespace/--gateway-name)")
+	fs.Var(&c.ManagedRouteLabels, "managed-route-labels", "Comma-separated key=value labels selecting the HTTPRoutes KServe manages for LLMInferenceServices; values may reference {name} (defaults to the labels set by KServe)")
+	fs.StringVar(&c.ModelIDStrategy, "model-id-strategy", c.ModelIDStrategy, "How model IDs are derived: model-name (spec.model.name, falling back to the resource name), resource-name or namespaced (namespace/name)")
 	fs.StringVar(&c.Port, "port", c.Port, "Port to listen on")
 	fs.BoolVar(&c.DebugMode, "debug", c.DebugMode, "Enable debug mode")
+	fs.StringVar(&c.LogFormat, "log-format", c.LogFormat, "Log output format: json or console (defaults to console in debug mode, json otherwise)")
+	fs.StringVar(&c.LogLevel, "log-level", c.LogLevel, "Minimum log level: debug, info, warn or error (defaults to debug in debug mode, info otherwise)")
+	fs.Var(&c.AdminGroups, "admin-groups", "Comma-separated list of groups allowed to see models with internal visibility")
+	fs.Var(&c.ImpersonationGroups, "impersonation-groups", "Comma-separated list of groups allowed to issue tokens on behalf of other users")
+	fs.IntVar(&c.MaxGroups, "max-groups", c.MaxGroups, "Maximum number of distinct groups accepted in the group header, larger ones are rejected with 400 (0 disables the cap)")
+	fs.Var(&c.TrustedProxies, "trusted-proxies", "Comma-separated list of proxy addresses or CIDRs trusted to report the client IP in Forwarded and X-Forwarded-For headers")
+	fs.Var(&c.ModelAccessGroups, "model-access-groups", "Comma-separated model=group1|group2 entries restricting which groups can list a model")
+	fs.Var(&c.TierNamespaceLabels, "tier-namespace-labels", "Comma-separated key=value labels added to created tier namespaces; values may reference {instance} and {tier}")
+	fs.BoolVar(&c.ManageNamespaces, "manage-namespaces", c.ManageNamespaces, "Create tier namespaces on demand; when false, they must be pre-created")
+	fs.StringVar(&c.TierNamespaceFallback, "tier-namespace-fallback", c.TierNamespaceFallback, "Namespace used for tiers whose namespace maas-api is not allowed to create")
+	fs.IntVar(&c.MaxTierNamespaces, "max-tier-namespaces", c.MaxTierNamespaces, "Maximum number of tier namespaces created by maas-api, 0 for no limit")
+	fs.Var(&c.SystemUsers, "system-users", "Comma-separated username patterns whose tokens are issued in the system user namespace, skipping tier resolution")
+	fs.StringVar(&c.SystemUserNamespace, "system-user-namespace", c.SystemUserNamespace, "Existing namespace of the Service Accounts of system users")
+	fs.BoolVar(&c.PublicCatalog, "public-catalog", c.PublicCatalog, "Expose the unauthenticated model catalog at /v1/catalog")
+	fs.BoolVar(&c.ListNotReadyModels, "list-not-ready-models", c.ListNotReadyModels, "List models that are not ready in /v1/models unless include_not_ready=false is requested")
+	fs.BoolVar(&c.ExcludeModelsWithoutURL, "exclude-models-without-url", c.ExcludeModelsWithoutURL, "Leave models without a URL out of the model listings instead of listing them with endpointPending set")
+	fs.BoolVar(&c.AuthzDebugHeader, "authz-debug-header", c.AuthzDebugHeader, "Send admins the X-MaaS-Authz-Debug header summarizing the authorization of the models in /v1/models")
+	fs.BoolVar(&c.EnforceUniqueKeyNames, "enforce-unique-key-names", c.EnforceUniqueKeyNames, "Reject API keys named like another active key of the same user")
+	fs.IntVar(&c.MaxActiveKeysPerUser, "max-active-keys-per-user", c.MaxActiveKeysPerUser, "Maximum number of active API keys of a user, 0 for no limit")
 	fs.Var(&c.StorageMode, "storage", "Storage mode: in-memory (default), disk, or external")
 	fs.StringVar(&c.DBConnectionURL, "db-connection-url", c.DBConnectionURL, "Database connection URL (required for --storage=external)")
 	fs.StringVar(&c.DataPath, "data-path", c.DataPath, "Path to database file (for --storage=disk)")
+	fs.DurationVar(&c.ExpirationGrace, "expiration-grace", c.ExpirationGrace, "Clock skew tolerated before an API key is reported as expired")
+	fs.StringVar(&c.TokenIDPrefix, "token-id-prefix", c.TokenIDPrefix, "Issuer prefix of the IDs of stored API keys, stripped when they are read")
+	fs.IntVar(&c.TokenExpirationJitterPercent, "token-expiration-jitter-percent", c.TokenExpirationJitterPercent, "Spread the expiration of issued tokens randomly by up to this percentage of the requested one (0 disables the jitter)")
+	fs.DurationVar(&c.RevocationGracePeriod, "revocation-grace-period", c.RevocationGracePeriod, "Delay before the Service Account of a user revoking their tokens is recreated; API keys are expired right away (0 recreates it immediately)")
+	fs.DurationVar(&c.RevokeConfirmationTTL, "revoke-confirmation-ttl", c.RevokeConfirmationTTL, "Require DELETE /v1/tokens to be confirmed with a confirmation token valid for this long (0 revokes right away)")
+	fs.StringVar(&c.RevokeConfirmationSecret, "revoke-confirmation-secret", c.RevokeConfirmationSecret, "Secret of at least 32 bytes keying the confirmation tokens of DELETE /v1/tokens, shared by all replicas (random per replica when empty)")
+	fs.DurationVar(&c.ResyncPeriod, "informer-resync-period", c.ResyncPeriod, "Period at which informers resync their caches (0 disables periodic resync)")
+	fs.DurationVar(&c.ReadHeaderTimeout, "read-header-timeout", c.ReadHeaderTimeout, "Maximum duration for reading request headers")
+	fs.DurationVar(&c.ReadTimeout, "read-timeout", c.ReadTimeout, "Maximum duration for reading the entire request, including the body")
+	fs.DurationVar(&c.WriteTimeout, "write-timeout", c.WriteTimeout, "Maximum duration before timing out writes of the response")
+	fs.DurationVar(&c.IdleTimeout, "idle-timeout", c.IdleTimeout, "Maximum amount of time to wait for the next request when keep-alives are enabled")
+	fs.DurationVar(&c.MaxRequestTimeout, "max-request-timeout", c.MaxRequestTimeout, "Upper bound of the deadline clients set in the X-MaaS-Timeout header (0 ignores the header)")
+	fs.IntVar(&c.CompressionMinSize, "compression-min-size", c.CompressionMinSize, "Size in bytes from which JSON responses are gzip-compressed for clients accepting it (0 disables compression)")
+	fs.Int64Var(&c.MaxRequestBodySize, "max-request-body-size", c.MaxRequestBodySize, "Size limit in bytes of the body of token and API key requests, larger requests are rejected with 413 (0 disables the limit)")
+	fs.BoolVar(&c.RequireJSONContentType, "require-json-content-type", c.RequireJSONContentType, "Reject token, API key, introspection and tier lookup requests with a body that is not sent as application/json with 415")
+	fs.IntVar(&c.DefaultPageSize, "default-page-size", c.DefaultPageSize, "Number of items listed per page when no limit is requested")
+	fs.IntVar(&c.MaxPageSize, "max-page-size", c.MaxPageSize, "Maximum number of items listed per page, larger limits are clamped")
+	fs.BoolVar(&c.ValidateOnly, "validate", c.ValidateOnly, "Validate the configuration and the tier mapping, then exit without starting the server")
+	fs.StringVar(&c.TierConfigFile, "tier-config-file", c.TierConfigFile, "Tier mapping ConfigMap manifest to check with --validate (read from the cluster when empty)")
+}
+
+// Redacted replaces the values of settings holding credentials in Effective.
+const Redacted = "REDACTED"
+
+// redactedFlags are the settings whose values may hold credentials, e.g. the database password.
+var redactedFlags = map[string]bool{
+	"db-connection-url":          true,
+	"revoke-confirmation-secret": true,
+}
+
+// Effective returns the value of every setting, keyed by its flag name, as resolved from the environment and the
+// command line. Values that may hold credentials are redacted; empty ones stay empty, so that it remains visible
+// whether they are set.
+func (c *Config) Effective() map[string]string {
+	// Binding flags sets the config to their defaults, i.e. its current values: bind a copy to leave it untouched.
+	cfg := *c
+	fs := flag.NewFlagSet("effective", flag.ContinueOnError)
+	cfg.bindFlags(fs)
+
+	settings := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if redactedFlags[f.Name] && value != "" {
+			value = Redacted
+		}
+		settings[f.Name] = value
+	})
+	return settings
+}
+
+// Validate checks the configuration for values that cannot be used to run the server.
+func (c *Config) Validate() error {
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read-header-timeout", c.ReadHeaderTimeout},
+		{"read-timeout", c.ReadTimeout},
+		{"write-timeout", c.WriteTimeout},
+		{"idle-timeout", c.IdleTimeout},
+	}
+
+	var errs []error
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive duration, got %s", timeout.name, timeout.value))
+		}
+	}
+
+	if c.modelAccessGroupsErr != nil {
+		errs = append(errs, fmt.Errorf("model-access-groups: %w", c.modelAccessGroupsErr))
+	}
+
+	if c.ResyncPeriod < 0 {
+		errs = append(errs, fmt.Errorf("informer-resync-period must not be negative, got %s", c.ResyncPeriod))
+	}
+
+	if c.MaxRequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("max-request-timeout must not be negative, got %s", c.MaxRequestTimeout))
+	}
+
+	if c.ExpirationGrace < 0 {
+		errs = append(errs, fmt.Errorf("expiration-grace must not be negative, got %s", c.ExpirationGrace))
+	}
+
+	if c.RevocationGracePeriod < 0 {
+		errs = append(errs, fmt.Errorf("revocation-grace-period must not be negative, got %s", c.RevocationGracePeriod))
+	}
+
+	if c.RevokeConfirmationTTL < 0 {
+		errs = append(errs, fmt.Errorf("revoke-confirmation-ttl must not be negative, got %s", c.RevokeConfirmationTTL))
+	}
+
+	if c.RevokeConfirmationSecret != "" && len(c.RevokeConfirmationSecret) < MinRevokeConfirmationSecretLength {
+		errs = append(errs, fmt.Errorf("revoke-confirmation-secret must be at least %d bytes long, got %d",
+			MinRevokeConfirmationSecretLength, len(c.RevokeConfirmationSecret)))
+	}
+
+	if c.TokenExpirationJitterPercent < 0 || c.TokenExpirationJitterPercent >= 100 {
+		errs = append(errs, fmt.Errorf("token-expiration-jitter-percent must be between 0 and 99, got %d", c.TokenExpirationJitterPercent))
+	}
+
+	if c.MaxRequestBodySize < 0 {
+		errs = append(errs, fmt.Errorf("max-request-body-size must not be negative, got %d", c.MaxRequestBodySize))
+	}
+
+	if c.CompressionMinSize < 0 {
+		errs = append(errs, fmt.Errorf("compression-min-size must not be negative, got %d", c.CompressionMinSize))
+	}
+
+	if len(c.SystemUsers) > 0 && c.SystemUserNamespace == "" {
+		errs = append(errs, errors.New("system-user-namespace is required when system-users is set"))
+	}
+
+	if c.MaxGroups < 0 {
+		errs = append(errs, fmt.Errorf("max-groups must not be negative, got %d", c.MaxGroups))
+	}
+
+	if c.MaxActiveKeysPerUser < 0 {
+		errs = append(errs, fmt.Errorf("max-active-keys-per-user must not be negative, got %d", c.MaxActiveKeysPerUser))
+	}
+
+	if c.MaxTierNamespaces < 0 {
+		errs = append(errs, fmt.Errorf("max-tier-namespaces must not be negative, got %d", c.MaxTierNamespaces))
+	}
+
+	if c.DefaultPageSize < 0 {
+		errs = append(errs, fmt.Errorf("default-page-size must not be negative, got %d", c.DefaultPageSize))
+	}
+
+	if c.MaxPageSize < 0 {
+		errs = append(errs, fmt.Errorf("max-page-size must not be negative, got %d", c.MaxPageSize))
+	} else if c.MaxPageSize > 0 && c.DefaultPageSize > c.MaxPageSize {
+		errs = append(errs, fmt.Errorf("default-page-size must not exceed max-page-size, got %d > %d", c.DefaultPageSize, c.MaxPageSize))
+	}
+
+	return errors.Join(errs...)
+}
+
+// getDuration returns the duration parsed from the environment variable, or the default value if it is not set.
+func getDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("failed to parse %s as duration: %w", key, err)
+	}
+
+	return d, nil
 }
@@ -0,0 +1,158 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+)
+
+func TestGroupMappingSet(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    config.GroupMapping
+		expectError bool
+	}{
+		{
+			name:     "empty value",
+			value:    "",
+			expected: config.GroupMapping{},
+		},
+		{
+			name:  "multiple entries and groups",
+			value: "llama-3-8b=team-a|team-b, granite-8b = team-c",
+			expected: config.GroupMapping{
+				"llama-3-8b": {"team-a", "team-b"},
+				"granite-8b": {"team-c"},
+			},
+		},
+		{
+			name:        "missing groups",
+			value:       "llama-3-8b=",
+			expectError: true,
+		},
+		{
+			name:        "missing separator",
+			value:       "llama-3-8b",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mapping config.GroupMapping
+			err := mapping.Set(tt.value)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mapping)
+		})
+	}
+}
+
+func TestGroupMappingString(t *testing.T) {
+	mapping := config.GroupMapping{
+		"b-model": {"team-b"},
+		"a-model": {"team-a", "team-c"},
+	}
+
+	assert.Equal(t, "a-model=team-a|team-c,b-model=team-b", mapping.String())
+}
+
+func TestConfigEffective(t *testing.T) {
+	cfg := &config.Config{Name: "maas", ExpirationGrace: 30 * time.Second}
+
+	settings := cfg.Effective()
+	assert.Equal(t, "maas", settings["name"])
+	assert.Equal(t, "30s", settings["expiration-grace"])
+	assert.Empty(t, settings["db-connection-url"], "unset credentials should not be reported as redacted")
+
+	cfg.DBConnectionURL = "postgresql://maas:s3cr3t@db:5432/maas"
+	assert.Equal(t, config.Redacted, cfg.Effective()["db-connection-url"])
+	assert.Equal(t, "postgresql://maas:s3cr3t@db:5432/maas", cfg.DBConnectionURL, "the config must be left untouched")
+}
+
+func TestConfigValidate_RejectsNegativeExpirationGrace(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       config.DefaultReadTimeout,
+		WriteTimeout:      config.DefaultWriteTimeout,
+		IdleTimeout:       config.DefaultIdleTimeout,
+		ExpirationGrace:   -time.Second,
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expiration-grace")
+
+	cfg.ExpirationGrace = 0
+	require.NoError(t, cfg.Validate(), "a zero grace window disables the tolerance")
+}
+
+func TestConfigValidate_PageSizes(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       config.DefaultReadTimeout,
+		WriteTimeout:      config.DefaultWriteTimeout,
+		IdleTimeout:       config.DefaultIdleTimeout,
+		DefaultPageSize:   config.DefaultPageSize,
+		MaxPageSize:       config.DefaultMaxPageSize,
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.DefaultPageSize = config.DefaultMaxPageSize + 1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "default-page-size must not exceed max-page-size")
+
+	cfg.DefaultPageSize = config.DefaultPageSize
+	cfg.MaxPageSize = -1
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max-page-size")
+}
+
+func TestConfigValidate_TokenExpirationJitterPercent(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout:            config.DefaultReadHeaderTimeout,
+		ReadTimeout:                  config.DefaultReadTimeout,
+		WriteTimeout:                 config.DefaultWriteTimeout,
+		IdleTimeout:                  config.DefaultIdleTimeout,
+		TokenExpirationJitterPercent: 10,
+	}
+	require.NoError(t, cfg.Validate())
+
+	for _, percent := range []int{-1, 100} {
+		cfg.TokenExpirationJitterPercent = percent
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token-expiration-jitter-percent")
+	}
+}
+
+func TestConfigValidate_RevokeConfirmationSecret(t *testing.T) {
+	cfg := &config.Config{
+		ReadHeaderTimeout: config.DefaultReadHeaderTimeout,
+		ReadTimeout:       config.DefaultReadTimeout,
+		WriteTimeout:      config.DefaultWriteTimeout,
+		IdleTimeout:       config.DefaultIdleTimeout,
+	}
+	require.NoError(t, cfg.Validate(), "an empty secret is generated at startup")
+
+	cfg.RevokeConfirmationSecret = "too-short"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "revoke-confirmation-secret")
+
+	cfg.RevokeConfirmationSecret = strings.Repeat("s", config.MinRevokeConfirmationSecretLength)
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, config.Redacted, cfg.Effective()["revoke-confirmation-secret"])
+}
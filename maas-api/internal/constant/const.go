@@ -14,8 +14,32 @@ const (
 	HeaderUsername = "X-MaaS-Username"
 	HeaderGroup    = "X-MaaS-Group"
 
+	// DefaultMaxGroups caps the number of groups accepted in the group header.
+	DefaultMaxGroups = 100
+
+	// HeaderRequestID carries the request ID reported in error responses.
+	HeaderRequestID = "X-Request-Id"
+
+	// HeaderSource names the client a request is made from, e.g. ui, cli or ci. It is recorded with the API keys created.
+	HeaderSource = "X-MaaS-Source"
+
+	// HeaderTimeout sets a deadline for handling the request, a duration such as 5s, bounded by the server.
+	HeaderTimeout = "X-MaaS-Timeout"
+
 	// LLMInferenceService annotation keys for model metadata.
 	AnnotationGenAIUseCase = "opendatahub.io/genai-use-case"
 	AnnotationDescription  = "openshift.io/description"
 	AnnotationDisplayName  = "openshift.io/display-name"
+
+	// AnnotationVisibility controls who can see the model in listings: public (default), internal or hidden.
+	AnnotationVisibility = "maas/visibility"
+
+	// AnnotationFamily sets the family of the model, e.g. llama, used to group its variants in listings.
+	AnnotationFamily = "maas/family"
+
+	// AnnotationOwnedBy overrides the owner displayed for the model, which defaults to its namespace.
+	AnnotationOwnedBy = "maas/owned-by"
+
+	// AnnotationStreaming set to false marks a model that does not support streaming (SSE) completions.
+	AnnotationStreaming = "maas/streaming"
 )
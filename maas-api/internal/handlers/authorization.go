@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// RequireAnyGroup rejects requests with 403 Forbidden unless the caller belongs to at least one of the given groups.
+// It relies on the user context set by token.Handler.ExtractUserInfo, so it must be chained after it.
+func RequireAnyGroup(groups []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userCtx, exists := c.Get("user")
+		if !exists {
+			apierror.Abort(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
+			return
+		}
+
+		user, ok := userCtx.(*token.UserContext)
+		if !ok {
+			apierror.Abort(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+			return
+		}
+
+		if !user.InAnyGroup(groups) {
+			apierror.Abort(c, http.StatusForbidden, apierror.CodeForbidden, "Insufficient permissions")
+			return
+		}
+
+		c.Next()
+	}
+}
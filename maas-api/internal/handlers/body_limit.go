@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+)
+
+// LimitRequestBody rejects requests with 413 Request Entity Too Large when their body exceeds maxBytes.
+// The body is read upfront, so that handlers binding it never see a truncated payload. A maxBytes of 0 or less
+// disables the limit.
+func LimitRequestBody(maxBytes int64) gin.HandlerFunc {
+	tooLarge := "Request body must not exceed " + strconv.FormatInt(maxBytes, 10) + " bytes"
+
+	return func(c *gin.Context) {
+		if maxBytes <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			apierror.Abort(c, http.StatusRequestEntityTooLarge, apierror.CodeRequestTooLarge, tooLarge)
+			return
+		}
+
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				apierror.Abort(c, http.StatusRequestEntityTooLarge, apierror.CodeRequestTooLarge, tooLarge)
+				return
+			}
+			apierror.Abort(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+	}
+}
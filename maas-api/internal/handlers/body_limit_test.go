@@ -0,0 +1,64 @@
+package handlers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+func TestLimitRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/echo", handlers.LimitRequestBody(16), func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, string(body))
+	})
+
+	post := func(t *testing.T, body string, knownLength bool) *httptest.ResponseRecorder {
+		t.Helper()
+
+		var reader io.Reader = strings.NewReader(body)
+		if !knownLength {
+			// Hide the length, as for chunked requests.
+			reader = io.MultiReader(reader)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/echo", reader)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		knownLength    bool
+		expectedStatus int
+	}{
+		{name: "within limit", body: `{"a": "b"}`, knownLength: true, expectedStatus: http.StatusOK},
+		{name: "at limit", body: strings.Repeat("x", 16), knownLength: true, expectedStatus: http.StatusOK},
+		{name: "declared length over limit", body: strings.Repeat("x", 17), knownLength: true, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "unknown length within limit", body: `{"a": "b"}`, expectedStatus: http.StatusOK},
+		{name: "unknown length over limit", body: strings.Repeat("x", 64), expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(t, tt.body, tt.knownLength)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String(), "the handler must see the whole body")
+			}
+		})
+	}
+}
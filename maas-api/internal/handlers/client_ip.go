@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// ParseTrustedProxies parses the IP addresses and CIDRs of the proxies trusted to report the client IP.
+func ParseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy CIDR %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy address %q: %w", entry, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ResolveClientIP resolves the IP of the client the request originates from and stores it in the request context,
+// see token.ClientIPFromContext. The Forwarded and X-Forwarded-For headers are only honored when the direct peer is
+// a trusted proxy: the client IP is then the rightmost forwarded address that does not belong to a trusted proxy.
+// Without trusted proxies, the client IP is the address of the direct peer.
+func ResolveClientIP(trustedProxies []netip.Prefix) gin.HandlerFunc {
+	trusted := func(addr netip.Addr) bool {
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		client, ok := peerAddr(c.Request.RemoteAddr)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		hops := forwardedFor(c.Request.Header)
+		for i := len(hops) - 1; i >= 0 && trusted(client); i-- {
+			addr, ok := parseForwardedAddr(hops[i])
+			if !ok {
+				break
+			}
+			client = addr
+		}
+
+		c.Request = c.Request.WithContext(token.WithClientIP(c.Request.Context(), client.String()))
+		c.Next()
+	}
+}
+
+func peerAddr(remoteAddr string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// forwardedFor returns the addresses the request was forwarded for, from the client to the last proxy.
+// The standard Forwarded header takes precedence over X-Forwarded-For.
+func forwardedFor(header http.Header) []string {
+	var hops []string
+	for _, value := range header.Values("Forwarded") {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(key, "for") {
+					hops = append(hops, strings.Trim(value, `"`))
+				}
+			}
+		}
+	}
+	if len(hops) > 0 {
+		return hops
+	}
+
+	for _, value := range header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// parseForwardedAddr parses a forwarded address, optionally with a port and IPv6 addresses in brackets.
+// Obfuscated identifiers and "unknown" are not addresses.
+func parseForwardedAddr(hop string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(hop, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	trustedProxies, err := handlers.ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(handlers.ResolveClientIP(trustedProxies))
+	router.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, token.ClientIPFromContext(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expectedIP string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:51234",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer cannot spoof X-Forwarded-For",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer cannot spoof Forwarded",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.1"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "trusted peer",
+			remoteAddr: "10.1.2.3:8443",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "chain of trusted proxies",
+			remoteAddr: "10.1.2.3:8443",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7, 10.4.5.6"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "addresses left of the first untrusted hop are ignored",
+			remoteAddr: "10.1.2.3:8443",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "Forwarded takes precedence",
+			remoteAddr: "10.1.2.3:8443",
+			headers: map[string]string{
+				"Forwarded":       `for="[2001:db8::cafe]:4711";proto=https, for=10.4.5.6`,
+				"X-Forwarded-For": "198.51.100.1",
+			},
+			expectedIP: "2001:db8::cafe",
+		},
+		{
+			name:       "trusted IPv6 peer",
+			remoteAddr: "[2001:db8::1]:8443",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "obfuscated hop",
+			remoteAddr: "10.1.2.3:8443",
+			headers:    map[string]string{"Forwarded": "for=unknown"},
+			expectedIP: "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedIP, w.Body.String())
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := handlers.ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.10 ", "10.1.2.3/16"})
+	require.NoError(t, err)
+	require.Len(t, prefixes, 3)
+	assert.Equal(t, "192.168.1.10/32", prefixes[1].String())
+	assert.Equal(t, "10.1.0.0/16", prefixes[2].String())
+
+	for _, entry := range []string{"10.0.0.0/33", "proxy.local", ""} {
+		_, err := handlers.ParseTrustedProxies([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compress gzip-encodes JSON responses of at least minSize bytes for clients accepting gzip.
+// Smaller responses are sent as they are. Responses that are flushed before reaching minSize, such as streamed
+// CSV exports or server-sent events, are passed through uncompressed from the first flush, so that they are
+// never held back. Every response carries Vary: Accept-Encoding, whichever encoding it ends up with, so that
+// caches do not serve one client's encoding to another. A minSize of 0 or less disables compression.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if minSize <= 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, minSize: minSize, status: http.StatusOK}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+		writer.finish()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of the request lists gzip with a non-zero quality.
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for encoding := range strings.SplitSeq(header, ",") {
+			name, params, _ := strings.Cut(encoding, ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+				continue
+			}
+			value, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+			if !found {
+				return true
+			}
+			quality, err := strconv.ParseFloat(value, 64)
+			return err == nil && quality > 0
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the response until it either reaches minSize bytes, at which point a JSON response
+// is compressed, or it is flushed or finished, in which case it is written as it is.
+type compressWriter struct {
+	gin.ResponseWriter
+
+	minSize int
+	status  int
+	buffer  bytes.Buffer
+
+	gzip        *gzip.Writer
+	passthrough bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.started() {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+// WriteHeaderNow defers the header until the encoding of the response is decided.
+func (w *compressWriter) WriteHeaderNow() {
+	if w.started() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *compressWriter) Status() int {
+	if w.started() {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gzip != nil:
+		return w.gzip.Write(data)
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() < w.minSize {
+		return len(data), nil
+	}
+
+	if err := w.start(isJSON(w.Header().Get("Content-Type"))); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends the buffered response uncompressed and passes the rest of it through, so that streamed
+// responses reach the client as they are produced.
+func (w *compressWriter) Flush() {
+	if !w.started() {
+		_ = w.start(false)
+	}
+	if w.gzip != nil {
+		_ = w.gzip.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// finish writes the response buffered so far, uncompressed as it is smaller than minSize,
+// or completes the gzip stream.
+func (w *compressWriter) finish() {
+	if w.gzip != nil {
+		_ = w.gzip.Close()
+		return
+	}
+	if !w.started() {
+		_ = w.start(false)
+	}
+}
+
+func (w *compressWriter) started() bool {
+	return w.gzip != nil || w.passthrough
+}
+
+// start sends the header and the buffered response, compressing them when compress is set and the response
+// has not already been encoded by the handler.
+func (w *compressWriter) start(compress bool) error {
+	header := w.Header()
+	compress = compress && header.Get("Content-Encoding") == "" && bodyAllowed(w.status)
+
+	if compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+		w.gzip = gzip.NewWriter(w.ResponseWriter)
+	} else {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.WriteHeaderNow()
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := w.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == gin.MIMEJSON
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
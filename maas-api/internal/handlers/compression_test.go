@@ -0,0 +1,156 @@
+package handlers_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openai/openai-go/v2/packages/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
+)
+
+const compressionMinSize = 1024
+
+func setupCompressionTestRouter(t *testing.T, modelCount int) *gin.Engine {
+	t.Helper()
+	testLogger := logger.Development()
+
+	const (
+		testGatewayName      = "test-gateway"
+		testGatewayNamespace = "test-gateway-ns"
+	)
+
+	scenarios := make([]fixtures.LLMTestScenario, 0, modelCount)
+	for i := range modelCount {
+		name := fmt.Sprintf("model-%03d", i)
+		scenarios = append(scenarios, fixtures.LLMTestScenario{
+			Name:             name,
+			Namespace:        "model-serving",
+			URL:              fixtures.PublicURL("http://" + name + ".model-serving.acme.com/v1"),
+			Ready:            true,
+			GatewayName:      testGatewayName,
+			GatewayNamespace: testGatewayNamespace,
+		})
+	}
+
+	router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{
+		Objects: fixtures.CreateLLMInferenceServices(scenarios...),
+	})
+	router.Use(handlers.Compress(compressionMinSize))
+
+	modelMgr, errMgr := models.NewManager(
+		testLogger,
+		clients.InferenceServiceLister,
+		clients.LLMInferenceServiceLister,
+		clients.HTTPRouteLister,
+		models.ManagerOptions{},
+		models.GatewayRef{Name: testGatewayName, Namespace: testGatewayNamespace},
+	)
+	require.NoError(t, errMgr)
+
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{})
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+	router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+	return router
+}
+
+func getWithEncoding(t *testing.T, router http.Handler, path, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	require.NoError(t, err)
+	req.Header.Set(constant.HeaderUsername, "compression-user")
+	req.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCompress_LargeModelList(t *testing.T) {
+	router := setupCompressionTestRouter(t, 50)
+
+	t.Run("GzipAccepted", func(t *testing.T) {
+		w := getWithEncoding(t, router, "/v1/models", "gzip, deflate")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+
+		var page pagination.Page[models.Model]
+		require.NoError(t, json.Unmarshal(body, &page))
+		assert.Len(t, page.Data, 50)
+		assert.Less(t, w.Body.Len(), len(body), "the compressed response should be smaller")
+	})
+
+	t.Run("NoAcceptEncoding", func(t *testing.T) {
+		w := getWithEncoding(t, router, "/v1/models", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding", "caches must not reuse the plain response for gzip clients")
+
+		var page pagination.Page[models.Model]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 50)
+	})
+
+	t.Run("GzipRefused", func(t *testing.T) {
+		w := getWithEncoding(t, router, "/v1/models", "gzip;q=0, identity")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+}
+
+func TestCompress_SmallResponse(t *testing.T) {
+	router := setupCompressionTestRouter(t, 1)
+
+	w := getWithEncoding(t, router, "/v1/models", "gzip")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "responses below the threshold are not compressed")
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+	var page pagination.Page[models.Model]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 1)
+}
+
+func TestCompress_StreamedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handlers.Compress(compressionMinSize))
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		for i := range 3 {
+			_, _ = fmt.Fprintf(c.Writer, "data: %d\n\n", i)
+			c.Writer.Flush()
+		}
+	})
+
+	w := getWithEncoding(t, router, "/events", "gzip")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "streamed responses must not be compressed")
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "data: 0\n\ndata: 1\n\ndata: 2\n\n", w.Body.String())
+}
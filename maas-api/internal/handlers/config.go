@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+)
+
+// EffectiveConfig is the response of GET /v1/admin/config.
+type EffectiveConfig struct {
+	// Settings maps the flag name of every setting to its effective value, see config.Config.Effective.
+	Settings map[string]string `json:"settings"`
+}
+
+// ConfigHandler serves the effective configuration of the server, for debugging.
+type ConfigHandler struct {
+	settings map[string]string
+}
+
+// NewConfigHandler creates a handler serving the configuration, which must not change afterwards.
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{settings: cfg.Effective()}
+}
+
+// GetConfig handles GET /v1/admin/config.
+//
+// Settings holding credentials, e.g. the database connection URL, are redacted.
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, EffectiveConfig{Settings: h.settings})
+}
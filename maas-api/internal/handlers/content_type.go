@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+)
+
+// RequireJSONContentType rejects POST, PUT and PATCH requests with 415 Unsupported Media Type unless their
+// Content-Type is application/json, so that bodies are never bound from forms or guessed formats. Requests without
+// a body are accepted whatever their Content-Type, e.g. token requests relying on the default expiration.
+// When enforce is false, all requests are accepted.
+func RequireJSONContentType(enforce bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enforce || !hasBodyMethod(c.Request.Method) || isEmptyBody(c.Request) {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != gin.MIMEJSON {
+			apierror.Abort(c, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+				"Content-Type must be "+gin.MIMEJSON)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func hasBodyMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// isEmptyBody reports whether the request is known to have no body. Bodies of unknown length, as for chunked
+// requests, are not considered empty.
+func isEmptyBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0
+}
@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+func TestRequireJSONContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(enforce bool) *gin.Engine {
+		router := gin.New()
+		echo := func(c *gin.Context) {
+			body, err := io.ReadAll(c.Request.Body)
+			require.NoError(t, err)
+			c.String(http.StatusOK, string(body))
+		}
+		router.POST("/echo", handlers.RequireJSONContentType(enforce), echo)
+		router.DELETE("/echo", handlers.RequireJSONContentType(enforce), echo)
+		return router
+	}
+
+	tests := []struct {
+		name           string
+		enforce        bool
+		method         string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "json", enforce: true, method: http.MethodPost, contentType: "application/json", body: `{"expiration": "1h"}`, expectedStatus: http.StatusOK},
+		{name: "json with charset", enforce: true, method: http.MethodPost, contentType: "Application/JSON; charset=utf-8", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "form", enforce: true, method: http.MethodPost, contentType: "application/x-www-form-urlencoded", body: `{"expiration": "1h"}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "text", enforce: true, method: http.MethodPost, contentType: "text/plain", body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "missing content type", enforce: true, method: http.MethodPost, body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed content type", enforce: true, method: http.MethodPost, contentType: "application/json;;", body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "empty body", enforce: true, method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "empty body with other content type", enforce: true, method: http.MethodPost, contentType: "text/plain", expectedStatus: http.StatusOK},
+		{name: "method without body", enforce: true, method: http.MethodDelete, contentType: "text/plain", body: "ignored", expectedStatus: http.StatusOK},
+		{name: "not enforced", method: http.MethodPost, contentType: "text/plain", body: `{}`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), tt.method, "/echo", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			newRouter(tt.enforce).ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "UNSUPPORTED_MEDIA_TYPE")
+			}
+		})
+	}
+}
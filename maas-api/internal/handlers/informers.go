@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+)
+
+// InformerStatusesFunc reports the state of the informer caches backing the handlers.
+type InformerStatusesFunc func() []config.InformerStatus
+
+// InformersHandler reports the state of the informer caches, to tell stale caches apart from
+// models missing from the cluster.
+type InformersHandler struct {
+	statuses InformerStatusesFunc
+}
+
+// NewInformersHandler creates a new informers handler.
+func NewInformersHandler(statuses InformerStatusesFunc) *InformersHandler {
+	return &InformersHandler{
+		statuses: statuses,
+	}
+}
+
+// InformersResponse is the body of GET /debug/informers.
+type InformersResponse struct {
+	Synced    bool                    `json:"synced"`
+	Informers []config.InformerStatus `json:"informers"`
+}
+
+// ListInformers handles GET /debug/informers.
+func (h *InformersHandler) ListInformers(c *gin.Context) {
+	statuses := h.statuses()
+
+	synced := true
+	for _, status := range statuses {
+		synced = synced && status.Synced
+	}
+
+	c.JSON(http.StatusOK, InformersResponse{
+		Synced:    synced,
+		Informers: statuses,
+	})
+}
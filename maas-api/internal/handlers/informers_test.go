@@ -0,0 +1,59 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+func TestListInformers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(t *testing.T, statuses []config.InformerStatus) handlers.InformersResponse {
+		t.Helper()
+
+		router := gin.New()
+		router.GET("/debug/informers", handlers.NewInformersHandler(func() []config.InformerStatus {
+			return statuses
+		}).ListInformers)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/debug/informers", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.InformersResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	llmIsvcs := config.InformerStatus{Resource: "llminferenceservices", Synced: true, LastSyncResourceVersion: "42", Objects: 3}
+
+	t.Run("all synced", func(t *testing.T) {
+		response := serve(t, []config.InformerStatus{
+			llmIsvcs,
+			{Resource: "httproutes", Synced: true, LastSyncResourceVersion: "41", Objects: 2},
+		})
+		assert.True(t, response.Synced)
+		require.Len(t, response.Informers, 2)
+		assert.Equal(t, llmIsvcs, response.Informers[0])
+	})
+
+	t.Run("one not synced", func(t *testing.T) {
+		response := serve(t, []config.InformerStatus{
+			llmIsvcs,
+			{Resource: "httproutes"},
+		})
+		assert.False(t, response.Synced)
+		assert.Len(t, response.Informers, 2)
+	})
+}
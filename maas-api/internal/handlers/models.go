@@ -1,28 +1,59 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/openai/openai-go/v2/packages/pagination"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
 
 // ModelsHandler handles model-related endpoints.
 type ModelsHandler struct {
 	modelMgr *models.Manager
+	options  ModelsHandlerOptions
 	logger   *logger.Logger
 }
 
-// NewModelsHandler creates a new models handler.
-func NewModelsHandler(log *logger.Logger, modelMgr *models.Manager) *ModelsHandler {
+// ModelsHandlerOptions configures which models a ModelsHandler lists, and to whom. The zero value lists the ready
+// models visible to the caller, along with the ones still waiting for their endpoint.
+type ModelsHandlerOptions struct {
+	// AdminGroups can additionally see models with internal visibility.
+	AdminGroups []string
+	// ModelAccessGroups restricts the models it lists to members of one of the groups they map to.
+	ModelAccessGroups map[string][]string
+	// ListNotReady lists models that are not ready, unless the request asks otherwise.
+	ListNotReady bool
+	// AuthzDebug adds the AuthzDebugHeader to the model lists returned to admins.
+	AuthzDebug bool
+	// ExcludeEndpointPending leaves models without a URL out of the listings, instead of listing them with
+	// endpointPending set so that clients can show them as being provisioned.
+	ExcludeEndpointPending bool
+}
+
+// AuthzDebugHeader is the response header summarizing the authorization checks of the listed models.
+const AuthzDebugHeader = "X-MaaS-Authz-Debug"
+
+// NewModelsHandler creates a new models handler listing the models of modelMgr as configured by options.
+func NewModelsHandler(log *logger.Logger, modelMgr *models.Manager, options ModelsHandlerOptions) *ModelsHandler {
 	if log == nil {
 		log = logger.Production()
 	}
 	return &ModelsHandler{
 		modelMgr: modelMgr,
+		options:  options,
 		logger:   log,
 	}
 }
@@ -34,28 +65,202 @@ func (h *ModelsHandler) ListModels(c *gin.Context) {
 		h.logger.Error("Failed to get available models",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve models"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve models")
 		return
 	}
 
 	c.JSON(http.StatusOK, pagination.Page[models.Model]{
 		Object: "list",
-		Data:   modelList,
+		Data:   h.withEndpoint(modelList),
 	})
 }
 
+// ListExplanation describes how the returned model list was derived from the models in the instance.
+type ListExplanation struct {
+	// TotalModels is the number of models served by this MaaS instance.
+	TotalModels int `json:"totalModels"`
+	// FilteredByAuthorization is the number of models the caller is not allowed to see.
+	FilteredByAuthorization int `json:"filteredByAuthorization"`
+	// FilteredByQuery is the number of models excluded by query parameters.
+	FilteredByQuery int `json:"filteredByQuery"`
+}
+
+// ExplainedModelList is the model list response returned when explain=true is requested.
+type ExplainedModelList struct {
+	Object  string          `json:"object"`
+	Data    []models.Model  `json:"data"`
+	Explain ListExplanation `json:"explain"`
+}
+
+// OpenAIModel is a model in the exact format of the OpenAI models API, without the fields added by MaaS.
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// OpenAIModelList is the model list response returned when openai=true is requested.
+type OpenAIModelList struct {
+	Object string        `json:"object"`
+	Data   []OpenAIModel `json:"data"`
+}
+
+// groupByFamily is the value of the group_by query parameter grouping models by family.
+const groupByFamily = "family"
+
+// UnknownFamily is the group of the models whose family cannot be determined.
+const UnknownFamily = "unknown"
+
+// GroupedModelList is the model list response returned when group_by=family is requested.
+// Models are grouped under their family, see models.Model.Family.
+type GroupedModelList struct {
+	Object  string                    `json:"object"`
+	Groups  map[string][]models.Model `json:"groups"`
+	Explain *ListExplanation          `json:"explain,omitempty"`
+}
+
 // ListLLMs handles GET /v1/models.
+//
+// Models that are not ready to serve requests are left out, unless the handler is configured to list them
+// or the include_not_ready query parameter asks for them.
+// With the optional ready_for query parameter, a duration such as 30s, only models that have been ready for at least
+// that long are listed, leaving out the ones still warming up.
+// With the optional explain=true query parameter, the response additionally carries counts
+// explaining why models were left out of the list.
+// With the optional group_by=family query parameter, models are grouped by family instead of listed flat.
+// With the optional debug=true query parameter, admins additionally get the gateway and HTTPRoute each model
+// is exposed through.
+// With the optional openai=true query parameter, models are listed with the fields of the OpenAI models API only,
+// for clients rejecting unknown fields.
+// When enabled, admins additionally get the AuthzDebugHeader counting the models checked against the access groups,
+// allowed and denied, without the per-model detail.
 func (h *ModelsHandler) ListLLMs(c *gin.Context) {
+	explain, ok := boolQuery(c, "explain", false)
+	if !ok {
+		return
+	}
+
+	debug, ok := boolQuery(c, "debug", false)
+	if !ok {
+		return
+	}
+	if debug && !h.isAdmin(c) {
+		apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, "debug details are only available to admins")
+		return
+	}
+
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != groupByFamily {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid value for group_by: "+groupBy)
+		return
+	}
+
+	includeNotReady, ok := boolQuery(c, "include_not_ready", h.options.ListNotReady)
+	if !ok {
+		return
+	}
+
+	readyFor, ok := durationQuery(c, "ready_for")
+	if !ok {
+		return
+	}
+
+	openAI, ok := boolQuery(c, "openai", false)
+	if !ok {
+		return
+	}
+	if openAI && (explain || debug || groupBy != "") {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest,
+			"openai=true lists the OpenAI fields only, it cannot be combined with explain, debug or group_by")
+		return
+	}
+
 	modelList, err := h.modelMgr.ListAvailableLLMs()
 	if err != nil {
 		h.logger.Error("Failed to get available LLM models",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Failed to retrieve LLM models",
-				"type":    "server_error",
-			}})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve LLM models")
+		return
+	}
+
+	modelList = h.withEndpoint(modelList)
+	total := len(modelList)
+	modelList = h.visibleModels(c, modelList)
+	checked := len(modelList)
+	modelList = h.authorizedModels(c, modelList)
+	authorized := len(modelList)
+
+	if h.options.AuthzDebug && h.isAdmin(c) {
+		c.Header(AuthzDebugHeader, fmt.Sprintf("checked=%d; allowed=%d; denied=%d", checked, authorized, checked-authorized))
+	}
+
+	if !includeNotReady {
+		modelList = slices.DeleteFunc(modelList, func(model models.Model) bool {
+			return !model.Ready
+		})
+	}
+
+	if readyFor > 0 {
+		now := time.Now()
+		modelList = slices.DeleteFunc(modelList, func(model models.Model) bool {
+			return !model.Ready || model.ReadySince == nil || now.Sub(*model.ReadySince) < readyFor
+		})
+	}
+
+	if !debug {
+		modelList = withoutExposure(modelList)
+	}
+
+	etag, err := modelListETag(currentUser(c), modelList, explain, groupBy, openAI, total, authorized)
+	if err != nil {
+		h.logger.Error("Failed to compute model list ETag",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve LLM models")
+		return
+	}
+	// The list depends on the caller, it must not be served to anyone else from a shared cache.
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	explanation := ListExplanation{
+		TotalModels:             total,
+		FilteredByAuthorization: total - authorized,
+		FilteredByQuery:         authorized - len(modelList),
+	}
+
+	if groupBy == groupByFamily {
+		grouped := GroupedModelList{
+			Object: "list",
+			Groups: modelsByFamily(modelList),
+		}
+		if explain {
+			grouped.Explain = &explanation
+		}
+		c.JSON(http.StatusOK, grouped)
+		return
+	}
+
+	if openAI {
+		c.JSON(http.StatusOK, OpenAIModelList{
+			Object: "list",
+			Data:   openAIModels(modelList),
+		})
+		return
+	}
+
+	if explain {
+		c.JSON(http.StatusOK, ExplainedModelList{
+			Object:  "list",
+			Data:    modelList,
+			Explain: explanation,
+		})
 		return
 	}
 
@@ -64,3 +269,221 @@ func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 		Data:   modelList,
 	})
 }
+
+// openAIModels returns the models with the fields of the OpenAI models API only.
+func openAIModels(modelList []models.Model) []OpenAIModel {
+	result := make([]OpenAIModel, 0, len(modelList))
+	for _, model := range modelList {
+		result = append(result, OpenAIModel{
+			ID:      model.ID,
+			Object:  "model",
+			Created: model.Created,
+			OwnedBy: model.OwnedBy,
+		})
+	}
+	return result
+}
+
+// modelsByFamily groups the models by family, models without a family are grouped under UnknownFamily.
+func modelsByFamily(modelList []models.Model) map[string][]models.Model {
+	groups := make(map[string][]models.Model)
+	for _, model := range modelList {
+		family := model.Family()
+		if family == "" {
+			family = UnknownFamily
+		}
+		groups[family] = append(groups[family], model)
+	}
+	return groups
+}
+
+// modelListETag returns a weak ETag of the model list returned to the user. It covers every field of the listed
+// models, regardless of their order, as well as the caller identity, the grouping, the OpenAI format and,
+// for explained lists, the explanation counts.
+func modelListETag(user *token.UserContext, modelList []models.Model, explain bool, groupBy string, openAI bool, total, authorized int) (string, error) {
+	encoded := make([]string, 0, len(modelList))
+	for _, model := range modelList {
+		data, err := json.Marshal(model)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode model %s: %w", model.ID, err)
+		}
+		encoded = append(encoded, string(data))
+	}
+	slices.Sort(encoded)
+
+	hash := sha256.New()
+	if user != nil {
+		groups := slices.Sorted(slices.Values(user.Groups))
+		fmt.Fprintf(hash, "%q %q\n", user.Username, groups)
+	}
+	if explain {
+		fmt.Fprintf(hash, "explain %d %d\n", total, authorized)
+	}
+	if groupBy != "" {
+		fmt.Fprintf(hash, "group_by %s\n", groupBy)
+	}
+	if openAI {
+		fmt.Fprintln(hash, "openai")
+	}
+	for _, model := range encoded {
+		fmt.Fprintln(hash, model)
+	}
+
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, nil
+}
+
+// etagMatches reports whether the If-None-Match header lists the ETag, using the weak comparison of RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// boolQuery parses the boolean query parameter, returning defaultValue when it is absent.
+// When the value is invalid, a 400 response is written and false is returned as second value.
+func boolQuery(c *gin.Context, name string, defaultValue bool) (bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid value for "+name+": "+value)
+		return false, false
+	}
+
+	return parsed, true
+}
+
+// durationQuery parses the non-negative duration query parameter, returning 0 when it is absent.
+// When the value is invalid, a 400 response is written and false is returned as second value.
+func durationQuery(c *gin.Context, name string) (time.Duration, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid value for "+name+": "+value)
+		return 0, false
+	}
+
+	return parsed, true
+}
+
+// ListCatalog handles GET /v1/catalog.
+//
+// The catalog is served without authentication, so models are listed as seen by an anonymous caller
+// and their URLs are stripped: browsing the catalog does not reveal where the models can be invoked.
+func (h *ModelsHandler) ListCatalog(c *gin.Context) {
+	modelList, err := h.modelMgr.ListAvailableLLMs()
+	if err != nil {
+		h.logger.Error("Failed to get model catalog",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to retrieve model catalog")
+		return
+	}
+
+	modelList = withoutExposure(h.visibleModels(c, h.withEndpoint(modelList)))
+	for i := range modelList {
+		modelList[i].URL = nil
+		modelList[i].Addresses = nil
+	}
+
+	c.JSON(http.StatusOK, pagination.Page[models.Model]{
+		Object: "list",
+		Data:   modelList,
+	})
+}
+
+// SummarizeModels handles GET /v1/admin/models/summary.
+//
+// Models of all namespaces are counted by state, without filtering them by visibility or access groups, and
+// reporting whether each is attached to one of the MaaS gateways. It gives admins a fleet-wide view of deployments.
+func (h *ModelsHandler) SummarizeModels(c *gin.Context) {
+	summaries, err := h.modelMgr.SummarizeLLMs()
+	if err != nil {
+		h.logger.Error("Failed to summarize models",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to summarize models")
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.Page[models.NamespaceSummary]{
+		Object: "list",
+		Data:   summaries,
+	})
+}
+
+// withoutExposure strips the debug details of how the models are exposed.
+func withoutExposure(modelList []models.Model) []models.Model {
+	for i := range modelList {
+		modelList[i].Exposure = nil
+	}
+	return modelList
+}
+
+// withEndpoint drops models whose endpoint is pending when the handler is configured to exclude them.
+// They are not counted as models of the instance until they get a URL.
+func (h *ModelsHandler) withEndpoint(modelList []models.Model) []models.Model {
+	if !h.options.ExcludeEndpointPending {
+		return modelList
+	}
+
+	return slices.DeleteFunc(modelList, func(model models.Model) bool {
+		return model.EndpointPending
+	})
+}
+
+// visibleModels drops models with internal visibility unless the caller belongs to one of the admin groups.
+func (h *ModelsHandler) visibleModels(c *gin.Context, modelList []models.Model) []models.Model {
+	if h.isAdmin(c) {
+		return modelList
+	}
+
+	return slices.DeleteFunc(modelList, func(model models.Model) bool {
+		return model.Visibility == models.VisibilityInternal
+	})
+}
+
+// authorizedModels drops models mapped to access groups the caller does not belong to.
+// Models without a mapping are kept, access to them is enforced at the gateway.
+func (h *ModelsHandler) authorizedModels(c *gin.Context, modelList []models.Model) []models.Model {
+	if len(h.options.ModelAccessGroups) == 0 {
+		return modelList
+	}
+
+	user := currentUser(c)
+	return slices.DeleteFunc(modelList, func(model models.Model) bool {
+		requiredGroups, restricted := h.options.ModelAccessGroups[model.ID]
+		return restricted && (user == nil || !user.InAnyGroup(requiredGroups))
+	})
+}
+
+func (h *ModelsHandler) isAdmin(c *gin.Context) bool {
+	user := currentUser(c)
+	return user != nil && user.InAnyGroup(h.options.AdminGroups)
+}
+
+// currentUser returns the user set on the context by token.Handler.ExtractUserInfo, or nil for anonymous requests.
+func currentUser(c *gin.Context) *token.UserContext {
+	userCtx, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+
+	user, ok := userCtx.(*token.UserContext)
+	if !ok {
+		return nil
+	}
+
+	return user
+}
@@ -2,19 +2,30 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	kservelistersv1alpha1 "github.com/kserve/kserve/pkg/client/listers/serving/v1alpha1"
 	"github.com/openai/openai-go/v2/packages/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/apis"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
 )
 
@@ -152,16 +163,17 @@ func TestListingModels(t *testing.T) {
 		clients.InferenceServiceLister,
 		clients.LLMInferenceServiceLister,
 		clients.HTTPRouteLister,
+		models.ManagerOptions{},
 		gatewayRef,
 	)
 	require.NoError(t, errMgr)
 
-	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr)
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{})
 	v1 := router.Group("/v1")
 	v1.GET("/models", modelsHandler.ListLLMs)
 
 	w := httptest.NewRecorder()
-	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/models", nil)
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/models?include_not_ready=true", nil)
 	require.NoError(t, err, "Failed to create request")
 
 	req.Header.Set("Authorization", "Bearer valid-token")
@@ -198,6 +210,11 @@ func TestListingModels(t *testing.T) {
 			assert.Equal(t, "model", string(actualModel.Object))
 			assert.Equal(t, mustParseURL(scenario.URL.String()), actualModel.URL)
 			assert.Equal(t, scenario.Ready, actualModel.Ready)
+			expectedState := models.StateNotReady
+			if scenario.Ready {
+				expectedState = models.StateReady
+			}
+			assert.Equal(t, expectedState, actualModel.State)
 
 			// Run scenario-specific assertions if defined
 			if scenario.AssertDetails != nil {
@@ -217,3 +234,833 @@ func mustParseURL(rawURL string) *apis.URL {
 	}
 	return u
 }
+
+// setupVisibilityTestRouter serves /v1/models for a set of models covering every visibility value.
+func setupVisibilityTestRouter(t *testing.T, adminGroup string, modelAccessGroups map[string][]string, authzDebug bool) http.Handler {
+	t.Helper()
+	testLogger := logger.Development()
+
+	const (
+		testGatewayName      = "test-gateway"
+		testGatewayNamespace = "test-gateway-ns"
+	)
+
+	scenario := func(name string, annotations map[string]string) fixtures.LLMTestScenario {
+		return fixtures.LLMTestScenario{
+			Name:             name,
+			Namespace:        "model-serving",
+			URL:              fixtures.PublicURL("http://" + name + ".model-serving.acme.com/v1"),
+			Ready:            true,
+			GatewayName:      testGatewayName,
+			GatewayNamespace: testGatewayNamespace,
+			Annotations:      annotations,
+		}
+	}
+
+	llmInferenceServices := fixtures.CreateLLMInferenceServices(
+		scenario("default-model", nil),
+		scenario("public-model", map[string]string{constant.AnnotationVisibility: "public"}),
+		scenario("internal-model", map[string]string{constant.AnnotationVisibility: "internal"}),
+		scenario("hidden-model", map[string]string{constant.AnnotationVisibility: "hidden"}),
+		scenario("unknown-visibility-model", map[string]string{constant.AnnotationVisibility: "secret"}),
+	)
+
+	router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{
+		Objects: llmInferenceServices,
+	})
+
+	modelMgr, errMgr := models.NewManager(
+		testLogger,
+		clients.InferenceServiceLister,
+		clients.LLMInferenceServiceLister,
+		clients.HTTPRouteLister,
+		models.ManagerOptions{},
+		models.GatewayRef{Name: testGatewayName, Namespace: testGatewayNamespace},
+	)
+	require.NoError(t, errMgr)
+
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{
+		AdminGroups:       []string{adminGroup},
+		ModelAccessGroups: modelAccessGroups,
+		AuthzDebug:        authzDebug,
+	})
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+	router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+	router.GET("/v1/catalog", modelsHandler.ListCatalog)
+
+	return router
+}
+
+func listModels(t *testing.T, router http.Handler, path, groups string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	require.NoError(t, err)
+	req.Header.Set(constant.HeaderUsername, "visibility-user")
+	req.Header.Set(constant.HeaderGroup, groups)
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestListingModelsVisibility(t *testing.T) {
+	const adminGroup = "maas-admins"
+	router := setupVisibilityTestRouter(t, adminGroup, nil, false)
+
+	tests := []struct {
+		name           string
+		groups         string
+		expectedModels []string
+	}{
+		{
+			name:           "regular user sees only public models",
+			groups:         `["system:authenticated"]`,
+			expectedModels: []string{"default-model", "public-model", "unknown-visibility-model"},
+		},
+		{
+			name:           "admin sees public and internal models",
+			groups:         `["system:authenticated","` + adminGroup + `"]`,
+			expectedModels: []string{"default-model", "public-model", "unknown-visibility-model", "internal-model"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := listModels(t, router, "/v1/models", tt.groups)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response pagination.Page[models.Model]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			actualModels := make([]string, 0, len(response.Data))
+			for _, model := range response.Data {
+				actualModels = append(actualModels, model.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedModels, actualModels)
+			assert.NotContains(t, strings.ToLower(w.Body.String()), `"visibility":`, "visibility must not be exposed in the response")
+		})
+	}
+}
+
+func TestListingModelsExplain(t *testing.T) {
+	const adminGroup = "maas-admins"
+	router := setupVisibilityTestRouter(t, adminGroup, nil, false)
+
+	tests := []struct {
+		name            string
+		groups          string
+		expectedListed  int
+		expectedExplain handlers.ListExplanation
+	}{
+		{
+			name:           "regular user has internal models filtered by authorization",
+			groups:         `["system:authenticated"]`,
+			expectedListed: 3,
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:             4,
+				FilteredByAuthorization: 1,
+			},
+		},
+		{
+			name:           "admin has nothing filtered",
+			groups:         `["` + adminGroup + `"]`,
+			expectedListed: 4,
+			expectedExplain: handlers.ListExplanation{
+				TotalModels: 4,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := listModels(t, router, "/v1/models?explain=true", tt.groups)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ExplainedModelList
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			assert.Equal(t, "list", response.Object)
+			assert.Len(t, response.Data, tt.expectedListed)
+			assert.Equal(t, tt.expectedExplain, response.Explain)
+		})
+	}
+
+	t.Run("default response has no explanation", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotContains(t, response, "explain")
+	})
+
+	t.Run("invalid explain value", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?explain=maybe", `["system:authenticated"]`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, apierror.CodeInvalidRequest, response.Error.Code)
+		assert.Equal(t, "invalid_request_error", response.Error.Type)
+		assert.Equal(t, "invalid value for explain: maybe", response.Error.Message)
+		assert.NotEmpty(t, response.Error.RequestID)
+	})
+}
+
+func TestListingModelsDebug(t *testing.T) {
+	const adminGroup = "maas-admins"
+	router := setupVisibilityTestRouter(t, adminGroup, nil, false)
+
+	t.Run("admin gets the exposure of the models", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?debug=true", `["`+adminGroup+`"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response pagination.Page[models.Model]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		require.NotEmpty(t, response.Data)
+		for _, model := range response.Data {
+			assert.Equal(t, &models.Exposure{
+				Source:  models.ExposureGatewayRef,
+				Gateway: models.ObjectRef{Namespace: "test-gateway-ns", Name: "test-gateway"},
+			}, model.Exposure, model.ID)
+		}
+	})
+
+	t.Run("exposure is omitted without debug", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models", `["`+adminGroup+`"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"exposure"`)
+	})
+
+	t.Run("regular user is forbidden", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?debug=true", `["system:authenticated"]`)
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, apierror.CodeForbidden, response.Error.Code)
+	})
+
+	t.Run("catalog never has the exposure", func(t *testing.T) {
+		w := listModels(t, router, "/v1/catalog?debug=true", `["`+adminGroup+`"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"exposure"`)
+	})
+}
+
+func TestListingModelsOpenAI(t *testing.T) {
+	router := setupVisibilityTestRouter(t, "maas-admins", nil, false)
+
+	t.Run("strict response has the OpenAI fields only", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?openai=true", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.ElementsMatch(t, []string{"object", "data"}, slices.Collect(maps.Keys(response)))
+		assert.JSONEq(t, `"list"`, string(response["object"]))
+
+		var data []map[string]any
+		require.NoError(t, json.Unmarshal(response["data"], &data))
+		require.NotEmpty(t, data)
+		for _, model := range data {
+			assert.ElementsMatch(t, []string{"id", "object", "created", "owned_by"}, slices.Collect(maps.Keys(model)), model)
+			assert.Equal(t, "model", model["object"])
+			assert.NotEmpty(t, model["id"])
+		}
+	})
+
+	t.Run("extended response stays the default", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"url"`)
+		assert.Contains(t, w.Body.String(), `"ready"`)
+	})
+
+	t.Run("strict and extended responses have distinct ETags", func(t *testing.T) {
+		strict := listModels(t, router, "/v1/models?openai=true", `["system:authenticated"]`)
+		extended := listModels(t, router, "/v1/models", `["system:authenticated"]`)
+		assert.NotEqual(t, extended.Header().Get("ETag"), strict.Header().Get("ETag"))
+	})
+
+	t.Run("cannot be combined with extensions", func(t *testing.T) {
+		for _, query := range []string{"explain=true", "group_by=family", "debug=true"} {
+			w := listModels(t, router, "/v1/models?openai=true&"+query, `["maas-admins"]`)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
+
+func TestListingModelsGroupByFamily(t *testing.T) {
+	testLogger := logger.Development()
+
+	const (
+		testGatewayName      = "test-gateway"
+		testGatewayNamespace = "test-gateway-ns"
+	)
+
+	scenario := func(name string, annotations map[string]string) fixtures.LLMTestScenario {
+		return fixtures.LLMTestScenario{
+			Name:             name,
+			Namespace:        "model-serving",
+			URL:              fixtures.PublicURL("http://" + name + ".model-serving.acme.com/v1"),
+			Ready:            true,
+			GatewayName:      testGatewayName,
+			GatewayNamespace: testGatewayNamespace,
+			Annotations:      annotations,
+		}
+	}
+
+	router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{
+		Objects: fixtures.CreateLLMInferenceServices(
+			scenario("llama-3-8b", nil),
+			scenario("llama3-70b-instruct", nil),
+			scenario("support-assistant", map[string]string{constant.AnnotationFamily: "Llama"}),
+			scenario("granite-8b-code", nil),
+			scenario("7b-chat", nil),
+		),
+	})
+
+	modelMgr, errMgr := models.NewManager(
+		testLogger,
+		clients.InferenceServiceLister,
+		clients.LLMInferenceServiceLister,
+		clients.HTTPRouteLister,
+		models.ManagerOptions{},
+		models.GatewayRef{Name: testGatewayName, Namespace: testGatewayNamespace},
+	)
+	require.NoError(t, errMgr)
+
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{})
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+	router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+	t.Run("groups variants under their family", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?group_by=family", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response handlers.GroupedModelList
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "list", response.Object)
+		assert.Nil(t, response.Explain)
+
+		families := make(map[string][]string)
+		for family, group := range response.Groups {
+			for _, model := range group {
+				families[family] = append(families[family], model.ID)
+			}
+		}
+		assert.Len(t, families, 3)
+		assert.ElementsMatch(t, []string{"llama-3-8b", "llama3-70b-instruct", "support-assistant"}, families["llama"],
+			"the family annotation takes precedence over the model ID")
+		assert.ElementsMatch(t, []string{"granite-8b-code"}, families["granite"])
+		assert.ElementsMatch(t, []string{"7b-chat"}, families[handlers.UnknownFamily])
+	})
+
+	t.Run("flat list by default", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotContains(t, response, "groups")
+		assert.Len(t, response["data"], 5)
+	})
+
+	t.Run("grouping changes the ETag", func(t *testing.T) {
+		flat := listModels(t, router, "/v1/models", `["system:authenticated"]`)
+		grouped := listModels(t, router, "/v1/models?group_by=family", `["system:authenticated"]`)
+		assert.NotEqual(t, flat.Header().Get("ETag"), grouped.Header().Get("ETag"))
+	})
+
+	t.Run("invalid group_by value", func(t *testing.T) {
+		w := listModels(t, router, "/v1/models?group_by=provider", `["system:authenticated"]`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid value for group_by: provider", response.Error.Message)
+	})
+}
+
+func TestListingCatalog(t *testing.T) {
+	const adminGroup = "maas-admins"
+	router := setupVisibilityTestRouter(t, adminGroup, nil, false)
+
+	// Identity headers are ignored by the catalog, even when they claim admin group membership.
+	w := listModels(t, router, "/v1/catalog", `["`+adminGroup+`"]`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response pagination.Page[models.Model]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	actualModels := make([]string, 0, len(response.Data))
+	for _, model := range response.Data {
+		actualModels = append(actualModels, model.ID)
+		assert.Nil(t, model.URL, "catalog must not expose model URLs")
+	}
+	assert.ElementsMatch(t, []string{"default-model", "public-model", "unknown-visibility-model"}, actualModels)
+	assert.NotContains(t, w.Body.String(), "acme.com", "catalog must not expose model URLs")
+}
+
+func TestListingModelsAccessGroups(t *testing.T) {
+	const adminGroup = "maas-admins"
+	router := setupVisibilityTestRouter(t, adminGroup, map[string][]string{
+		"public-model":   {"team-a", "team-b"},
+		"internal-model": {"team-a"},
+	}, false)
+
+	tests := []struct {
+		name            string
+		groups          string
+		expectedModels  []string
+		expectedExplain handlers.ListExplanation
+	}{
+		{
+			name:           "member of a mapped group is allowed",
+			groups:         `["system:authenticated","team-b"]`,
+			expectedModels: []string{"default-model", "public-model", "unknown-visibility-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:             4,
+				FilteredByAuthorization: 1,
+			},
+		},
+		{
+			name:           "caller outside the mapped groups is denied",
+			groups:         `["system:authenticated"]`,
+			expectedModels: []string{"default-model", "unknown-visibility-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:             4,
+				FilteredByAuthorization: 2,
+			},
+		},
+		{
+			name:           "visibility and access groups both apply",
+			groups:         `["` + adminGroup + `"]`,
+			expectedModels: []string{"default-model", "unknown-visibility-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:             4,
+				FilteredByAuthorization: 2,
+			},
+		},
+		{
+			name:           "admin in the mapped group sees the internal model",
+			groups:         `["` + adminGroup + `","team-a"]`,
+			expectedModels: []string{"default-model", "public-model", "internal-model", "unknown-visibility-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels: 4,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := listModels(t, router, "/v1/models?explain=true", tt.groups)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ExplainedModelList
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			actualModels := make([]string, 0, len(response.Data))
+			for _, model := range response.Data {
+				actualModels = append(actualModels, model.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedModels, actualModels)
+			assert.Equal(t, tt.expectedExplain, response.Explain)
+		})
+	}
+}
+
+// setupReadinessTestRouter serves /v1/models for a ready and a not ready model.
+func TestListingModelsAuthzDebugHeader(t *testing.T) {
+	const adminGroup = "maas-admins"
+	accessGroups := map[string][]string{
+		"public-model":   {"team-a", "team-b"},
+		"internal-model": {"team-a"},
+	}
+
+	t.Run("admin gets the summary when enabled", func(t *testing.T) {
+		router := setupVisibilityTestRouter(t, adminGroup, accessGroups, true)
+
+		w := listModels(t, router, "/v1/models", `["`+adminGroup+`","team-b"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "checked=4; allowed=3; denied=1", w.Header().Get(handlers.AuthzDebugHeader))
+	})
+
+	t.Run("not sent to other users", func(t *testing.T) {
+		router := setupVisibilityTestRouter(t, adminGroup, accessGroups, true)
+
+		w := listModels(t, router, "/v1/models", `["system:authenticated","team-b"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Values(handlers.AuthzDebugHeader))
+	})
+
+	t.Run("not sent when disabled", func(t *testing.T) {
+		router := setupVisibilityTestRouter(t, adminGroup, accessGroups, false)
+
+		w := listModels(t, router, "/v1/models", `["`+adminGroup+`","team-b"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Values(handlers.AuthzDebugHeader))
+	})
+}
+
+const (
+	readinessTestGatewayName      = "test-gateway"
+	readinessTestGatewayNamespace = "test-gateway-ns"
+)
+
+func readinessScenario(name string, ready bool) fixtures.LLMTestScenario {
+	return fixtures.LLMTestScenario{
+		Name:             name,
+		Namespace:        "model-serving",
+		URL:              fixtures.PublicURL("http://" + name + ".model-serving.acme.com/v1"),
+		Ready:            ready,
+		GatewayName:      readinessTestGatewayName,
+		GatewayNamespace: readinessTestGatewayNamespace,
+	}
+}
+
+func setupReadinessTestRouter(t *testing.T, listNotReady bool) http.Handler {
+	t.Helper()
+	return setupReadinessTestRouterWithObjects(t, listNotReady, fixtures.CreateLLMInferenceServices(
+		readinessScenario("ready-model", true),
+		readinessScenario("starting-model", false),
+	))
+}
+
+func setupReadinessTestRouterWithObjects(t *testing.T, listNotReady bool, objects []runtime.Object) http.Handler {
+	t.Helper()
+	testLogger := logger.Development()
+
+	router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{
+		Objects: objects,
+	})
+
+	modelMgr, errMgr := models.NewManager(
+		testLogger,
+		clients.InferenceServiceLister,
+		clients.LLMInferenceServiceLister,
+		clients.HTTPRouteLister,
+		models.ManagerOptions{},
+		models.GatewayRef{Name: readinessTestGatewayName, Namespace: readinessTestGatewayNamespace},
+	)
+	require.NoError(t, errMgr)
+
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{ListNotReady: listNotReady})
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+	router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+	return router
+}
+
+func TestListingModelsReadiness(t *testing.T) {
+	tests := []struct {
+		name            string
+		listNotReady    bool
+		path            string
+		expectedModels  []string
+		expectedExplain handlers.ListExplanation
+	}{
+		{
+			name:           "not ready models are excluded by default",
+			path:           "/v1/models?explain=true",
+			expectedModels: []string{"ready-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:     2,
+				FilteredByQuery: 1,
+			},
+		},
+		{
+			name:           "not ready models are included on request",
+			path:           "/v1/models?explain=true&include_not_ready=true",
+			expectedModels: []string{"ready-model", "starting-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels: 2,
+			},
+		},
+		{
+			name:           "configured to list not ready models",
+			listNotReady:   true,
+			path:           "/v1/models?explain=true",
+			expectedModels: []string{"ready-model", "starting-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels: 2,
+			},
+		},
+		{
+			name:           "configured to list not ready models but excluded on request",
+			listNotReady:   true,
+			path:           "/v1/models?explain=true&include_not_ready=false",
+			expectedModels: []string{"ready-model"},
+			expectedExplain: handlers.ListExplanation{
+				TotalModels:     2,
+				FilteredByQuery: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupReadinessTestRouter(t, tt.listNotReady)
+
+			w := listModels(t, router, tt.path, `["system:authenticated"]`)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ExplainedModelList
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			actualModels := make([]string, 0, len(response.Data))
+			for _, model := range response.Data {
+				actualModels = append(actualModels, model.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedModels, actualModels)
+			assert.Equal(t, tt.expectedExplain, response.Explain)
+		})
+	}
+
+	t.Run("invalid include_not_ready value", func(t *testing.T) {
+		router := setupReadinessTestRouter(t, false)
+
+		w := listModels(t, router, "/v1/models?include_not_ready=maybe", `["system:authenticated"]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestListingModelsReadyFor(t *testing.T) {
+	readyAgo := func(obj runtime.Object, ago time.Duration) runtime.Object {
+		llm, ok := obj.(*kservev1alpha1.LLMInferenceService)
+		require.True(t, ok)
+		for i := range llm.Status.Conditions {
+			if llm.Status.Conditions[i].Type == apis.ConditionReady {
+				llm.Status.Conditions[i].LastTransitionTime = apis.VolatileTime{Inner: metav1.NewTime(time.Now().Add(-ago))}
+			}
+		}
+		return llm
+	}
+
+	objects := fixtures.CreateLLMInferenceServices(
+		readinessScenario("warm-model", true),
+		readinessScenario("warming-model", true),
+		readinessScenario("starting-model", false),
+	)
+	objects[0] = readyAgo(objects[0], 10*time.Minute)
+	objects[1] = readyAgo(objects[1], 5*time.Second)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedModels []string
+	}{
+		{
+			name:           "all ready models without ready_for",
+			path:           "/v1/models",
+			expectedModels: []string{"warm-model", "warming-model"},
+		},
+		{
+			name:           "models ready too recently are left out",
+			path:           "/v1/models?ready_for=30s",
+			expectedModels: []string{"warm-model"},
+		},
+		{
+			name:           "models ready for a shorter time than all of them",
+			path:           "/v1/models?ready_for=1s",
+			expectedModels: []string{"warm-model", "warming-model"},
+		},
+		{
+			name:           "no model ready for long enough",
+			path:           "/v1/models?ready_for=1h",
+			expectedModels: []string{},
+		},
+		{
+			name:           "not ready models are left out even when included",
+			path:           "/v1/models?ready_for=30s&include_not_ready=true",
+			expectedModels: []string{"warm-model"},
+		},
+	}
+
+	router := setupReadinessTestRouterWithObjects(t, false, objects)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := listModels(t, router, tt.path, `["system:authenticated"]`)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response pagination.Page[models.Model]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			actualModels := make([]string, 0, len(response.Data))
+			for _, model := range response.Data {
+				actualModels = append(actualModels, model.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedModels, actualModels)
+		})
+	}
+
+	for _, value := range []string{"soon", "-30s"} {
+		t.Run("invalid ready_for value "+value, func(t *testing.T) {
+			w := listModels(t, router, "/v1/models?ready_for="+value, `["system:authenticated"]`)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestListingModelsEndpointPending(t *testing.T) {
+	testLogger := logger.Development()
+
+	const (
+		testGatewayName      = "test-gateway"
+		testGatewayNamespace = "test-gateway-ns"
+	)
+
+	setupRouter := func(t *testing.T, excludePending bool) http.Handler {
+		t.Helper()
+
+		router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{
+			Objects: fixtures.CreateLLMInferenceServices(
+				fixtures.LLMTestScenario{
+					Name:             "routed-model",
+					Namespace:        "model-serving",
+					URL:              fixtures.PublicURL("http://routed-model.model-serving.acme.com/v1"),
+					Ready:            true,
+					GatewayName:      testGatewayName,
+					GatewayNamespace: testGatewayNamespace,
+				},
+				fixtures.LLMTestScenario{
+					Name:             "pending-model",
+					Namespace:        "model-serving",
+					Ready:            true,
+					GatewayName:      testGatewayName,
+					GatewayNamespace: testGatewayNamespace,
+				},
+			),
+		})
+
+		modelMgr, errMgr := models.NewManager(
+			testLogger,
+			clients.InferenceServiceLister,
+			clients.LLMInferenceServiceLister,
+			clients.HTTPRouteLister,
+			models.ManagerOptions{},
+			models.GatewayRef{Name: testGatewayName, Namespace: testGatewayNamespace},
+		)
+		require.NoError(t, errMgr)
+
+		modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{ExcludeEndpointPending: excludePending})
+		tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+		router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+		return router
+	}
+
+	t.Run("models without URL are listed as pending by default", func(t *testing.T) {
+		w := listModels(t, setupRouter(t, false), "/v1/models?explain=true", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ExplainedModelList
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, 2, response.Explain.TotalModels)
+
+		pending := make(map[string]bool, len(response.Data))
+		for _, model := range response.Data {
+			pending[model.ID] = model.EndpointPending
+			assert.Equal(t, model.EndpointPending, model.URL == nil, "model %s", model.ID)
+		}
+		assert.Equal(t, map[string]bool{"routed-model": false, "pending-model": true}, pending)
+	})
+
+	t.Run("models without URL are excluded when configured", func(t *testing.T) {
+		w := listModels(t, setupRouter(t, true), "/v1/models?explain=true", `["system:authenticated"]`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ExplainedModelList
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "routed-model", response.Data[0].ID)
+		assert.False(t, response.Data[0].EndpointPending)
+		assert.Equal(t, 1, response.Explain.TotalModels)
+	})
+}
+
+func TestListingModelsETag(t *testing.T) {
+	testLogger := logger.Development()
+
+	const (
+		testGatewayName      = "test-gateway"
+		testGatewayNamespace = "test-gateway-ns"
+	)
+
+	llmService := func(ready bool) *kservev1alpha1.LLMInferenceService {
+		return fixtures.CreateLLMInferenceService("etag-model", "model-serving", ready,
+			fixtures.WithURL(fixtures.PublicURL("http://etag-model.model-serving.acme.com/v1")),
+			fixtures.WithGatewaySpec(testGatewayName, testGatewayNamespace),
+		)
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(llmService(true)))
+
+	router, clients := fixtures.SetupTestServer(t, fixtures.TestServerConfig{})
+	modelMgr, errMgr := models.NewManager(
+		testLogger,
+		clients.InferenceServiceLister,
+		kservelistersv1alpha1.NewLLMInferenceServiceLister(indexer),
+		clients.HTTPRouteLister,
+		models.ManagerOptions{},
+		models.GatewayRef{Name: testGatewayName, Namespace: testGatewayNamespace},
+	)
+	require.NoError(t, errMgr)
+
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, handlers.ModelsHandlerOptions{ListNotReady: true})
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant, nil, token.HandlerOptions{})
+	router.GET("/v1/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+	conditionalList := func(t *testing.T, username, etag string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/models", nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, username)
+		req.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		router.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := conditionalList(t, "etag-user", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	t.Run("UnchangedList", func(t *testing.T) {
+		w := conditionalList(t, "etag-user", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("OtherCaller", func(t *testing.T) {
+		w := conditionalList(t, "another-user", etag)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"), "the ETag must depend on the caller")
+	})
+
+	t.Run("ReadinessChanged", func(t *testing.T) {
+		require.NoError(t, indexer.Update(llmService(false)))
+
+		w := conditionalList(t, "etag-user", etag)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+		var page pagination.Page[models.Model]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.False(t, page.Data[0].Ready)
+	})
+}
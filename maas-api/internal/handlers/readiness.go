@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cacheRetryAfterSeconds is the Retry-After hint returned while the service is not ready.
+const cacheRetryAfterSeconds = 5
+
+// SyncedFunc reports whether the informer caches backing the handlers are synced.
+type SyncedFunc func() bool
+
+// CheckFunc reports why the service is not ready to serve requests, or nil when it is.
+type CheckFunc func() error
+
+// ReadinessHandler handles readiness check endpoints.
+type ReadinessHandler struct {
+	cachesSynced SyncedFunc
+	checks       []CheckFunc
+}
+
+// NewReadinessHandler creates a new readiness handler. The checks are run once the caches are synced,
+// the service is only ready when all of them pass.
+func NewReadinessHandler(cachesSynced SyncedFunc, checks ...CheckFunc) *ReadinessHandler {
+	return &ReadinessHandler{
+		cachesSynced: cachesSynced,
+		checks:       checks,
+	}
+}
+
+// ReadinessCheck handles GET /ready.
+func (h *ReadinessHandler) ReadinessCheck(c *gin.Context) {
+	if !h.cachesSynced() {
+		c.Header("Retry-After", strconv.Itoa(cacheRetryAfterSeconds))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "not ready",
+			"caches": "not synced",
+		})
+		return
+	}
+
+	for _, check := range h.checks {
+		if err := check(); err != nil {
+			c.Header("Retry-After", strconv.Itoa(cacheRetryAfterSeconds))
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "not ready",
+				"caches": "synced",
+				"reason": err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ready",
+		"caches": "synced",
+	})
+}
@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
+)
+
+func TestReadinessCheck_CachesNotSynced(t *testing.T) {
+	router, _ := fixtures.SetupTestServer(t, fixtures.TestServerConfig{})
+
+	var synced atomic.Bool
+	router.GET("/ready", handlers.NewReadinessHandler(synced.Load).ReadinessCheck)
+
+	serve := func(t *testing.T) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/ready", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("not synced", func(t *testing.T) {
+		synced.Store(false)
+
+		w := serve(t)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"not synced"`)
+	})
+
+	t.Run("synced", func(t *testing.T) {
+		synced.Store(true)
+
+		w := serve(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"ready"`)
+	})
+}
+
+func TestReadinessCheck_Gateways(t *testing.T) {
+	gatewayRefs := []models.GatewayRef{{Name: "maas-default-gateway", Namespace: "openshift-ingress"}}
+
+	serve := func(t *testing.T, gatewayLister gatewaylisters.GatewayLister) *httptest.ResponseRecorder {
+		t.Helper()
+
+		router, _ := fixtures.SetupTestServer(t, fixtures.TestServerConfig{})
+		checkGateways := func() error { return models.CheckGatewaysExist(gatewayLister, gatewayRefs) }
+		router.GET("/ready", handlers.NewReadinessHandler(func() bool { return true }, checkGateways).ReadinessCheck)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/ready", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("missing gateway", func(t *testing.T) {
+		w := serve(t, fixtures.NewGatewayLister(
+			&gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "maas-default-gatway", Namespace: "openshift-ingress"}},
+		))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		var response map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "not ready", response["status"])
+		assert.Contains(t, response["reason"], "openshift-ingress/maas-default-gateway")
+	})
+
+	t.Run("present gateway", func(t *testing.T) {
+		w := serve(t, fixtures.NewGatewayLister(
+			&gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "maas-default-gateway", Namespace: "openshift-ingress"}},
+		))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"ready"`)
+	})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+// RequestTimeout sets the deadline clients request in the timeout header, a duration such as 5s, on the context of
+// the request. Deadlines longer than maxTimeout are shortened to it. When the deadline is exceeded and the handler
+// failed, its response is replaced with 504 Gateway Timeout; the handler stops early only if it honors the context.
+// A handler succeeding after the deadline still gets its response sent: its changes, e.g. an issued token, are
+// committed and would otherwise be lost to the client. Requests without the header are not bounded, and a maxTimeout
+// of 0 or less ignores the header.
+//
+// The response is buffered until the handler returns, the middleware must not be used for streamed responses.
+func RequestTimeout(maxTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := strings.TrimSpace(c.GetHeader(constant.HeaderTimeout))
+		if maxTimeout <= 0 || value == "" {
+			c.Next()
+			return
+		}
+
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			apierror.Abort(c, http.StatusBadRequest, apierror.CodeInvalidRequest,
+				"invalid value for "+constant.HeaderTimeout+": "+value+", expected a positive duration such as 5s")
+			return
+		}
+		timeout = min(timeout, maxTimeout)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, header: c.Writer.Header().Clone(), status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && writer.status >= http.StatusBadRequest {
+			apierror.Write(c, http.StatusGatewayTimeout, apierror.CodeTimeout, "Request did not complete within "+timeout.String())
+			return
+		}
+		writer.flush()
+	}
+}
+
+// timeoutWriter buffers the header and body of the response, so that they can be discarded when the handler failed
+// after the deadline of the request.
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	header  http.Header
+	status  int
+	written bool
+	buffer  bytes.Buffer
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.buffer.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.buffer.WriteString(s)
+}
+
+func (w *timeoutWriter) Status() int {
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.buffer.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op, the response is only sent once the handler returns.
+func (w *timeoutWriter) Flush() {}
+
+// flush sends the buffered response.
+func (w *timeoutWriter) flush() {
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		if _, kept := w.header[key]; !kept {
+			header.Del(key)
+		}
+	}
+	maps.Copy(header, w.header)
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buffer.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(w.buffer.Bytes())
+}
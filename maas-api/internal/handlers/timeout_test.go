@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handlers.RequestTimeout(time.Second))
+	// The handler takes 200ms, or fails earlier if the deadline of the request is exceeded.
+	router.GET("/slow", func(c *gin.Context) {
+		c.Header("X-Handler", "slow")
+		select {
+		case <-time.After(200 * time.Millisecond):
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "canceled"})
+		}
+	})
+	// The handler ignores the deadline and succeeds once its work is done, e.g. after issuing a token.
+	router.POST("/slow", func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+		c.Header("X-Handler", "slow")
+		c.JSON(http.StatusCreated, gin.H{"token": "issued"})
+	})
+
+	get := func(t *testing.T, timeout string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/slow", nil)
+		require.NoError(t, err)
+		if timeout != "" {
+			req.Header.Set(constant.HeaderTimeout, timeout)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("a short timeout returns 504", func(t *testing.T) {
+		start := time.Now()
+		w := get(t, "20ms")
+		assert.Less(t, time.Since(start), 200*time.Millisecond, "the handler should stop at the deadline")
+
+		require.Equal(t, http.StatusGatewayTimeout, w.Code, w.Body.String())
+		assert.Empty(t, w.Header().Get("X-Handler"), "the response of the handler should be discarded")
+
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, apierror.CodeTimeout, response.Error.Code)
+	})
+
+	t.Run("a handler succeeding after the deadline gets its response sent", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/slow", nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderTimeout, "10ms")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Equal(t, "slow", w.Header().Get("X-Handler"))
+		assert.JSONEq(t, `{"token": "issued"}`, w.Body.String(), "the issued token must not be lost")
+	})
+
+	t.Run("the response is sent within the timeout", func(t *testing.T) {
+		w := get(t, "5s")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "slow", w.Header().Get("X-Handler"))
+		assert.JSONEq(t, `{"status": "done"}`, w.Body.String())
+	})
+
+	t.Run("requests without timeout are not bounded", func(t *testing.T) {
+		w := get(t, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	for _, value := range []string{"soon", "0s", "-1s"} {
+		t.Run("invalid timeout "+value, func(t *testing.T) {
+			w := get(t, value)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	t.Run("timeouts are bounded by the maximum", func(t *testing.T) {
+		bounded := gin.New()
+		bounded.Use(handlers.RequestTimeout(20 * time.Millisecond))
+		bounded.GET("/slow", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			c.Status(http.StatusServiceUnavailable)
+		})
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/slow", nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderTimeout, "1h")
+		bounded.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+}
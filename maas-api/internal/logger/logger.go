@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 
 	"go.uber.org/zap"
@@ -31,14 +32,94 @@ func Development() *Logger {
 // It supports different log levels (DEBUG, INFO, WARN, ERROR) and structured output.
 // Prefer using Production() or Development() for better readability.
 func New(debug bool) *Logger {
+	log, err := NewWithOptions(Options{Debug: debug})
+	if err != nil {
+		// Fallback to a basic logger if configuration fails
+		return FromZap(zap.NewExample(), debug)
+	}
+	return log
+}
+
+// Output formats of the logger.
+const (
+	// FormatJSON writes one JSON object per entry, for log aggregation pipelines.
+	FormatJSON = "json"
+	// FormatConsole writes human-readable entries, for local development.
+	FormatConsole = "console"
+)
+
+// Options configure the logger created by NewWithOptions.
+type Options struct {
+	// Debug selects the development defaults: console output at DEBUG level.
+	Debug bool
+	// Format is FormatJSON or FormatConsole. Defaults to console in debug mode and JSON otherwise.
+	Format string
+	// Level is the minimum level logged: debug, info, warn or error. Defaults to debug in debug mode and info otherwise.
+	Level string
+	// OutputPaths are the zap sinks entries are written to. Defaults to stdout.
+	OutputPaths []string
+}
+
+// Validate checks the format and the level of the options.
+func (o Options) Validate() error {
+	_, _, err := o.resolve()
+	return err
+}
+
+// resolve returns the format and the level of the options, applying the defaults of the mode.
+func (o Options) resolve() (string, zapcore.Level, error) {
+	format := o.Format
+	switch format {
+	case "":
+		format = FormatJSON
+		if o.Debug {
+			format = FormatConsole
+		}
+	case FormatJSON, FormatConsole:
+	default:
+		return "", 0, fmt.Errorf("invalid log format %q: valid formats are %q or %q", format, FormatJSON, FormatConsole)
+	}
+
+	level := zapcore.InfoLevel
+	if o.Debug {
+		level = zapcore.DebugLevel
+	}
+	switch o.Level {
+	case "":
+	case "debug":
+		level = zapcore.DebugLevel
+	case "info":
+		level = zapcore.InfoLevel
+	case "warn":
+		level = zapcore.WarnLevel
+	case "error":
+		level = zapcore.ErrorLevel
+	default:
+		return "", 0, fmt.Errorf("invalid log level %q: valid levels are debug, info, warn or error", o.Level)
+	}
+
+	return format, level, nil
+}
+
+// NewWithOptions creates a new logger with KServe-compatible configuration, in the format and at the level
+// of the options. Returns an error if the options are invalid.
+func NewWithOptions(opts Options) (*Logger, error) {
+	format, level, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+
 	var config zap.Config
-	if debug {
+	if opts.Debug {
 		config = zap.NewDevelopmentConfig()
-		config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	} else {
 		config = zap.NewProductionConfig()
-		config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	}
+	config.Encoding = format
+	config.Level = zap.NewAtomicLevelAt(level)
+	if format == FormatConsole {
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	} else {
 		config.EncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
 	}
 
@@ -50,6 +131,9 @@ func New(debug bool) *Logger {
 	config.EncoderConfig.CallerKey = "caller"
 	config.EncoderConfig.StacktraceKey = "stacktrace"
 	config.OutputPaths = []string{"stdout"}
+	if len(opts.OutputPaths) > 0 {
+		config.OutputPaths = opts.OutputPaths
+	}
 	config.ErrorOutputPaths = []string{"stderr"}
 
 	// Build logger
@@ -59,17 +143,24 @@ func New(debug bool) *Logger {
 		zap.AddStacktrace(zapcore.ErrorLevel),
 	)
 	if err != nil {
-		// Fallback to a basic logger if configuration fails
-		baseLogger = zap.NewExample()
+		return nil, fmt.Errorf("failed to build logger: %w", err)
 	}
 
+	return &Logger{
+		SugaredLogger: baseLogger.Sugar(),
+		level:         level,
+	}, nil
+}
+
+// FromZap wraps an existing zap logger, e.g. an observer capturing log entries in tests.
+func FromZap(base *zap.Logger, debug bool) *Logger {
 	level := zapcore.InfoLevel
 	if debug {
 		level = zapcore.DebugLevel
 	}
 
 	return &Logger{
-		SugaredLogger: baseLogger.Sugar(),
+		SugaredLogger: base.Sugar(),
 		level:         level,
 	}
 }
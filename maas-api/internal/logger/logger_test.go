@@ -0,0 +1,123 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// logLines builds a logger with the options writing to a temporary file, logs with it and returns the written lines.
+func logLines(t *testing.T, opts logger.Options, log func(*logger.Logger)) []string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "maas-api.log")
+	opts.OutputPaths = []string{path}
+
+	l, err := logger.NewWithOptions(opts)
+	require.NoError(t, err)
+	log(l)
+	_ = l.Sync()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	output := strings.TrimSpace(string(data))
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+func TestNewWithOptions_Format(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		lines := logLines(t, logger.Options{Format: logger.FormatJSON}, func(l *logger.Logger) {
+			l.Info("Token issued", "user", "alice")
+		})
+		require.Len(t, lines, 1)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry), lines[0])
+		assert.Equal(t, "info", entry["level"])
+		assert.Equal(t, "Token issued", entry["message"])
+		assert.Equal(t, "alice", entry["user"])
+		assert.Contains(t, entry, "timestamp")
+	})
+
+	t.Run("console", func(t *testing.T) {
+		lines := logLines(t, logger.Options{Format: logger.FormatConsole}, func(l *logger.Logger) {
+			l.Info("Token issued", "user", "alice")
+		})
+		require.Len(t, lines, 1)
+
+		assert.False(t, json.Valid([]byte(lines[0])), "console entries must not be JSON: %s", lines[0])
+		assert.Contains(t, lines[0], "INFO")
+		assert.Contains(t, lines[0], "Token issued")
+		assert.Contains(t, lines[0], `{"user": "alice"}`)
+	})
+
+	t.Run("defaults to json in release mode", func(t *testing.T) {
+		lines := logLines(t, logger.Options{}, func(l *logger.Logger) {
+			l.Info("Token issued")
+		})
+		require.Len(t, lines, 1)
+		assert.True(t, json.Valid([]byte(lines[0])), lines[0])
+	})
+
+	t.Run("defaults to console in debug mode", func(t *testing.T) {
+		lines := logLines(t, logger.Options{Debug: true}, func(l *logger.Logger) {
+			l.Debug("Token issued")
+		})
+		require.Len(t, lines, 1)
+		assert.False(t, json.Valid([]byte(lines[0])), lines[0])
+	})
+}
+
+func TestNewWithOptions_Level(t *testing.T) {
+	logAll := func(l *logger.Logger) {
+		l.Debug("debug entry")
+		l.Info("info entry")
+		l.Warn("warn entry")
+		l.Error("error entry")
+	}
+
+	tests := []struct {
+		name     string
+		opts     logger.Options
+		expected int
+	}{
+		{name: "release mode defaults to info", opts: logger.Options{}, expected: 3},
+		{name: "debug mode defaults to debug", opts: logger.Options{Debug: true, Format: logger.FormatJSON}, expected: 4},
+		{name: "level independent of debug mode", opts: logger.Options{Level: "debug"}, expected: 4},
+		{name: "level overrides debug mode", opts: logger.Options{Debug: true, Format: logger.FormatJSON, Level: "warn"}, expected: 2},
+		{name: "error level", opts: logger.Options{Level: "error"}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := logLines(t, tt.opts, logAll)
+			assert.Len(t, lines, tt.expected, lines)
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.NoError(t, logger.Options{Format: logger.FormatConsole, Level: "warn"}.Validate())
+
+	err := logger.Options{Format: "logfmt"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log format")
+
+	err = logger.Options{Level: "trace"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log level")
+
+	_, err = logger.NewWithOptions(logger.Options{Level: "trace"})
+	require.Error(t, err)
+}
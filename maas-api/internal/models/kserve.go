@@ -3,6 +3,8 @@ package models
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
 	kservelistersv1alpha1 "github.com/kserve/kserve/pkg/client/listers/serving/v1alpha1"
@@ -17,11 +19,48 @@ import (
 )
 
 type Manager struct {
-	isvcLister      kservelistersv1beta1.InferenceServiceLister
-	llmIsvcLister   kservelistersv1alpha1.LLMInferenceServiceLister
-	httpRouteLister gatewaylisters.HTTPRouteLister
-	gatewayRef      GatewayRef
-	logger          *logger.Logger
+	isvcLister         kservelistersv1beta1.InferenceServiceLister
+	llmIsvcLister      kservelistersv1alpha1.LLMInferenceServiceLister
+	httpRouteLister    gatewaylisters.HTTPRouteLister
+	gatewayRefs        []GatewayRef
+	managedRouteLabels map[string]string
+	idStrategy         IDStrategy
+	logger             *logger.Logger
+}
+
+// IDStrategy determines how the IDs of models are derived from the resources serving them, see ParseIDStrategy.
+type IDStrategy string
+
+const (
+	// IDStrategyModelName uses the name of the served model, falling back to the name of the resource: spec.model.name
+	// of LLMInferenceServices and the model format of InferenceServices. Models of different namespaces may collide.
+	IDStrategyModelName IDStrategy = "model-name"
+	// IDStrategyResourceName uses the name of the resource.
+	IDStrategyResourceName IDStrategy = "resource-name"
+	// IDStrategyNamespaced uses namespace/name of the resource, unique across namespaces.
+	IDStrategyNamespaced IDStrategy = "namespaced"
+)
+
+// ParseIDStrategy parses a model ID strategy, an empty value is the default IDStrategyModelName.
+func ParseIDStrategy(value string) (IDStrategy, error) {
+	switch strategy := IDStrategy(strings.TrimSpace(value)); strategy {
+	case "":
+		return IDStrategyModelName, nil
+	case IDStrategyModelName, IDStrategyResourceName, IDStrategyNamespaced:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid model ID strategy %q, expected one of %s, %s or %s",
+			value, IDStrategyModelName, IDStrategyResourceName, IDStrategyNamespaced)
+	}
+}
+
+// ManagerOptions configures how the Manager discovers and names models. Zero values keep the defaults.
+type ManagerOptions struct {
+	// ManagedRouteLabels are the label templates selecting the HTTPRoutes KServe manages for LLMInferenceServices,
+	// see ParseManagedRouteLabels. Empty templates keep DefaultManagedRouteLabels.
+	ManagedRouteLabels map[string]string
+	// IDStrategy determines how the IDs of all listed models are derived. Defaults to IDStrategyModelName.
+	IDStrategy IDStrategy
 }
 
 func NewManager(
@@ -29,7 +68,8 @@ func NewManager(
 	isvcLister kservelistersv1beta1.InferenceServiceLister,
 	llmIsvcLister kservelistersv1alpha1.LLMInferenceServiceLister,
 	httpRouteLister gatewaylisters.HTTPRouteLister,
-	gatewayRef GatewayRef,
+	options ManagerOptions,
+	gatewayRefs ...GatewayRef,
 ) (*Manager, error) {
 	if isvcLister == nil {
 		return nil, errors.New("isvcLister is required")
@@ -40,16 +80,46 @@ func NewManager(
 	if httpRouteLister == nil {
 		return nil, errors.New("httpRouteLister is required")
 	}
+	if len(gatewayRefs) == 0 {
+		return nil, errors.New("at least one gatewayRef is required")
+	}
+
+	managedRouteLabels := options.ManagedRouteLabels
+	if len(managedRouteLabels) == 0 {
+		managedRouteLabels = DefaultManagedRouteLabels()
+	}
+	idStrategy := options.IDStrategy
+	if idStrategy == "" {
+		idStrategy = IDStrategyModelName
+	}
 
 	return &Manager{
-		isvcLister:      isvcLister,
-		llmIsvcLister:   llmIsvcLister,
-		httpRouteLister: httpRouteLister,
-		gatewayRef:      gatewayRef,
-		logger:          log,
+		isvcLister:         isvcLister,
+		llmIsvcLister:      llmIsvcLister,
+		httpRouteLister:    httpRouteLister,
+		gatewayRefs:        gatewayRefs,
+		managedRouteLabels: managedRouteLabels,
+		idStrategy:         idStrategy,
+		logger:             log,
 	}, nil
 }
 
+// modelID derives the ID of a model served by the named resource following the ID strategy.
+// The model name is the one the resource serves, if any.
+func (m *Manager) modelID(namespace, name, modelName string) string {
+	switch m.idStrategy {
+	case IDStrategyNamespaced:
+		return namespace + "/" + name
+	case IDStrategyResourceName:
+		return name
+	default:
+		if modelName != "" {
+			return modelName
+		}
+		return name
+	}
+}
+
 // ListAvailableModels lists all InferenceServices across all namespaces.
 func (m *Manager) ListAvailableModels() ([]Model, error) {
 	list, err := m.isvcLister.List(labels.Everything())
@@ -69,11 +139,17 @@ func (m *Manager) inferenceServicesToModels(items []*kservev1beta1.InferenceServ
 			m.logger.Debug("Failed to find URL for InferenceService")
 		}
 
-		modelID := item.Name
-		if item.Spec.Predictor.Model != nil && item.Spec.Predictor.Model.ModelFormat.Name != "" {
-			modelID = item.Spec.Predictor.Model.ModelFormat.Name
+		var modelName string
+		if item.Spec.Predictor.Model != nil {
+			modelName = item.Spec.Predictor.Model.ModelFormat.Name
 		}
+		modelID := m.modelID(item.Namespace, item.Name, modelName)
 
+		state := m.inferenceServiceState(item)
+		var since *time.Time
+		if state == StateReady {
+			since = readySince(item.Status.Conditions)
+		}
 		models = append(models, Model{
 			Model: openai.Model{
 				ID:      modelID,
@@ -81,8 +157,11 @@ func (m *Manager) inferenceServicesToModels(items []*kservev1beta1.InferenceServ
 				OwnedBy: item.Namespace,
 				Created: item.CreationTimestamp.Unix(),
 			},
-			URL:   url,
-			Ready: m.checkInferenceServiceReadiness(item),
+			URL:             url,
+			EndpointPending: url == nil,
+			Ready:           state == StateReady,
+			State:           state,
+			ReadySince:      since,
 		})
 	}
 
@@ -102,9 +181,9 @@ func (m *Manager) findInferenceServiceURL(is *kservev1beta1.InferenceService) *a
 	return nil
 }
 
-func (m *Manager) checkInferenceServiceReadiness(is *kservev1beta1.InferenceService) bool {
+func (m *Manager) inferenceServiceState(is *kservev1beta1.InferenceService) State {
 	if is.DeletionTimestamp != nil {
-		return false
+		return StateNotReady
 	}
 
 	if is.Generation > 0 && is.Status.ObservedGeneration != is.Generation {
@@ -112,19 +191,69 @@ func (m *Manager) checkInferenceServiceReadiness(is *kservev1beta1.InferenceServ
 			"observed_generation", is.Status.ObservedGeneration,
 			"expected_generation", is.Generation,
 		)
-		return false
+		return StateNotReady
 	}
 
 	if len(is.Status.Conditions) == 0 {
 		m.logger.Debug("No conditions found for InferenceService")
-		return false
+		return StateNotReady
+	}
+
+	state := conditionsState(is.Status.Conditions, kservev1beta1.PredictorReady, kservev1beta1.IngressReady)
+
+	// Model copies failing to load leave the model serving from the remaining ones, if any.
+	if copies := is.Status.ModelStatus.ModelCopies; copies != nil && copies.FailedCopies > 0 && state != StateNotReady {
+		if copies.FailedCopies >= copies.TotalCopies {
+			return StateNotReady
+		}
+		return StateDegraded
+	}
+
+	return state
+}
+
+// readySince returns the last transition time of the Ready condition when it is true, nil when it is not or the time
+// is not set.
+func readySince(conditions []apis.Condition) *time.Time {
+	for _, cond := range conditions {
+		if cond.Type != apis.ConditionReady {
+			continue
+		}
+		if cond.Status != corev1.ConditionTrue || cond.LastTransitionTime.Inner.IsZero() {
+			return nil
+		}
+		since := cond.LastTransitionTime.Inner.Time
+		return &since
 	}
+	return nil
+}
 
-	for _, cond := range is.Status.Conditions {
+// conditionsState derives the state of a model from its status conditions. The model is ready when all of them
+// are true, and degraded when some are not but it still serves requests: either the aggregated Ready condition
+// is true, or all the serving conditions are.
+func conditionsState(conditions []apis.Condition, serving ...apis.ConditionType) State {
+	status := make(map[apis.ConditionType]corev1.ConditionStatus, len(conditions))
+	allTrue := true
+	for _, cond := range conditions {
+		status[cond.Type] = cond.Status
 		if cond.Status != corev1.ConditionTrue {
-			return false
+			allTrue = false
+		}
+	}
+
+	if allTrue {
+		return StateReady
+	}
+
+	if status[apis.ConditionReady] == corev1.ConditionTrue {
+		return StateDegraded
+	}
+
+	for _, condType := range serving {
+		if status[condType] != corev1.ConditionTrue {
+			return StateNotReady
 		}
 	}
 
-	return true
+	return StateDegraded
 }
@@ -2,13 +2,21 @@ package models
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
 	"github.com/openai/openai-go/v2"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 )
@@ -16,6 +24,101 @@ import (
 type GatewayRef struct {
 	Name      string
 	Namespace string
+	// Audience is the audience expected by the authentication policy of the gateway, if any.
+	// It is added to the audiences of the Service Account tokens issued to clients.
+	Audience string
+}
+
+// ParseGatewayRefs parses gateway references. Each entry is either "namespace/name" or a bare "name",
+// in which case the gateway is looked up in defaultNamespace, optionally followed by "=audience".
+func ParseGatewayRefs(entries []string, defaultNamespace string) ([]GatewayRef, error) {
+	refs := make([]GatewayRef, 0, len(entries))
+	for _, entry := range entries {
+		gateway, audience, hasAudience := strings.Cut(entry, "=")
+		gateway = strings.TrimSpace(gateway)
+
+		ref := GatewayRef{Name: gateway, Namespace: defaultNamespace, Audience: strings.TrimSpace(audience)}
+		if namespace, name, found := strings.Cut(gateway, "/"); found {
+			ref.Name = strings.TrimSpace(name)
+			ref.Namespace = strings.TrimSpace(namespace)
+		}
+
+		if ref.Name == "" || ref.Namespace == "" || strings.Contains(ref.Name, "/") {
+			return nil, fmt.Errorf("invalid gateway reference %q, expected namespace/name", entry)
+		}
+
+		if hasAudience && ref.Audience == "" {
+			return nil, fmt.Errorf("invalid gateway reference %q, expected a non-empty audience after '='", entry)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// managedRouteNamePlaceholder is replaced by the name of the LLMInferenceService in managed route label templates.
+const managedRouteNamePlaceholder = "{name}"
+
+// DefaultManagedRouteLabels returns the labels KServe sets on the HTTPRoutes it manages for LLMInferenceServices.
+func DefaultManagedRouteLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/component": "llminferenceservice-router",
+		"app.kubernetes.io/name":      managedRouteNamePlaceholder,
+		"app.kubernetes.io/part-of":   "llminferenceservice",
+	}
+}
+
+// ParseManagedRouteLabels parses key=value label templates selecting the HTTPRoutes KServe manages for
+// LLMInferenceServices. Values may reference {name}, the name of the LLMInferenceService.
+func ParseManagedRouteLabels(entries []string) (map[string]string, error) {
+	templates := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found {
+			return nil, fmt.Errorf("invalid managed route label %q: expected key=value", entry)
+		}
+
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+
+		// Render with a valid name, so that only the template itself is validated.
+		rendered := strings.ReplaceAll(value, managedRouteNamePlaceholder, "model")
+		if errs := validation.IsValidLabelValue(rendered); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value %q for label %q: %s", value, key, strings.Join(errs, "; "))
+		}
+
+		if _, duplicate := templates[key]; duplicate {
+			return nil, fmt.Errorf("duplicate managed route label %q", key)
+		}
+		templates[key] = value
+	}
+
+	return templates, nil
+}
+
+// CheckGatewaysExist returns an error naming the referenced gateways missing from the lister. Models are only
+// listed when exposed through one of the gateways, a missing gateway therefore silently yields no models.
+func CheckGatewaysExist(gatewayLister gatewaylisters.GatewayLister, refs []GatewayRef) error {
+	var missing []string
+	for _, ref := range refs {
+		_, err := gatewayLister.Gateways(ref.Namespace).Get(ref.Name)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, ref.Namespace+"/"+ref.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get gateway %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("configured gateways not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 func (m *Manager) ListAvailableLLMs() ([]Model, error) {
@@ -24,34 +127,101 @@ func (m *Manager) ListAvailableLLMs() ([]Model, error) {
 		return nil, fmt.Errorf("failed to list LLMInferenceServices: %w", err)
 	}
 
-	var instanceLLMs []*kservev1alpha1.LLMInferenceService
+	var instanceLLMs []exposedLLM
 	for _, llmIsvc := range list {
-		if m.partOfMaaSInstance(llmIsvc) {
-			instanceLLMs = append(instanceLLMs, llmIsvc)
+		if exposure := m.partOfMaaSInstance(llmIsvc); exposure != nil {
+			instanceLLMs = append(instanceLLMs, exposedLLM{llmIsvc: llmIsvc, exposure: exposure})
 		}
 	}
 
 	return m.llmInferenceServicesToModels(instanceLLMs)
 }
 
+// SummarizeLLMs counts the LLMInferenceServices of all namespaces by state, sorted by namespace. Unlike
+// ListAvailableLLMs, models are not filtered: those that are hidden or not exposed through one of the MaaS gateways
+// are summarized too, reporting whether they are attached to one.
+func (m *Manager) SummarizeLLMs() ([]NamespaceSummary, error) {
+	list, err := m.llmIsvcLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LLMInferenceServices: %w", err)
+	}
+
+	byNamespace := make(map[string]*NamespaceSummary)
+	for _, llmIsvc := range list {
+		summary, exists := byNamespace[llmIsvc.Namespace]
+		if !exists {
+			summary = &NamespaceSummary{Namespace: llmIsvc.Namespace}
+			byNamespace[llmIsvc.Namespace] = summary
+		}
+
+		state := m.llmInferenceServiceState(llmIsvc)
+		switch state {
+		case StateReady:
+			summary.Ready++
+		case StateDegraded:
+			summary.Degraded++
+		default:
+			summary.NotReady++
+		}
+
+		summary.Models = append(summary.Models, ModelSummary{
+			Name:     llmIsvc.Name,
+			State:    state,
+			Attached: m.partOfMaaSInstance(llmIsvc) != nil,
+		})
+	}
+
+	summaries := make([]NamespaceSummary, 0, len(byNamespace))
+	for _, namespace := range slices.Sorted(maps.Keys(byNamespace)) {
+		summary := byNamespace[namespace]
+		slices.SortFunc(summary.Models, func(a, b ModelSummary) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		summaries = append(summaries, *summary)
+	}
+
+	return summaries, nil
+}
+
+// exposedLLM is an LLMInferenceService that is part of the MaaS instance, with how it is exposed.
+type exposedLLM struct {
+	llmIsvc  *kservev1alpha1.LLMInferenceService
+	exposure *Exposure
+}
+
 // partOfMaaSInstance checks if the given LLMInferenceService is part of this "MaaS instance". This means that it is
-// either directly referenced by the gateway that has MaaS capabilities, or it is referenced by an HTTPRoute that is managed by the gateway.
-// The gateway is part of the component configuration.
-func (m *Manager) partOfMaaSInstance(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+// either directly referenced by one of the gateways that have MaaS capabilities, or it is referenced by an HTTPRoute
+// that is managed by one of them. The gateways are part of the component configuration.
+// Returns how the LLMInferenceService is exposed through the first matching gateway, or nil if it is not part of it.
+func (m *Manager) partOfMaaSInstance(llmIsvc *kservev1alpha1.LLMInferenceService) *Exposure {
 	if llmIsvc.Spec.Router == nil {
-		return false
+		return nil
+	}
+
+	for _, exposure := range []func(*kservev1alpha1.LLMInferenceService) *Exposure{
+		m.directGatewayReference,
+		m.httpRouteSpecRefToGateway,
+		m.referencedRouteAttachedToGateway,
+		m.managedRouteAttachedToGateway,
+	} {
+		if found := exposure(llmIsvc); found != nil {
+			return found
+		}
 	}
 
-	return m.hasDirectGatewayReference(llmIsvc) ||
-		m.hasHTTPRouteSpecRefToGateway(llmIsvc) ||
-		m.hasReferencedRouteAttachedToGateway(llmIsvc) ||
-		m.hasManagedRouteAttachedToGateway(llmIsvc)
+	return nil
 }
 
-func (m *Manager) llmInferenceServicesToModels(items []*kservev1alpha1.LLMInferenceService) ([]Model, error) {
+func (m *Manager) llmInferenceServicesToModels(items []exposedLLM) ([]Model, error) {
 	models := make([]Model, 0, len(items))
 
-	for _, item := range items {
+	for _, exposed := range items {
+		item := exposed.llmIsvc
+		visibility := m.modelVisibility(item)
+		if visibility == VisibilityHidden {
+			continue
+		}
+
 		url := m.findLLMInferenceServiceURL(item)
 		if url == nil {
 			m.logger.Debug("Failed to find URL for LLMInferenceService",
@@ -60,21 +230,34 @@ func (m *Manager) llmInferenceServicesToModels(items []*kservev1alpha1.LLMInfere
 			)
 		}
 
-		modelID := item.Name
-		if item.Spec.Model.Name != nil && *item.Spec.Model.Name != "" {
-			modelID = *item.Spec.Model.Name
+		var modelName string
+		if item.Spec.Model.Name != nil {
+			modelName = *item.Spec.Model.Name
 		}
+		modelID := m.modelID(item.Namespace, item.Name, modelName)
 
+		state := m.llmInferenceServiceState(item)
+		var since *time.Time
+		if state == StateReady {
+			since = readySince(item.Status.Conditions)
+		}
 		models = append(models, Model{
 			Model: openai.Model{
 				ID:      modelID,
 				Object:  "model",
-				OwnedBy: item.Namespace,
+				OwnedBy: m.modelOwner(item),
 				Created: item.CreationTimestamp.Unix(),
 			},
-			URL:     url,
-			Ready:   m.checkLLMInferenceServiceReadiness(item),
-			Details: m.extractModelDetails(item),
+			URL:               url,
+			Addresses:         llmInferenceServiceAddresses(item),
+			EndpointPending:   url == nil,
+			SupportsStreaming: m.modelSupportsStreaming(item),
+			Ready:             state == StateReady,
+			State:             state,
+			ReadySince:        since,
+			Details:           m.extractModelDetails(item),
+			Visibility:        visibility,
+			Exposure:          exposed.exposure,
 		})
 	}
 
@@ -101,6 +284,93 @@ func (m *Manager) findLLMInferenceServiceURL(llmIsvc *kservev1alpha1.LLMInferenc
 	return nil
 }
 
+// llmInferenceServiceAddresses returns all addresses from the status, skipping entries without a URL.
+func llmInferenceServiceAddresses(llmIsvc *kservev1alpha1.LLMInferenceService) []ModelAddress {
+	var addresses []ModelAddress
+	for _, address := range llmIsvc.Status.Addresses {
+		if address.URL == nil {
+			continue
+		}
+
+		modelAddress := ModelAddress{URL: address.URL}
+		if address.Name != nil {
+			modelAddress.Name = *address.Name
+		}
+		addresses = append(addresses, modelAddress)
+	}
+
+	return addresses
+}
+
+// modelVisibility reads the visibility annotation, defaulting to public when it is absent or unrecognized.
+func (m *Manager) modelVisibility(llmIsvc *kservev1alpha1.LLMInferenceService) Visibility {
+	value, exists := llmIsvc.GetAnnotations()[constant.AnnotationVisibility]
+	if !exists {
+		return VisibilityPublic
+	}
+
+	switch visibility := Visibility(strings.ToLower(strings.TrimSpace(value))); visibility {
+	case VisibilityPublic, VisibilityInternal, VisibilityHidden:
+		return visibility
+	default:
+		m.logger.Warn("Unknown model visibility, treating as public",
+			"namespace", llmIsvc.Namespace,
+			"name", llmIsvc.Name,
+			"visibility", value,
+		)
+		return VisibilityPublic
+	}
+}
+
+// modelSupportsStreaming reads the streaming annotation. LLMs support streaming unless annotated false,
+// unrecognized values keep the default.
+func (m *Manager) modelSupportsStreaming(llmIsvc *kservev1alpha1.LLMInferenceService) *bool {
+	supported := true
+	if value, exists := llmIsvc.GetAnnotations()[constant.AnnotationStreaming]; exists {
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			m.logger.Warn("Invalid streaming annotation, assuming streaming is supported",
+				"namespace", llmIsvc.Namespace,
+				"name", llmIsvc.Name,
+				"streaming", value,
+			)
+		} else {
+			supported = parsed
+		}
+	}
+	return &supported
+}
+
+// maxOwnedByLength caps the number of characters of an owner set through the owned-by annotation.
+const maxOwnedByLength = 64
+
+// modelOwner returns the owner displayed for the model. The owned-by annotation takes precedence over the namespace,
+// once stripped of control characters, with whitespace collapsed and truncated to maxOwnedByLength characters.
+func (m *Manager) modelOwner(llmIsvc *kservev1alpha1.LLMInferenceService) string {
+	value, exists := llmIsvc.GetAnnotations()[constant.AnnotationOwnedBy]
+	if !exists {
+		return llmIsvc.Namespace
+	}
+
+	owner := strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}), " ")
+
+	if runes := []rune(owner); len(runes) > maxOwnedByLength {
+		owner = strings.TrimSpace(string(runes[:maxOwnedByLength]))
+	}
+
+	if owner == "" {
+		m.logger.Warn("Empty owned-by annotation, falling back to namespace",
+			"namespace", llmIsvc.Namespace,
+			"name", llmIsvc.Name,
+		)
+		return llmIsvc.Namespace
+	}
+
+	return owner
+}
+
 func (m *Manager) extractModelDetails(llmIsvc *kservev1alpha1.LLMInferenceService) *Details {
 	annotations := llmIsvc.GetAnnotations()
 	if annotations == nil {
@@ -110,9 +380,10 @@ func (m *Manager) extractModelDetails(llmIsvc *kservev1alpha1.LLMInferenceServic
 	genaiUseCase := annotations[constant.AnnotationGenAIUseCase]
 	description := annotations[constant.AnnotationDescription]
 	displayName := annotations[constant.AnnotationDisplayName]
+	family := strings.ToLower(strings.TrimSpace(annotations[constant.AnnotationFamily]))
 
 	// Only return Details if at least one field is populated
-	if genaiUseCase == "" && description == "" && displayName == "" {
+	if genaiUseCase == "" && description == "" && displayName == "" && family == "" {
 		return nil
 	}
 
@@ -120,12 +391,13 @@ func (m *Manager) extractModelDetails(llmIsvc *kservev1alpha1.LLMInferenceServic
 		GenAIUseCase: genaiUseCase,
 		Description:  description,
 		DisplayName:  displayName,
+		Family:       family,
 	}
 }
 
-func (m *Manager) checkLLMInferenceServiceReadiness(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+func (m *Manager) llmInferenceServiceState(llmIsvc *kservev1alpha1.LLMInferenceService) State {
 	if llmIsvc.DeletionTimestamp != nil {
-		return false
+		return StateNotReady
 	}
 
 	if llmIsvc.Generation > 0 && llmIsvc.Status.ObservedGeneration != llmIsvc.Generation {
@@ -133,7 +405,7 @@ func (m *Manager) checkLLMInferenceServiceReadiness(llmIsvc *kservev1alpha1.LLMI
 			"observed_generation", llmIsvc.Status.ObservedGeneration,
 			"expected_generation", llmIsvc.Generation,
 		)
-		return false
+		return StateNotReady
 	}
 
 	if len(llmIsvc.Status.Conditions) == 0 {
@@ -141,67 +413,55 @@ func (m *Manager) checkLLMInferenceServiceReadiness(llmIsvc *kservev1alpha1.LLMI
 			"namespace", llmIsvc.Namespace,
 			"name", llmIsvc.Name,
 		)
-		return false
+		return StateNotReady
 	}
 
-	for _, cond := range llmIsvc.Status.Conditions {
-		if cond.Status != corev1.ConditionTrue {
-			return false
-		}
-	}
-
-	return true
+	return conditionsState(llmIsvc.Status.Conditions, kservev1alpha1.MainWorkloadReady, kservev1alpha1.RouterReady)
 }
 
-func (m *Manager) hasDirectGatewayReference(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+func (m *Manager) directGatewayReference(llmIsvc *kservev1alpha1.LLMInferenceService) *Exposure {
 	if llmIsvc.Spec.Router.Gateway == nil {
-		return false
+		return nil
 	}
 
 	for _, ref := range llmIsvc.Spec.Router.Gateway.Refs {
-		if string(ref.Name) != m.gatewayRef.Name {
-			continue
-		}
-
 		refNamespace := llmIsvc.Namespace
 		if ref.Namespace != "" {
 			refNamespace = string(ref.Namespace)
 		}
 
-		if refNamespace == m.gatewayRef.Namespace {
-			return true
+		if m.isMaaSGateway(string(ref.Name), refNamespace) {
+			return &Exposure{
+				Source:  ExposureGatewayRef,
+				Gateway: ObjectRef{Namespace: refNamespace, Name: string(ref.Name)},
+			}
 		}
 	}
 
-	return false
+	return nil
 }
 
-func (m *Manager) hasHTTPRouteSpecRefToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+func (m *Manager) httpRouteSpecRefToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) *Exposure {
 	if llmIsvc.Spec.Router.Route == nil || llmIsvc.Spec.Router.Route.HTTP == nil || llmIsvc.Spec.Router.Route.HTTP.Spec == nil {
-		return false
+		return nil
 	}
 
-	for _, parentRef := range llmIsvc.Spec.Router.Route.HTTP.Spec.ParentRefs {
-		if string(parentRef.Name) != m.gatewayRef.Name {
-			continue
-		}
-
-		parentNamespace := llmIsvc.Namespace
-		if parentRef.Namespace != nil {
-			parentNamespace = string(*parentRef.Namespace)
-		}
-
-		if parentNamespace == m.gatewayRef.Namespace {
-			return true
-		}
+	spec := llmIsvc.Spec.Router.Route.HTTP.Spec
+	gateway, ok := m.attachedGateway(spec.ParentRefs, llmIsvc.Namespace)
+	if !ok {
+		return nil
 	}
 
-	return false
+	return &Exposure{
+		Source:  ExposureRouteSpec,
+		Gateway: gateway,
+		Route:   &RouteRef{Hostnames: hostnames(spec.Hostnames)},
+	}
 }
 
-func (m *Manager) hasReferencedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+func (m *Manager) referencedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) *Exposure {
 	if llmIsvc.Spec.Router.Route == nil || llmIsvc.Spec.Router.Route.HTTP == nil || len(llmIsvc.Spec.Router.Route.HTTP.Refs) == 0 {
-		return false
+		return nil
 	}
 
 	for _, routeRef := range llmIsvc.Spec.Router.Route.HTTP.Refs {
@@ -218,29 +478,29 @@ func (m *Manager) hasReferencedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LL
 			continue
 		}
 
-		if m.routeAttachedToGateway(route, llmIsvc.Namespace) {
-			return true
+		if exposure := m.routeExposure(ExposureReferencedRoute, route, llmIsvc.Namespace); exposure != nil {
+			return exposure
 		}
 	}
 
-	return false
+	return nil
 }
 
-func (m *Manager) hasManagedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) bool {
+func (m *Manager) managedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LLMInferenceService) *Exposure {
 	if llmIsvc.Spec.Router.Route == nil || llmIsvc.Spec.Router.Route.HTTP == nil {
-		return false
+		return nil
 	}
 
 	httpRoute := llmIsvc.Spec.Router.Route.HTTP
 	if httpRoute.Spec != nil || len(httpRoute.Refs) > 0 {
-		return false
+		return nil
 	}
 
-	selector := labels.SelectorFromSet(labels.Set{
-		"app.kubernetes.io/component": "llminferenceservice-router",
-		"app.kubernetes.io/name":      llmIsvc.Name,
-		"app.kubernetes.io/part-of":   "llminferenceservice",
-	})
+	set := make(labels.Set, len(m.managedRouteLabels))
+	for key, template := range m.managedRouteLabels {
+		set[key] = strings.ReplaceAll(template, managedRouteNamePlaceholder, llmIsvc.Name)
+	}
+	selector := labels.SelectorFromSet(set)
 
 	routes, err := m.httpRouteLister.HTTPRoutes(llmIsvc.Namespace).List(selector)
 	if err != nil {
@@ -249,33 +509,68 @@ func (m *Manager) hasManagedRouteAttachedToGateway(llmIsvc *kservev1alpha1.LLMIn
 			"name", llmIsvc.Name,
 			"error", err,
 		)
-		return false
+		return nil
 	}
 
 	for _, route := range routes {
-		if m.routeAttachedToGateway(route, llmIsvc.Namespace) {
-			return true
+		if exposure := m.routeExposure(ExposureManagedRoute, route, llmIsvc.Namespace); exposure != nil {
+			return exposure
 		}
 	}
 
-	return false
+	return nil
 }
 
-func (m *Manager) routeAttachedToGateway(route *gwapiv1.HTTPRoute, defaultNamespace string) bool {
-	for _, parentRef := range route.Spec.ParentRefs {
-		if string(parentRef.Name) != m.gatewayRef.Name {
-			continue
-		}
+// routeExposure returns the exposure of a model through the HTTPRoute, or nil if the route is not attached
+// to one of the MaaS gateways.
+func (m *Manager) routeExposure(source string, route *gwapiv1.HTTPRoute, defaultNamespace string) *Exposure {
+	gateway, ok := m.attachedGateway(route.Spec.ParentRefs, defaultNamespace)
+	if !ok {
+		return nil
+	}
+
+	return &Exposure{
+		Source:  source,
+		Gateway: gateway,
+		Route: &RouteRef{
+			Namespace: route.Namespace,
+			Name:      route.Name,
+			Hostnames: hostnames(route.Spec.Hostnames),
+		},
+	}
+}
 
+// attachedGateway returns the first of the parent references that is one of the MaaS gateways.
+func (m *Manager) attachedGateway(parentRefs []gwapiv1.ParentReference, defaultNamespace string) (ObjectRef, bool) {
+	for _, parentRef := range parentRefs {
 		parentNamespace := defaultNamespace
 		if parentRef.Namespace != nil {
 			parentNamespace = string(*parentRef.Namespace)
 		}
 
-		if parentNamespace == m.gatewayRef.Namespace {
-			return true
+		if m.isMaaSGateway(string(parentRef.Name), parentNamespace) {
+			return ObjectRef{Namespace: parentNamespace, Name: string(parentRef.Name)}, true
 		}
 	}
 
-	return false
+	return ObjectRef{}, false
+}
+
+func hostnames(names []gwapiv1.Hostname) []string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, string(name))
+	}
+	return result
+}
+
+// isMaaSGateway checks if the gateway identified by name and namespace is one of the gateways configured for this MaaS instance.
+func (m *Manager) isMaaSGateway(name, namespace string) bool {
+	return slices.ContainsFunc(m.gatewayRefs, func(ref GatewayRef) bool {
+		return ref.Name == name && ref.Namespace == namespace
+	})
 }
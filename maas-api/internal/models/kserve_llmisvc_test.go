@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"strings"
 	"testing"
 
 	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
@@ -8,8 +9,11 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
@@ -260,6 +264,7 @@ func TestListAvailableLLMs(t *testing.T) {
 				fixtures.NewInferenceServiceLister(),
 				fixtures.NewLLMInferenceServiceLister(fixtures.ToRuntimeObjects(tt.llmServices)...),
 				fixtures.NewHTTPRouteLister(fixtures.ToRuntimeObjects(tt.httpRoutes)...),
+				models.ManagerOptions{},
 				gateway,
 			)
 			require.NoError(t, errMgr)
@@ -277,6 +282,908 @@ func TestListAvailableLLMs(t *testing.T) {
 	}
 }
 
+func TestListAvailableLLMs_MultipleGateways(t *testing.T) {
+	testLogger := logger.Development()
+	internalGateway := models.GatewayRef{Name: "maas-internal", Namespace: "gateway-ns"}
+	externalGateway := models.GatewayRef{Name: "maas-external", Namespace: "edge-ns"}
+
+	llmServices := []*kservev1alpha1.LLMInferenceService{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "llm-internal", Namespace: "test-ns"},
+			Spec: kservev1alpha1.LLMInferenceServiceSpec{
+				Router: &kservev1alpha1.RouterSpec{
+					Gateway: &kservev1alpha1.GatewaySpec{
+						Refs: []kservev1alpha1.UntypedObjectReference{
+							{Name: "maas-internal", Namespace: "gateway-ns"},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "llm-external-direct", Namespace: "test-ns"},
+			Spec: kservev1alpha1.LLMInferenceServiceSpec{
+				Router: &kservev1alpha1.RouterSpec{
+					Gateway: &kservev1alpha1.GatewaySpec{
+						Refs: []kservev1alpha1.UntypedObjectReference{
+							{Name: "maas-external", Namespace: "edge-ns"},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "llm-external-route", Namespace: "test-ns"},
+			Spec: kservev1alpha1.LLMInferenceServiceSpec{
+				Router: &kservev1alpha1.RouterSpec{
+					Route: &kservev1alpha1.GatewayRoutesSpec{
+						HTTP: &kservev1alpha1.HTTPRouteSpec{
+							Refs: []corev1.LocalObjectReference{{Name: "external-route"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "llm-other", Namespace: "test-ns"},
+			Spec: kservev1alpha1.LLMInferenceServiceSpec{
+				Router: &kservev1alpha1.RouterSpec{
+					Gateway: &kservev1alpha1.GatewaySpec{
+						Refs: []kservev1alpha1.UntypedObjectReference{
+							// Same name as the second gateway, but in another namespace.
+							{Name: "maas-external", Namespace: "gateway-ns"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "external-route", Namespace: "test-ns"},
+			Spec: gwapiv1.HTTPRouteSpec{
+				CommonRouteSpec: gwapiv1.CommonRouteSpec{
+					ParentRefs: []gwapiv1.ParentReference{
+						{Name: "maas-external", Namespace: ptrTo(gwapiv1.Namespace("edge-ns"))},
+					},
+				},
+			},
+		},
+	}
+
+	manager, errMgr := models.NewManager(
+		testLogger,
+		fixtures.NewInferenceServiceLister(),
+		fixtures.NewLLMInferenceServiceLister(fixtures.ToRuntimeObjects(llmServices)...),
+		fixtures.NewHTTPRouteLister(fixtures.ToRuntimeObjects(httpRoutes)...),
+		models.ManagerOptions{},
+		internalGateway,
+		externalGateway,
+	)
+	require.NoError(t, errMgr)
+
+	availableModels, err := manager.ListAvailableLLMs()
+	require.NoError(t, err)
+
+	var actualNames []string
+	for _, model := range availableModels {
+		actualNames = append(actualNames, model.ID)
+	}
+
+	assert.ElementsMatch(t, []string{"llm-internal", "llm-external-direct", "llm-external-route"}, actualNames)
+}
+
+func TestNewManager_RequiresGatewayRef(t *testing.T) {
+	_, err := models.NewManager(
+		logger.Development(),
+		fixtures.NewInferenceServiceLister(),
+		fixtures.NewLLMInferenceServiceLister(),
+		fixtures.NewHTTPRouteLister(),
+		models.ManagerOptions{},
+	)
+	require.Error(t, err)
+}
+
+func TestParseGatewayRefs(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []string
+		expected    []models.GatewayRef
+		expectError bool
+	}{
+		{
+			name:    "namespaced references",
+			entries: []string{"gateway-ns/maas-internal", "edge-ns/maas-external"},
+			expected: []models.GatewayRef{
+				{Name: "maas-internal", Namespace: "gateway-ns"},
+				{Name: "maas-external", Namespace: "edge-ns"},
+			},
+		},
+		{
+			name:     "bare name uses the default namespace",
+			entries:  []string{"maas-internal"},
+			expected: []models.GatewayRef{{Name: "maas-internal", Namespace: "openshift-ingress"}},
+		},
+		{
+			name:    "references with audiences",
+			entries: []string{"gateway-ns/maas-internal=maas-internal-sa", "maas-external = external-audience"},
+			expected: []models.GatewayRef{
+				{Name: "maas-internal", Namespace: "gateway-ns", Audience: "maas-internal-sa"},
+				{Name: "maas-external", Namespace: "openshift-ingress", Audience: "external-audience"},
+			},
+		},
+		{
+			name:        "empty audience",
+			entries:     []string{"gateway-ns/maas-internal="},
+			expectError: true,
+		},
+		{
+			name:        "missing name",
+			entries:     []string{"gateway-ns/"},
+			expectError: true,
+		},
+		{
+			name:        "too many segments",
+			entries:     []string{"a/b/c"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := models.ParseGatewayRefs(tt.entries, "openshift-ingress")
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, refs)
+		})
+	}
+}
+
+func TestParseManagedRouteLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:     "no entries",
+			expected: map[string]string{},
+		},
+		{
+			name:    "templates referencing the model name",
+			entries: []string{"serving.kserve.io/component=router", " serving.kserve.io/llminferenceservice = {name} "},
+			expected: map[string]string{
+				"serving.kserve.io/component":           "router",
+				"serving.kserve.io/llminferenceservice": "{name}",
+			},
+		},
+		{
+			name:        "missing value",
+			entries:     []string{"serving.kserve.io/component"},
+			expectError: true,
+		},
+		{
+			name:        "invalid key",
+			entries:     []string{"not a key=router"},
+			expectError: true,
+		},
+		{
+			name:        "invalid value",
+			entries:     []string{"app.kubernetes.io/name={name}/router"},
+			expectError: true,
+		},
+		{
+			name:        "duplicate key",
+			entries:     []string{"app.kubernetes.io/name={name}", "app.kubernetes.io/name=router"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := models.ParseManagedRouteLabels(tt.entries)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, templates)
+		})
+	}
+}
+
+func TestListAvailableLLMs_ManagedRouteLabels(t *testing.T) {
+	llmService := &kservev1alpha1.LLMInferenceService{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm-managed", Namespace: "test-ns"},
+		Spec: kservev1alpha1.LLMInferenceServiceSpec{
+			Router: &kservev1alpha1.RouterSpec{
+				Route: &kservev1alpha1.GatewayRoutesSpec{
+					HTTP: &kservev1alpha1.HTTPRouteSpec{},
+				},
+			},
+		},
+	}
+	// Route labeled as a future KServe version could, unmatched by the default labels.
+	httpRoute := &gwapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "llm-managed-kserve-route",
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				"serving.kserve.io/component":           "router",
+				"serving.kserve.io/llminferenceservice": "llm-managed",
+			},
+		},
+		Spec: gwapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gwapiv1.CommonRouteSpec{
+				ParentRefs: []gwapiv1.ParentReference{
+					{Name: "maas-gateway", Namespace: ptrTo(gwapiv1.Namespace("gateway-ns"))},
+				},
+			},
+		},
+	}
+
+	newManager := func(t *testing.T, options models.ManagerOptions) *models.Manager {
+		t.Helper()
+
+		manager, errMgr := models.NewManager(
+			logger.Development(),
+			fixtures.NewInferenceServiceLister(),
+			fixtures.NewLLMInferenceServiceLister(fixtures.ToRuntimeObjects([]*kservev1alpha1.LLMInferenceService{llmService})...),
+			fixtures.NewHTTPRouteLister(fixtures.ToRuntimeObjects([]*gwapiv1.HTTPRoute{httpRoute})...),
+			options,
+			models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+		)
+		require.NoError(t, errMgr)
+		return manager
+	}
+
+	t.Run("default labels", func(t *testing.T) {
+		availableModels, err := newManager(t, models.ManagerOptions{}).ListAvailableLLMs()
+		require.NoError(t, err)
+		assert.Empty(t, availableModels)
+	})
+
+	t.Run("custom labels", func(t *testing.T) {
+		templates, err := models.ParseManagedRouteLabels([]string{
+			"serving.kserve.io/component=router",
+			"serving.kserve.io/llminferenceservice={name}",
+		})
+		require.NoError(t, err)
+
+		availableModels, err := newManager(t, models.ManagerOptions{ManagedRouteLabels: templates}).ListAvailableLLMs()
+		require.NoError(t, err)
+		require.Len(t, availableModels, 1)
+		require.NotNil(t, availableModels[0].Exposure)
+		assert.Equal(t, models.ExposureManagedRoute, availableModels[0].Exposure.Source)
+		assert.Equal(t, "llm-managed-kserve-route", availableModels[0].Exposure.Route.Name)
+	})
+}
+
+func TestParseIDStrategy(t *testing.T) {
+	for value, expected := range map[string]models.IDStrategy{
+		"":              models.IDStrategyModelName,
+		"model-name":    models.IDStrategyModelName,
+		"resource-name": models.IDStrategyResourceName,
+		" namespaced ":  models.IDStrategyNamespaced,
+	} {
+		strategy, err := models.ParseIDStrategy(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, strategy, value)
+	}
+
+	_, err := models.ParseIDStrategy("uuid")
+	assert.ErrorContains(t, err, `invalid model ID strategy "uuid"`)
+}
+
+func TestListAvailableLLMs_IDStrategy(t *testing.T) {
+	testLogger := logger.Development()
+	gateway := models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"}
+
+	llm := func(namespace, name, modelName string) *kservev1alpha1.LLMInferenceService {
+		llmService := &kservev1alpha1.LLMInferenceService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: kservev1alpha1.LLMInferenceServiceSpec{
+				Router: &kservev1alpha1.RouterSpec{
+					Gateway: &kservev1alpha1.GatewaySpec{
+						Refs: []kservev1alpha1.UntypedObjectReference{
+							{Name: "maas-gateway", Namespace: "gateway-ns"},
+						},
+					},
+				},
+			},
+		}
+		if modelName != "" {
+			llmService.Spec.Model.Name = &modelName
+		}
+		return llmService
+	}
+
+	// Both teams serve the same model under the same resource name.
+	services := []runtime.Object{
+		llm("team-a", "llama", "meta-llama/Llama-3.1-8B-Instruct"),
+		llm("team-b", "llama", "meta-llama/Llama-3.1-8B-Instruct"),
+		llm("team-b", "granite", ""),
+	}
+
+	tests := []struct {
+		name        string
+		strategy    models.IDStrategy
+		expectedIDs []string
+	}{
+		{
+			name:        "default uses the served model name",
+			expectedIDs: []string{"meta-llama/Llama-3.1-8B-Instruct", "meta-llama/Llama-3.1-8B-Instruct", "granite"},
+		},
+		{
+			name:        "model-name",
+			strategy:    models.IDStrategyModelName,
+			expectedIDs: []string{"meta-llama/Llama-3.1-8B-Instruct", "meta-llama/Llama-3.1-8B-Instruct", "granite"},
+		},
+		{
+			name:        "resource-name",
+			strategy:    models.IDStrategyResourceName,
+			expectedIDs: []string{"llama", "llama", "granite"},
+		},
+		{
+			name:        "namespaced resolves collisions across namespaces",
+			strategy:    models.IDStrategyNamespaced,
+			expectedIDs: []string{"team-a/llama", "team-b/llama", "team-b/granite"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, errMgr := models.NewManager(
+				testLogger,
+				fixtures.NewInferenceServiceLister(),
+				fixtures.NewLLMInferenceServiceLister(services...),
+				fixtures.NewHTTPRouteLister(),
+				models.ManagerOptions{IDStrategy: tt.strategy},
+				gateway,
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableLLMs()
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(availableModels))
+			for _, model := range availableModels {
+				ids = append(ids, model.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestListAvailableLLMs_OwnedBy(t *testing.T) {
+	testLogger := logger.Development()
+	gateway := models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectedOwner string
+	}{
+		{
+			name:          "defaults to namespace without annotation",
+			expectedOwner: "team-a-prod-ns",
+		},
+		{
+			name:          "annotation overrides namespace",
+			annotations:   map[string]string{constant.AnnotationOwnedBy: "Acme Research"},
+			expectedOwner: "Acme Research",
+		},
+		{
+			name:          "annotation is stripped of control characters and extra whitespace",
+			annotations:   map[string]string{constant.AnnotationOwnedBy: "  Acme\n\tResearch\x00 Lab  "},
+			expectedOwner: "Acme Research Lab",
+		},
+		{
+			name:          "annotation is truncated",
+			annotations:   map[string]string{constant.AnnotationOwnedBy: strings.Repeat("a", 100)},
+			expectedOwner: strings.Repeat("a", 64),
+		},
+		{
+			name:          "blank annotation falls back to namespace",
+			annotations:   map[string]string{constant.AnnotationOwnedBy: " \t "},
+			expectedOwner: "team-a-prod-ns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmService := &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-owned", Namespace: "team-a-prod-ns", Annotations: tt.annotations},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Gateway: &kservev1alpha1.GatewaySpec{
+							Refs: []kservev1alpha1.UntypedObjectReference{
+								{Name: "maas-gateway", Namespace: "gateway-ns"},
+							},
+						},
+					},
+				},
+			}
+
+			manager, errMgr := models.NewManager(
+				testLogger,
+				fixtures.NewInferenceServiceLister(),
+				fixtures.NewLLMInferenceServiceLister(llmService),
+				fixtures.NewHTTPRouteLister(),
+				models.ManagerOptions{},
+				gateway,
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableLLMs()
+			require.NoError(t, err)
+			require.Len(t, availableModels, 1)
+
+			assert.Equal(t, tt.expectedOwner, availableModels[0].OwnedBy)
+		})
+	}
+}
+
+func TestListAvailableLLMs_SupportsStreaming(t *testing.T) {
+	testLogger := logger.Development()
+	gateway := models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "defaults to true without annotation",
+			expected: true,
+		},
+		{
+			name:        "annotated true",
+			annotations: map[string]string{constant.AnnotationStreaming: "true"},
+			expected:    true,
+		},
+		{
+			name:        "annotated false",
+			annotations: map[string]string{constant.AnnotationStreaming: "false"},
+			expected:    false,
+		},
+		{
+			name:        "invalid annotation keeps the default",
+			annotations: map[string]string{constant.AnnotationStreaming: "sometimes"},
+			expected:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmService := &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-streaming", Namespace: "model-ns", Annotations: tt.annotations},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Gateway: &kservev1alpha1.GatewaySpec{
+							Refs: []kservev1alpha1.UntypedObjectReference{
+								{Name: "maas-gateway", Namespace: "gateway-ns"},
+							},
+						},
+					},
+				},
+			}
+
+			manager, errMgr := models.NewManager(
+				testLogger,
+				fixtures.NewInferenceServiceLister(),
+				fixtures.NewLLMInferenceServiceLister(llmService),
+				fixtures.NewHTTPRouteLister(),
+				models.ManagerOptions{},
+				gateway,
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableLLMs()
+			require.NoError(t, err)
+			require.Len(t, availableModels, 1)
+
+			require.NotNil(t, availableModels[0].SupportsStreaming)
+			assert.Equal(t, tt.expected, *availableModels[0].SupportsStreaming)
+		})
+	}
+}
+
+func TestListAvailableLLMs_Addresses(t *testing.T) {
+	const (
+		externalURL = "https://maas.example.com/llm/multi-address"
+		internalURL = "http://multi-address-kserve-workload-svc.llm-ns.svc.cluster.local:8000"
+	)
+
+	llmService := fixtures.CreateLLMInferenceService("multi-address", "llm-ns", true,
+		fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+		fixtures.WithURL(fixtures.PublicURL(externalURL)),
+		fixtures.WithAddresses(
+			fixtures.NamedAddress{Name: "gateway-external", URL: externalURL},
+			fixtures.NamedAddress{Name: "gateway-internal", URL: internalURL},
+			fixtures.NamedAddress{URL: "http://10.0.0.12:8000"},
+		),
+	)
+	singleAddress := fixtures.CreateLLMInferenceService("single-address", "llm-ns", true,
+		fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+		fixtures.WithURL(fixtures.PublicURL("https://maas.example.com/llm/single-address")),
+	)
+
+	manager, errMgr := models.NewManager(
+		logger.Development(),
+		fixtures.NewInferenceServiceLister(),
+		fixtures.NewLLMInferenceServiceLister(llmService, singleAddress),
+		fixtures.NewHTTPRouteLister(),
+		models.ManagerOptions{},
+		models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+	)
+	require.NoError(t, errMgr)
+
+	availableModels, err := manager.ListAvailableLLMs()
+	require.NoError(t, err)
+	require.Len(t, availableModels, 2)
+
+	byID := make(map[string]models.Model, len(availableModels))
+	for _, model := range availableModels {
+		byID[model.ID] = model
+	}
+
+	multi := byID["multi-address"]
+	require.NotNil(t, multi.URL)
+	assert.Equal(t, externalURL, multi.URL.String(), "URL must remain the primary external address")
+	require.Len(t, multi.Addresses, 3)
+	assert.Equal(t, "gateway-external", multi.Addresses[0].Name)
+	assert.Equal(t, externalURL, multi.Addresses[0].URL.String())
+	assert.Equal(t, "gateway-internal", multi.Addresses[1].Name)
+	assert.Equal(t, internalURL, multi.Addresses[1].URL.String())
+	assert.Empty(t, multi.Addresses[2].Name)
+	assert.Equal(t, "http://10.0.0.12:8000", multi.Addresses[2].URL.String())
+
+	assert.Empty(t, byID["single-address"].Addresses, "models without status addresses must not report any")
+}
+
 func ptrTo[T any](v T) *T {
 	return &v
 }
+
+func TestListAvailableLLMs_Exposure(t *testing.T) {
+	gatewayParentRefs := []gwapiv1.ParentReference{
+		{Name: "other-gateway", Namespace: ptrTo(gwapiv1.Namespace("gateway-ns"))},
+		{Name: "maas-gateway", Namespace: ptrTo(gwapiv1.Namespace("gateway-ns"))},
+	}
+	maasGateway := models.ObjectRef{Namespace: "gateway-ns", Name: "maas-gateway"}
+
+	tests := []struct {
+		name       string
+		llmService *kservev1alpha1.LLMInferenceService
+		httpRoutes []*gwapiv1.HTTPRoute
+		expected   *models.Exposure
+	}{
+		{
+			name: "direct gateway reference",
+			llmService: &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-direct", Namespace: "test-ns"},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Gateway: &kservev1alpha1.GatewaySpec{
+							Refs: []kservev1alpha1.UntypedObjectReference{
+								{Name: "other-gateway", Namespace: "gateway-ns"},
+								{Name: "maas-gateway", Namespace: "gateway-ns"},
+							},
+						},
+					},
+				},
+			},
+			expected: &models.Exposure{Source: models.ExposureGatewayRef, Gateway: maasGateway},
+		},
+		{
+			name: "inline HTTPRoute spec",
+			llmService: &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-inline", Namespace: "test-ns"},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Route: &kservev1alpha1.GatewayRoutesSpec{
+							HTTP: &kservev1alpha1.HTTPRouteSpec{
+								Spec: &gwapiv1.HTTPRouteSpec{
+									CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gatewayParentRefs},
+									Hostnames:       []gwapiv1.Hostname{"inline.example.com"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &models.Exposure{
+				Source:  models.ExposureRouteSpec,
+				Gateway: maasGateway,
+				Route:   &models.RouteRef{Hostnames: []string{"inline.example.com"}},
+			},
+		},
+		{
+			name: "referenced HTTPRoute",
+			llmService: &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-ref", Namespace: "test-ns"},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Route: &kservev1alpha1.GatewayRoutesSpec{
+							HTTP: &kservev1alpha1.HTTPRouteSpec{
+								Refs: []corev1.LocalObjectReference{{Name: "missing-route"}, {Name: "my-route"}},
+							},
+						},
+					},
+				},
+			},
+			httpRoutes: []*gwapiv1.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "test-ns"},
+					Spec: gwapiv1.HTTPRouteSpec{
+						CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gatewayParentRefs},
+						Hostnames:       []gwapiv1.Hostname{"models.example.com", "llm.example.com"},
+					},
+				},
+			},
+			expected: &models.Exposure{
+				Source:  models.ExposureReferencedRoute,
+				Gateway: maasGateway,
+				Route: &models.RouteRef{
+					Namespace: "test-ns",
+					Name:      "my-route",
+					Hostnames: []string{"models.example.com", "llm.example.com"},
+				},
+			},
+		},
+		{
+			name: "managed HTTPRoute",
+			llmService: &kservev1alpha1.LLMInferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "llm-managed", Namespace: "test-ns"},
+				Spec: kservev1alpha1.LLMInferenceServiceSpec{
+					Router: &kservev1alpha1.RouterSpec{
+						Route: &kservev1alpha1.GatewayRoutesSpec{
+							HTTP: &kservev1alpha1.HTTPRouteSpec{},
+						},
+					},
+				},
+			},
+			httpRoutes: []*gwapiv1.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "llm-managed-kserve-route",
+						Namespace: "test-ns",
+						Labels: map[string]string{
+							"app.kubernetes.io/component": "llminferenceservice-router",
+							"app.kubernetes.io/name":      "llm-managed",
+							"app.kubernetes.io/part-of":   "llminferenceservice",
+						},
+					},
+					Spec: gwapiv1.HTTPRouteSpec{
+						CommonRouteSpec: gwapiv1.CommonRouteSpec{
+							// Parent reference without namespace defaults to the namespace of the route.
+							ParentRefs: []gwapiv1.ParentReference{{Name: "maas-gateway"}},
+						},
+					},
+				},
+			},
+			expected: &models.Exposure{
+				Source:  models.ExposureManagedRoute,
+				Gateway: models.ObjectRef{Namespace: "test-ns", Name: "maas-gateway"},
+				Route:   &models.RouteRef{Namespace: "test-ns", Name: "llm-managed-kserve-route"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, errMgr := models.NewManager(
+				logger.Development(),
+				fixtures.NewInferenceServiceLister(),
+				fixtures.NewLLMInferenceServiceLister(fixtures.ToRuntimeObjects([]*kservev1alpha1.LLMInferenceService{tt.llmService})...),
+				fixtures.NewHTTPRouteLister(fixtures.ToRuntimeObjects(tt.httpRoutes)...),
+				models.ManagerOptions{},
+				models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+				models.GatewayRef{Name: "maas-gateway", Namespace: "test-ns"},
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableLLMs()
+			require.NoError(t, err)
+			require.Len(t, availableModels, 1)
+
+			assert.Equal(t, tt.expected, availableModels[0].Exposure)
+		})
+	}
+}
+
+func TestListAvailableLLMs_State(t *testing.T) {
+	condition := func(condType apis.ConditionType, status corev1.ConditionStatus) apis.Condition {
+		return apis.Condition{Type: condType, Status: status}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []apis.Condition
+		deleted    bool
+		stale      bool
+		expected   models.State
+	}{
+		{
+			name: "all conditions true",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.MainWorkloadReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.RouterReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.WorkloadReady, corev1.ConditionTrue),
+			},
+			expected: models.StateReady,
+		},
+		{
+			name: "worker workload not ready",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.MainWorkloadReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.WorkerWorkloadReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.RouterReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.WorkloadReady, corev1.ConditionFalse),
+			},
+			expected: models.StateDegraded,
+		},
+		{
+			name: "non-blocking condition not ready",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.InferencePoolReady, corev1.ConditionUnknown),
+			},
+			expected: models.StateDegraded,
+		},
+		{
+			name: "main workload not ready",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.MainWorkloadReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.RouterReady, corev1.ConditionTrue),
+			},
+			expected: models.StateNotReady,
+		},
+		{
+			name: "router not ready",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.MainWorkloadReady, corev1.ConditionTrue),
+				condition(kservev1alpha1.RouterReady, corev1.ConditionFalse),
+			},
+			expected: models.StateNotReady,
+		},
+		{
+			name: "serving conditions missing",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionFalse),
+				condition(kservev1alpha1.WorkloadReady, corev1.ConditionFalse),
+			},
+			expected: models.StateNotReady,
+		},
+		{
+			name:     "no conditions",
+			expected: models.StateNotReady,
+		},
+		{
+			name: "stale observed generation",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionTrue),
+			},
+			stale:    true,
+			expected: models.StateNotReady,
+		},
+		{
+			name: "being deleted",
+			conditions: []apis.Condition{
+				condition(apis.ConditionReady, corev1.ConditionTrue),
+			},
+			deleted:  true,
+			expected: models.StateNotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmService := fixtures.CreateLLMInferenceService("state-llm", "llm-ns", true,
+				fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+			)
+			llmService.Status.Conditions = tt.conditions
+			if tt.stale {
+				llmService.Generation = 2
+			}
+			if tt.deleted {
+				llmService.DeletionTimestamp = ptrTo(metav1.Now())
+			}
+
+			manager, errMgr := models.NewManager(
+				logger.Development(),
+				fixtures.NewInferenceServiceLister(),
+				fixtures.NewLLMInferenceServiceLister(llmService),
+				fixtures.NewHTTPRouteLister(),
+				models.ManagerOptions{},
+				models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableLLMs()
+			require.NoError(t, err)
+			require.Len(t, availableModels, 1)
+
+			assert.Equal(t, tt.expected, availableModels[0].State)
+			assert.Equal(t, tt.expected == models.StateReady, availableModels[0].Ready)
+		})
+	}
+}
+
+func TestSummarizeLLMs(t *testing.T) {
+	degraded := fixtures.CreateLLMInferenceService("degraded-llm", "team-b", true,
+		fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+	)
+	degraded.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse},
+		{Type: kservev1alpha1.MainWorkloadReady, Status: corev1.ConditionTrue},
+		{Type: kservev1alpha1.WorkerWorkloadReady, Status: corev1.ConditionFalse},
+		{Type: kservev1alpha1.RouterReady, Status: corev1.ConditionTrue},
+	}
+
+	llmServices := []*kservev1alpha1.LLMInferenceService{
+		fixtures.CreateLLMInferenceService("ready-llm", "team-a", true,
+			fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+		),
+		fixtures.CreateLLMInferenceService("hidden-llm", "team-a", true,
+			fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+			fixtures.WithAnnotations(map[string]string{constant.AnnotationVisibility: "hidden"}),
+		),
+		fixtures.CreateLLMInferenceService("starting-llm", "team-a", false,
+			fixtures.WithGatewaySpec("maas-gateway", "gateway-ns"),
+		),
+		fixtures.CreateLLMInferenceService("unattached-llm", "team-a", true,
+			fixtures.WithGatewaySpec("other-gateway", "gateway-ns"),
+		),
+		degraded,
+		fixtures.CreateLLMInferenceService("failed-llm", "team-b", false),
+	}
+
+	manager, errMgr := models.NewManager(
+		logger.Development(),
+		fixtures.NewInferenceServiceLister(),
+		fixtures.NewLLMInferenceServiceLister(fixtures.ToRuntimeObjects(llmServices)...),
+		fixtures.NewHTTPRouteLister(),
+		models.ManagerOptions{},
+		models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+	)
+	require.NoError(t, errMgr)
+
+	summaries, err := manager.SummarizeLLMs()
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.NamespaceSummary{
+		{
+			Namespace: "team-a",
+			Ready:     3,
+			NotReady:  1,
+			Models: []models.ModelSummary{
+				{Name: "hidden-llm", State: models.StateReady, Attached: true},
+				{Name: "ready-llm", State: models.StateReady, Attached: true},
+				{Name: "starting-llm", State: models.StateNotReady, Attached: true},
+				{Name: "unattached-llm", State: models.StateReady, Attached: false},
+			},
+		},
+		{
+			Namespace: "team-b",
+			Degraded:  1,
+			NotReady:  1,
+			Models: []models.ModelSummary{
+				{Name: "degraded-llm", State: models.StateDegraded, Attached: true},
+				{Name: "failed-llm", State: models.StateNotReady, Attached: false},
+			},
+		},
+	}, summaries)
+}
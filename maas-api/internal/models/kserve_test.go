@@ -0,0 +1,121 @@
+package models_test
+
+import (
+	"testing"
+
+	kservev1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
+)
+
+func TestListAvailableModels_State(t *testing.T) {
+	servingConditions := func(predictor, ingress corev1.ConditionStatus) []apis.Condition {
+		ready := corev1.ConditionFalse
+		if predictor == corev1.ConditionTrue && ingress == corev1.ConditionTrue {
+			ready = corev1.ConditionTrue
+		}
+		return []apis.Condition{
+			{Type: apis.ConditionReady, Status: ready},
+			{Type: kservev1beta1.PredictorReady, Status: predictor},
+			{Type: kservev1beta1.IngressReady, Status: ingress},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []apis.Condition
+		copies     *kservev1beta1.ModelCopies
+		expected   models.State
+	}{
+		{
+			name:       "all conditions true",
+			conditions: servingConditions(corev1.ConditionTrue, corev1.ConditionTrue),
+			expected:   models.StateReady,
+		},
+		{
+			name:       "all copies loaded",
+			conditions: servingConditions(corev1.ConditionTrue, corev1.ConditionTrue),
+			copies:     &kservev1beta1.ModelCopies{FailedCopies: 0, TotalCopies: 3},
+			expected:   models.StateReady,
+		},
+		{
+			name:       "some copies failed",
+			conditions: servingConditions(corev1.ConditionTrue, corev1.ConditionTrue),
+			copies:     &kservev1beta1.ModelCopies{FailedCopies: 1, TotalCopies: 3},
+			expected:   models.StateDegraded,
+		},
+		{
+			name:       "all copies failed",
+			conditions: servingConditions(corev1.ConditionTrue, corev1.ConditionTrue),
+			copies:     &kservev1beta1.ModelCopies{FailedCopies: 3, TotalCopies: 3},
+			expected:   models.StateNotReady,
+		},
+		{
+			name: "transformer not ready",
+			conditions: []apis.Condition{
+				{Type: apis.ConditionReady, Status: corev1.ConditionFalse},
+				{Type: kservev1beta1.PredictorReady, Status: corev1.ConditionTrue},
+				{Type: kservev1beta1.IngressReady, Status: corev1.ConditionTrue},
+				{Type: kservev1beta1.TransformerReady, Status: corev1.ConditionFalse},
+			},
+			expected: models.StateDegraded,
+		},
+		{
+			name:       "predictor not ready",
+			conditions: servingConditions(corev1.ConditionFalse, corev1.ConditionTrue),
+			expected:   models.StateNotReady,
+		},
+		{
+			name:       "predictor not ready with failed copies",
+			conditions: servingConditions(corev1.ConditionFalse, corev1.ConditionTrue),
+			copies:     &kservev1beta1.ModelCopies{FailedCopies: 1, TotalCopies: 3},
+			expected:   models.StateNotReady,
+		},
+		{
+			name:       "ingress not ready",
+			conditions: servingConditions(corev1.ConditionTrue, corev1.ConditionUnknown),
+			expected:   models.StateNotReady,
+		},
+		{
+			name:     "no conditions",
+			expected: models.StateNotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isvc := &kservev1beta1.InferenceService{
+				ObjectMeta: metav1.ObjectMeta{Name: "state-isvc", Namespace: "model-ns", Generation: 1},
+				Status: kservev1beta1.InferenceServiceStatus{
+					Status:      duckv1.Status{ObservedGeneration: 1, Conditions: tt.conditions},
+					ModelStatus: kservev1beta1.ModelStatus{ModelCopies: tt.copies},
+				},
+			}
+
+			manager, errMgr := models.NewManager(
+				logger.Development(),
+				fixtures.NewInferenceServiceLister(isvc),
+				fixtures.NewLLMInferenceServiceLister(),
+				fixtures.NewHTTPRouteLister(),
+				models.ManagerOptions{},
+				models.GatewayRef{Name: "maas-gateway", Namespace: "gateway-ns"},
+			)
+			require.NoError(t, errMgr)
+
+			availableModels, err := manager.ListAvailableModels()
+			require.NoError(t, err)
+			require.Len(t, availableModels, 1)
+
+			assert.Equal(t, tt.expected, availableModels[0].State)
+			assert.Equal(t, tt.expected == models.StateReady, availableModels[0].Ready)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/openai/openai-go/v2"
 	"knative.dev/pkg/apis"
@@ -15,15 +17,137 @@ type Details struct {
 	GenAIUseCase string `json:"genaiUseCase,omitempty"`
 	Description  string `json:"description,omitempty"`
 	DisplayName  string `json:"displayName,omitempty"`
+	// Family groups the variants of a model, e.g. llama, see Model.Family.
+	Family string `json:"family,omitempty"`
+}
+
+// Visibility determines who can see a model in listings.
+type Visibility string
+
+const (
+	// VisibilityPublic models are listed for every caller.
+	VisibilityPublic Visibility = "public"
+	// VisibilityInternal models are listed only for callers in one of the admin groups.
+	VisibilityInternal Visibility = "internal"
+	// VisibilityHidden models are never listed.
+	VisibilityHidden Visibility = "hidden"
+)
+
+// State is the serving state of a model, see Model.State.
+type State string
+
+const (
+	// StateReady models are fully serving.
+	StateReady State = "ready"
+	// StateDegraded models serve requests, but some of their components or model copies are not ready.
+	StateDegraded State = "degraded"
+	// StateNotReady models cannot serve requests.
+	StateNotReady State = "not_ready"
+)
+
+// ModelAddress is one of the addresses a model is reachable at, e.g. the external or the in-cluster one.
+type ModelAddress struct {
+	Name string    `json:"name,omitempty"`
+	URL  *apis.URL `json:"url"`
+}
+
+// Sources of the exposure of a model through a MaaS gateway, see Exposure.Source.
+const (
+	// ExposureGatewayRef models reference the gateway directly in spec.router.gateway.refs.
+	ExposureGatewayRef = "gatewayRef"
+	// ExposureRouteSpec models have an inline HTTPRoute spec, in spec.router.route.http.spec, attached to the gateway.
+	ExposureRouteSpec = "routeSpec"
+	// ExposureReferencedRoute models reference an HTTPRoute attached to the gateway in spec.router.route.http.refs.
+	ExposureReferencedRoute = "referencedRoute"
+	// ExposureManagedRoute models have an HTTPRoute managed by KServe attached to the gateway.
+	ExposureManagedRoute = "managedRoute"
+)
+
+// ObjectRef identifies a namespaced Kubernetes object.
+type ObjectRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// RouteRef identifies the HTTPRoute a model is exposed through. Inline routes have no namespace and name.
+type RouteRef struct {
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+// Exposure describes how a model is exposed through one of the MaaS gateways, i.e. why it is part of the instance.
+type Exposure struct {
+	Source  string    `json:"source"`
+	Gateway ObjectRef `json:"gateway"`
+	Route   *RouteRef `json:"route,omitempty"`
 }
 
 // Model extends openai.Model with additional fields.
 type Model struct {
 	openai.Model `json:",inline"`
 
-	URL     *apis.URL `json:"url,omitempty"`
-	Ready   bool      `json:"ready"`
-	Details *Details  `json:"modelDetails,omitempty"`
+	// URL is the primary, external address of the model.
+	URL *apis.URL `json:"url,omitempty"`
+	// Addresses lists all addresses of the model, including in-cluster ones.
+	Addresses []ModelAddress `json:"addresses,omitempty"`
+	// EndpointPending is set for models without any URL yet, e.g. while their route is being provisioned.
+	EndpointPending bool `json:"endpointPending,omitempty"`
+	// SupportsStreaming reports whether the model serves streaming (SSE) completions. It is unset for models that
+	// do not report it, i.e. InferenceServices.
+	SupportsStreaming *bool `json:"supportsStreaming,omitempty"`
+	// Ready is kept for compatibility, it is true only when State is StateReady.
+	Ready   bool     `json:"ready"`
+	State   State    `json:"state"`
+	Details *Details `json:"modelDetails,omitempty"`
+	// ReadySince is when the model became ready, the last transition time of its Ready condition. It is unset for
+	// models that are not ready, or whose transition time is unknown.
+	ReadySince *time.Time `json:"-"`
+
+	Visibility Visibility `json:"-"`
+	// Exposure is only listed for admins requesting debug details.
+	Exposure *Exposure `json:"exposure,omitempty"`
+}
+
+// NamespaceSummary counts the models deployed in a namespace by state, see Manager.SummarizeLLMs.
+type NamespaceSummary struct {
+	Namespace string         `json:"namespace"`
+	Ready     int            `json:"ready"`
+	Degraded  int            `json:"degraded"`
+	NotReady  int            `json:"notReady"`
+	Models    []ModelSummary `json:"models"`
+}
+
+// ModelSummary is a model deployed in the cluster, whether or not it is part of the MaaS instance.
+type ModelSummary struct {
+	// Name is the name of the LLMInferenceService.
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// Attached reports whether the model is exposed through one of the MaaS gateways.
+	Attached bool `json:"attached"`
+}
+
+// Family returns the family of the model: the one set in its details if any, otherwise the leading letters
+// of the model ID without its organization prefix, e.g. "llama" for "meta-llama/Llama-3.1-8B-Instruct".
+// Returns an empty string when the ID does not start with a letter.
+func (m *Model) Family() string {
+	if m.Details != nil && m.Details.Family != "" {
+		return m.Details.Family
+	}
+
+	id := m.ID
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+
+	end := strings.IndexFunc(id, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end < 0 {
+		end = len(id)
+	}
+
+	return strings.ToLower(id[:end])
 }
 
 // UnmarshalJSON implements custom JSON unmarshalling to work around openai.Model's
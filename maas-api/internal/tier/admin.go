@@ -0,0 +1,185 @@
+package tier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+// ErrTierNotFound is returned when changing a tier missing from the tier configuration.
+var ErrTierNotFound = errors.New("tier not found")
+
+// ErrTierExists is returned when creating a tier whose name is already configured.
+var ErrTierExists = errors.New("tier already exists")
+
+// ErrConfigReadOnly is returned when changing tiers without a ConfigMap client, see MapperOptions.ConfigMapClient.
+var ErrConfigReadOnly = errors.New("tier configuration is read-only")
+
+// InvalidConfigError reports a change rejected because the resulting tier configuration would be invalid.
+type InvalidConfigError struct {
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return "invalid tier configuration: " + e.Err.Error()
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// CreateTier appends the tier to the tier configuration, creating the tier mapping ConfigMap when it does not exist.
+// Its groups must not be mapped to another tier already.
+func (m *Mapper) CreateTier(ctx context.Context, tier Tier) error {
+	return m.changeConfig(ctx, func(tiers []Tier) ([]Tier, error) {
+		if slices.ContainsFunc(tiers, func(t Tier) bool { return t.Name == tier.Name }) {
+			return nil, fmt.Errorf("%w: %s", ErrTierExists, tier.Name)
+		}
+		if err := checkGroupsAvailable(tiers, tier); err != nil {
+			return nil, err
+		}
+		return append(tiers, tier), nil
+	})
+}
+
+// UpdateTier replaces the configuration of the tier of the same name, keeping its position.
+// Its groups must not be mapped to another tier.
+func (m *Mapper) UpdateTier(ctx context.Context, tier Tier) error {
+	return m.changeConfig(ctx, func(tiers []Tier) ([]Tier, error) {
+		i := slices.IndexFunc(tiers, func(t Tier) bool { return t.Name == tier.Name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTierNotFound, tier.Name)
+		}
+		if err := checkGroupsAvailable(tiers, tier); err != nil {
+			return nil, err
+		}
+		tiers[i] = tier
+		return tiers, nil
+	})
+}
+
+// DeleteTier removes the tier from the tier configuration. Its namespace and Service Accounts are left untouched,
+// tokens already issued for them stay valid until they expire or are revoked.
+func (m *Mapper) DeleteTier(ctx context.Context, name string) error {
+	return m.changeConfig(ctx, func(tiers []Tier) ([]Tier, error) {
+		i := slices.IndexFunc(tiers, func(t Tier) bool { return t.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTierNotFound, name)
+		}
+		return slices.Delete(tiers, i, i+1), nil
+	})
+}
+
+// checkGroupsAvailable returns an InvalidConfigError when one of the groups of the tier is mapped to another tier.
+// Tier resolution would otherwise depend on levels and ordering in ways that are easy to get wrong through the API.
+func checkGroupsAvailable(tiers []Tier, tier Tier) error {
+	for _, other := range tiers {
+		if other.Name == tier.Name {
+			continue
+		}
+		for _, group := range slices.Concat(tier.Groups, tier.CatchAllGroups) {
+			if slices.Contains(other.Groups, group) || slices.Contains(other.CatchAllGroups, group) {
+				return &InvalidConfigError{Err: fmt.Errorf("group %q is already mapped to tier %q", group, other.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+// changeConfig applies the change to the tiers of the tier mapping ConfigMap and writes them back in the format,
+// YAML or JSON, they were stored in. A missing ConfigMap holds no tiers, it is created when the change adds some.
+// The change is retried on the latest ConfigMap on write conflicts.
+// Comments in the stored configuration are not preserved.
+func (m *Mapper) changeConfig(ctx context.Context, change func([]Tier) ([]Tier, error)) error {
+	if m.configMapClient == nil {
+		return ErrConfigReadOnly
+	}
+	configMaps := m.configMapClient.ConfigMaps(m.namespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, constant.TierMappingConfigMap, metav1.GetOptions{})
+		missing := k8serrors.IsNotFound(err)
+		if err != nil && !missing {
+			return fmt.Errorf("failed to get tier configuration: %w", err)
+		}
+
+		var tiers []Tier
+		key := ConfigKeyYAML
+		switch {
+		case missing:
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      constant.TierMappingConfigMap,
+				Namespace: m.namespace,
+			}}
+		case hasConfig(cm.Data):
+			if _, hasJSON := cm.Data[ConfigKeyJSON]; hasJSON {
+				key = ConfigKeyJSON
+			}
+			tiers, err = ParseConfig(cm.Data)
+			if err != nil {
+				return fmt.Errorf("failed to load the current tier configuration: %w", err)
+			}
+		}
+
+		changed, err := change(tiers)
+		if err != nil {
+			return err
+		}
+		if errValidate := validateTierConfig(changed); errValidate != nil {
+			return &InvalidConfigError{Err: errValidate}
+		}
+
+		data, err := marshalConfig(changed, key)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string, 1)
+		}
+		cm.Data[key] = data
+
+		if missing {
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+		} else {
+			_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write tier configuration: %w", err)
+		}
+		return nil
+	})
+}
+
+func hasConfig(data map[string]string) bool {
+	_, hasYAML := data[ConfigKeyYAML]
+	_, hasJSON := data[ConfigKeyJSON]
+	return hasYAML || hasJSON
+}
+
+func marshalConfig(tiers []Tier, key string) (string, error) {
+	if tiers == nil {
+		tiers = []Tier{}
+	}
+
+	var data []byte
+	var err error
+	if key == ConfigKeyJSON {
+		data, err = json.MarshalIndent(tiers, "", "  ")
+	} else {
+		data, err = yaml.Marshal(tiers)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize tier configuration: %w", err)
+	}
+	return string(data), nil
+}
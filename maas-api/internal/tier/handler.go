@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 )
 
 type Handler struct {
@@ -52,15 +54,135 @@ func (h *Handler) TierLookup(c *gin.Context) {
 		return
 	}
 
-	displayName := tier.DisplayName
-	if displayName == "" {
-		displayName = tier.Name
-	}
-
 	response := LookupResponse{
 		Tier:        tier.Name,
-		DisplayName: displayName,
+		DisplayName: tier.displayName(),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// ListTiers handles GET /v1/tiers, listing the configured tiers in the order of the tier configuration.
+// The list is empty when the tier mapping ConfigMap does not exist.
+func (h *Handler) ListTiers(c *gin.Context) {
+	tiers, err := h.mapper.ListTiers()
+	if err != nil {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to list tiers: "+err.Error())
+		return
+	}
+
+	infos := make([]Info, 0, len(tiers))
+	for _, tier := range tiers {
+		groups := tier.Groups
+		if groups == nil {
+			groups = []string{}
+		}
+		infos = append(infos, Info{
+			Name:           tier.Name,
+			DisplayName:    tier.displayName(),
+			Description:    tier.Description,
+			Level:          tier.Level,
+			Groups:         groups,
+			CatchAllGroups: tier.CatchAllGroups,
+			MaxExpiration:  tier.MaxExpiration,
+		})
+	}
+
+	c.JSON(http.StatusOK, ListResponse{
+		Object: "list",
+		Data:   infos,
+	})
+}
+
+// ListTierConfig handles GET /v1/admin/tiers, listing the tiers as configured, including the namespaces and
+// resource quotas left out of the public listing.
+func (h *Handler) ListTierConfig(c *gin.Context) {
+	tiers, err := h.mapper.ListTiers()
+	if err != nil {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to list tiers: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ConfigResponse{
+		Object: "list",
+		Data:   tiers,
+	})
+}
+
+// CreateTier handles POST /v1/admin/tiers, adding a tier to the tier mapping ConfigMap.
+func (h *Handler) CreateTier(c *gin.Context) {
+	var req Tier
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.mapper.CreateTier(c.Request.Context(), req); err != nil {
+		h.writeConfigError(c, err)
+		return
+	}
+
+	h.mapper.logger.Info("Created tier",
+		"tier", req.Name,
+	)
+	c.JSON(http.StatusCreated, req)
+}
+
+// UpdateTier handles PUT /v1/admin/tiers/:name, replacing the configuration of the tier. The name in the body,
+// if any, must match the one in the path: tiers cannot be renamed.
+func (h *Handler) UpdateTier(c *gin.Context) {
+	var req Tier
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	name := c.Param("name")
+	if req.Name != "" && req.Name != name {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest,
+			"The tier name in the body must match the one in the path, tiers cannot be renamed")
+		return
+	}
+	req.Name = name
+
+	if err := h.mapper.UpdateTier(c.Request.Context(), req); err != nil {
+		h.writeConfigError(c, err)
+		return
+	}
+
+	h.mapper.logger.Info("Updated tier",
+		"tier", name,
+	)
+	c.JSON(http.StatusOK, req)
+}
+
+// DeleteTier handles DELETE /v1/admin/tiers/:name, removing the tier from the tier mapping ConfigMap.
+func (h *Handler) DeleteTier(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.mapper.DeleteTier(c.Request.Context(), name); err != nil {
+		h.writeConfigError(c, err)
+		return
+	}
+
+	h.mapper.logger.Info("Deleted tier",
+		"tier", name,
+	)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) writeConfigError(c *gin.Context, err error) {
+	var invalidErr *InvalidConfigError
+	switch {
+	case errors.As(err, &invalidErr):
+		apierror.Write(c, http.StatusUnprocessableEntity, apierror.CodeValidationFailed, err.Error())
+	case errors.Is(err, ErrTierNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, err.Error())
+	case errors.Is(err, ErrTierExists):
+		apierror.Write(c, http.StatusConflict, apierror.CodeConflict, err.Error())
+	default:
+		h.mapper.logger.Error("Failed to change tier configuration",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to change tier configuration")
+	}
+}
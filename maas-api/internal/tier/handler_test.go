@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
@@ -229,7 +231,7 @@ func TestHandler_PostTierLookup_DisplayNameFallback(t *testing.T) {
 		},
 	}
 
-	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), fixtures.TestTenant, fixtures.TestNamespace)
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{})
 	router := fixtures.SetupTierTestRouter(mapper)
 
 	reqBody := tier.LookupRequest{Groups: []string{"basic-users"}}
@@ -257,3 +259,238 @@ func TestHandler_PostTierLookup_DisplayNameFallback(t *testing.T) {
 		t.Errorf("expected displayName to fall back to 'basic', got %s", response.DisplayName)
 	}
 }
+
+func TestHandler_ListTiers(t *testing.T) {
+	mapper := createTestMapper(true)
+	router := fixtures.SetupTierTestRouter(mapper)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/tiers", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response tier.ListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	expected := []tier.Info{
+		{Name: "free", DisplayName: "Free Tier", Description: "Free tier", Level: 1, Groups: []string{"system:authenticated", "free-users"}, MaxExpiration: "2160h"},
+		{Name: "premium", DisplayName: "Premium Tier", Description: "Premium tier", Level: 10, Groups: []string{"premium-users", "beta-testers"}},
+		{Name: "developer", DisplayName: "Developer Tier", Description: "Developer tier", Level: 15, Groups: []string{"developer-users"}},
+		{Name: "enterprise", DisplayName: "Enterprise Tier", Description: "Enterprise tier", Level: 20, Groups: []string{"enterprise-users", "admin-users"}},
+	}
+
+	if response.Object != "list" {
+		t.Errorf("expected object 'list', got %q", response.Object)
+	}
+	if !reflect.DeepEqual(expected, response.Data) {
+		t.Errorf("expected tiers %+v, got %+v", expected, response.Data)
+	}
+}
+
+func TestHandler_ListTiers_ConfigMapMissing(t *testing.T) {
+	mapper := createTestMapper(false) // No ConfigMap
+	router := fixtures.SetupTierTestRouter(mapper)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/tiers", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// An empty list, rather than null, so that clients can iterate it unconditionally.
+	expected := `{"object":"list","data":[]}`
+	if w.Body.String() != expected {
+		t.Errorf("expected %s, got %s", expected, w.Body.String())
+	}
+}
+
+func TestHandler_AdminTiers(t *testing.T) {
+	tests := []struct {
+		name          string
+		withConfigMap bool
+		method        string
+		path          string
+		body          string
+		expectedCode  int
+		expectedTiers []string
+		check         func(t *testing.T, tiers []tier.Tier)
+	}{
+		{
+			name:          "create a tier",
+			withConfigMap: true,
+			method:        http.MethodPost,
+			path:          "/admin/tiers",
+			body:          `{"name":"team","level":5,"groups":["team-users"],"maxExpiration":"24h"}`,
+			expectedCode:  http.StatusCreated,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise", "team"},
+			check: func(t *testing.T, tiers []tier.Tier) {
+				t.Helper()
+				created := tiers[4]
+				if created.Level != 5 || created.MaxExpiration != "24h" || !reflect.DeepEqual(created.Groups, []string{"team-users"}) {
+					t.Errorf("unexpected created tier %+v", created)
+				}
+			},
+		},
+		{
+			name:          "create the first tier without ConfigMap",
+			method:        http.MethodPost,
+			path:          "/admin/tiers",
+			body:          `{"name":"team","groups":["team-users"]}`,
+			expectedCode:  http.StatusCreated,
+			expectedTiers: []string{"team"},
+		},
+		{
+			name:          "create a duplicate tier",
+			withConfigMap: true,
+			method:        http.MethodPost,
+			path:          "/admin/tiers",
+			body:          `{"name":"premium","groups":["team-users"]}`,
+			expectedCode:  http.StatusConflict,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "create a tier with groups of another tier",
+			withConfigMap: true,
+			method:        http.MethodPost,
+			path:          "/admin/tiers",
+			body:          `{"name":"team","groups":["team-users","premium-users"]}`,
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "create an invalid tier",
+			withConfigMap: true,
+			method:        http.MethodPost,
+			path:          "/admin/tiers",
+			body:          `{"name":"team","groups":["team-users"],"maxExpiration":"-1h"}`,
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "update a tier",
+			withConfigMap: true,
+			method:        http.MethodPut,
+			path:          "/admin/tiers/premium",
+			body:          `{"displayName":"Premium","level":12,"groups":["premium-users"]}`,
+			expectedCode:  http.StatusOK,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+			check: func(t *testing.T, tiers []tier.Tier) {
+				t.Helper()
+				updated := tiers[1]
+				if updated.DisplayName != "Premium" || updated.Level != 12 || !reflect.DeepEqual(updated.Groups, []string{"premium-users"}) {
+					t.Errorf("unexpected updated tier %+v", updated)
+				}
+			},
+		},
+		{
+			name:          "update a tier with groups of another tier",
+			withConfigMap: true,
+			method:        http.MethodPut,
+			path:          "/admin/tiers/premium",
+			body:          `{"level":10,"groups":["premium-users","enterprise-users"]}`,
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "rename a tier",
+			withConfigMap: true,
+			method:        http.MethodPut,
+			path:          "/admin/tiers/premium",
+			body:          `{"name":"gold","groups":["premium-users"]}`,
+			expectedCode:  http.StatusBadRequest,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "update a missing tier",
+			withConfigMap: true,
+			method:        http.MethodPut,
+			path:          "/admin/tiers/gold",
+			body:          `{"groups":["gold-users"]}`,
+			expectedCode:  http.StatusNotFound,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+		{
+			name:          "delete a tier",
+			withConfigMap: true,
+			method:        http.MethodDelete,
+			path:          "/admin/tiers/developer",
+			expectedCode:  http.StatusNoContent,
+			expectedTiers: []string{"free", "premium", "enterprise"},
+		},
+		{
+			name:          "delete a missing tier",
+			withConfigMap: true,
+			method:        http.MethodDelete,
+			path:          "/admin/tiers/gold",
+			expectedCode:  http.StatusNotFound,
+			expectedTiers: []string{"free", "premium", "developer", "enterprise"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := k8sfake.NewClientset()
+			if tt.withConfigMap {
+				if _, err := fakeClient.CoreV1().ConfigMaps(fixtures.TestNamespace).Create(
+					t.Context(), fixtures.CreateTierConfigMap(fixtures.TestNamespace), metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create tier ConfigMap: %v", err)
+				}
+			}
+			var configMaps []*corev1.ConfigMap
+			if tt.withConfigMap {
+				configMaps = append(configMaps, fixtures.CreateTierConfigMap(fixtures.TestNamespace))
+			}
+			mapper := tier.NewMapper(logger.Development(), fixtures.NewConfigMapLister(configMaps...),
+				fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{ConfigMapClient: fakeClient.CoreV1()})
+			router := fixtures.SetupTierTestRouter(mapper)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequestWithContext(t.Context(), tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+
+			cm, err := fakeClient.CoreV1().ConfigMaps(fixtures.TestNamespace).Get(t.Context(), constant.TierMappingConfigMap, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get tier ConfigMap: %v", err)
+			}
+			tiers, err := tier.ParseConfig(cm.Data)
+			if err != nil {
+				t.Fatalf("stored tier configuration is invalid: %v", err)
+			}
+
+			names := make([]string, 0, len(tiers))
+			for _, stored := range tiers {
+				names = append(names, stored.Name)
+			}
+			if !reflect.DeepEqual(tt.expectedTiers, names) {
+				t.Errorf("expected tiers %v, got %v", tt.expectedTiers, names)
+			}
+			if tt.check != nil {
+				tt.check(t, tiers)
+			}
+		})
+	}
+}
+
+func TestHandler_AdminTiers_ReadOnly(t *testing.T) {
+	router := fixtures.SetupTierTestRouter(createTestMapper(true))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodDelete, "/admin/tiers/free", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d without a ConfigMap client, got %d: %s", http.StatusInternalServerError, w.Code, w.Body.String())
+	}
+}
@@ -9,6 +9,32 @@ type LookupResponse struct {
 	DisplayName string `json:"displayName"`
 }
 
+// Info describes a tier in the tier listing. It leaves out the namespace of the tier, an implementation detail
+// of the cluster, as well as the groups of the Service Accounts maas-api issues tokens for.
+type Info struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Description string   `json:"description,omitempty"`
+	Level       int      `json:"level"`
+	Groups      []string `json:"groups"`
+	// CatchAllGroups map users to the tier only when no other tier lists any of their groups.
+	CatchAllGroups []string `json:"catchAllGroups,omitempty"`
+	// MaxExpiration is the longest lifetime of tokens issued to the tier, absent when it is not limited.
+	MaxExpiration string `json:"maxExpiration,omitempty"`
+}
+
+// ListResponse is the tier listing, in the list envelope of the models endpoint.
+type ListResponse struct {
+	Object string `json:"object"`
+	Data   []Info `json:"data"`
+}
+
+// ConfigResponse is the tier configuration as stored in the tier mapping ConfigMap, listed to admins.
+type ConfigResponse struct {
+	Object string `json:"object"`
+	Data   []Tier `json:"data"`
+}
+
 type ErrorResponse struct {
 	Error   string `json:"error"`   // Error code (e.g., "bad_request", "not_found")
 	Message string `json:"message"` // Human-readable error message
@@ -1,29 +1,55 @@
 package tier
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 )
 
+// Keys of the tier mapping ConfigMap holding the tier configuration, in YAML or JSON format.
+const (
+	ConfigKeyYAML = "tiers"
+	ConfigKeyJSON = "tiers.json"
+)
+
 // Mapper handles tier-to-group mapping lookups.
 type Mapper struct {
 	tenantName      string
 	namespace       string
 	configMapLister corelisters.ConfigMapLister
+	// configMapClient writes the tier mapping ConfigMap, see MapperOptions.ConfigMapClient.
+	configMapClient corev1client.ConfigMapsGetter
 	logger          *logger.Logger
 }
 
-func NewMapper(log *logger.Logger, configMapLister corelisters.ConfigMapLister, tenantName, namespace string) *Mapper {
+// MapperOptions configures how the Mapper manages the tier configuration.
+type MapperOptions struct {
+	// ConfigMapClient enables changes to the tier configuration through CreateTier, UpdateTier and DeleteTier,
+	// which write the tier mapping ConfigMap with the client. Without it, the configuration is read-only.
+	ConfigMapClient corev1client.ConfigMapsGetter
+}
+
+func NewMapper(
+	log *logger.Logger,
+	configMapLister corelisters.ConfigMapLister,
+	tenantName, namespace string,
+	options MapperOptions,
+) *Mapper {
 	if log == nil {
 		log = logger.Production()
 	}
@@ -31,6 +57,7 @@ func NewMapper(log *logger.Logger, configMapLister corelisters.ConfigMapLister,
 		tenantName:      tenantName,
 		namespace:       namespace,
 		configMapLister: configMapLister,
+		configMapClient: options.ConfigMapClient,
 		logger:          log,
 	}
 }
@@ -50,7 +77,24 @@ func (m *Mapper) Namespace(tier string) (string, error) {
 	return "", fmt.Errorf("tier %s not found", tier)
 }
 
+// TierForNamespace returns the name of the tier whose projected namespace is the given one.
+func (m *Mapper) TierForNamespace(namespace string) (string, error) {
+	tiers, err := m.loadTierConfig()
+	if err != nil {
+		return "", err
+	}
+
+	for i := range tiers {
+		if m.ProjectedNsName(&tiers[i]) == namespace {
+			return tiers[i].Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no tier found for namespace %s", namespace)
+}
+
 // GetTierForGroups returns the highest level tier for a user with multiple group memberships.
+// The catch-all groups of the tiers are only matched when no tier lists any of the groups in its groups.
 //
 // Returns error if no groups provided or no groups found in any tier.
 func (m *Mapper) GetTierForGroups(groups ...string) (*Tier, error) {
@@ -74,14 +118,28 @@ func (m *Mapper) GetTierForGroups(groups ...string) (*Tier, error) {
 		return tiers[i].Level > tiers[j].Level
 	})
 
-	for i := range tiers {
-		for _, userGroup := range groups {
-			if slices.Contains(tiers[i].Groups, userGroup) {
-				return &tiers[i], nil
+	// Catch-all groups are only considered when none of the groups is specific to a tier.
+	for _, catchAll := range []bool{false, true} {
+		for i := range tiers {
+			tierGroups := tiers[i].Groups
+			if catchAll {
+				tierGroups = tiers[i].CatchAllGroups
+			}
+			for _, userGroup := range groups {
+				if slices.Contains(tierGroups, userGroup) {
+					recordResolution(tiers[i].Name, false)
+					return &tiers[i], nil
+				}
 			}
 		}
 	}
 
+	recordResolution(noTier, true)
+	m.logger.Info("No tier is mapped to the user groups, check the tier ConfigMap",
+		"configmap", constant.TierMappingConfigMap,
+		"groups", groups,
+	)
+
 	return nil, &GroupNotFoundError{Group: fmt.Sprintf("groups [%s]", strings.Join(groups, ", "))}
 }
 
@@ -90,27 +148,77 @@ func (m *Mapper) ProjectedSAGroup(tier *Tier) string {
 	return fmt.Sprintf("system:serviceaccounts:%s", m.ProjectedNsName(tier))
 }
 
+// ProjectedNsName returns the namespace of a tier: the one set in the tier configuration, if any,
+// or {instance}-tier-{tier} otherwise.
 func (m *Mapper) ProjectedNsName(tier *Tier) string {
+	if tier.Namespace != "" {
+		return tier.Namespace
+	}
 	return fmt.Sprintf("%s-tier-%s", m.tenantName, tier.Name)
 }
 
+// ListTiers returns the tiers in the order of the tier configuration, with the groups configured for them.
+// Returns an empty list when the tier mapping ConfigMap does not exist.
+func (m *Mapper) ListTiers() ([]Tier, error) {
+	cm, err := m.configMapLister.ConfigMaps(m.namespace).Get(constant.TierMappingConfigMap)
+	if k8serrors.IsNotFound(err) {
+		return []Tier{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tier configuration: %w", err)
+	}
+
+	return ParseConfig(cm.Data)
+}
+
 func (m *Mapper) loadTierConfig() ([]Tier, error) {
 	cm, err := m.configMapLister.ConfigMaps(m.namespace).Get(constant.TierMappingConfigMap)
 	if err != nil {
 		return nil, err
 	}
 
-	configData, exists := cm.Data["tiers"]
-	if !exists {
+	_, hasYAML := cm.Data[ConfigKeyYAML]
+	_, hasJSON := cm.Data[ConfigKeyJSON]
+	if !hasYAML && !hasJSON {
 		m.logger.Warn("Tiers key not found in ConfigMap",
 			"configmap", constant.TierMappingConfigMap,
 		)
 		return nil, errors.New("tier to group mapping configuration not found")
 	}
 
+	tiers, err := ParseConfig(cm.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tiers {
+		tier := &tiers[i]
+		tier.Groups = append(tier.Groups, m.ProjectedSAGroup(tier))
+	}
+
+	return tiers, nil
+}
+
+// ParseConfig parses and validates the tier configuration held in the data of the tier mapping ConfigMap,
+// either as YAML under the ConfigKeyYAML key or as JSON under the ConfigKeyJSON key.
+func ParseConfig(data map[string]string) ([]Tier, error) {
+	yamlData, hasYAML := data[ConfigKeyYAML]
+	jsonData, hasJSON := data[ConfigKeyJSON]
+
 	var tiers []Tier
-	if err := yaml.Unmarshal([]byte(configData), &tiers); err != nil {
-		return nil, fmt.Errorf("failed to parse tier configuration: %w", err)
+	switch {
+	case hasYAML && hasJSON:
+		return nil, fmt.Errorf("tier configuration must be set in either %q or %q, not both", ConfigKeyYAML, ConfigKeyJSON)
+	case hasYAML:
+		if err := yaml.Unmarshal([]byte(yamlData), &tiers); err != nil {
+			return nil, fmt.Errorf("failed to parse tier configuration as YAML (key %q): %w", ConfigKeyYAML, err)
+		}
+	case hasJSON:
+		if err := json.Unmarshal([]byte(jsonData), &tiers); err != nil {
+			return nil, fmt.Errorf("failed to parse tier configuration as JSON (key %q): %w", ConfigKeyJSON, err)
+		}
+	default:
+		return nil, errors.New("tier to group mapping configuration not found")
 	}
 
 	// Validate tier configuration on every load
@@ -118,19 +226,25 @@ func (m *Mapper) loadTierConfig() ([]Tier, error) {
 		return nil, fmt.Errorf("invalid tier configuration: %w", err)
 	}
 
-	for i := range tiers {
-		tier := &tiers[i]
-		tier.Groups = append(tier.Groups, m.ProjectedSAGroup(tier))
-	}
-
 	return tiers, nil
 }
 
 // validateTierConfig validates that tier configuration is valid:
 // - All tier names must be unique
-// - If displayName is provided, it must be non-empty.
+// - If displayName is provided, it must be non-empty
+// - If namespace is provided, it must be a valid namespace name not shared with another tier
+// - If maxExpiration is provided, it must be a positive duration
+// - If resourceQuota is provided, it must map resource names to non-negative quantities
+// - A catch-all group must not be listed in the groups of any tier, where it would never act as catch-all.
 func validateTierConfig(tiers []Tier) error {
 	seenNames := make(map[string]bool)
+	seenNamespaces := make(map[string]string)
+	specificGroups := make(map[string]string)
+	for _, tier := range tiers {
+		for _, group := range tier.Groups {
+			specificGroups[group] = tier.Name
+		}
+	}
 
 	for i, tier := range tiers {
 		if tier.Name == "" {
@@ -145,6 +259,39 @@ func validateTierConfig(tiers []Tier) error {
 		if tier.DisplayName != "" && strings.TrimSpace(tier.DisplayName) == "" {
 			return fmt.Errorf("tier %q has whitespace-only displayName", tier.Name)
 		}
+
+		if tier.Namespace != "" {
+			if errs := validation.IsDNS1123Label(tier.Namespace); len(errs) > 0 {
+				return fmt.Errorf("tier %q has invalid namespace %q: %s", tier.Name, tier.Namespace, strings.Join(errs, "; "))
+			}
+			if other, taken := seenNamespaces[tier.Namespace]; taken {
+				return fmt.Errorf("tiers %q and %q share namespace %q", other, tier.Name, tier.Namespace)
+			}
+			seenNamespaces[tier.Namespace] = tier.Name
+		}
+
+		for _, group := range tier.CatchAllGroups {
+			if other, specific := specificGroups[group]; specific {
+				return fmt.Errorf("tier %q lists catch-all group %q, which is also in the groups of tier %q", tier.Name, group, other)
+			}
+		}
+
+		if tier.MaxExpiration != "" {
+			if d, err := time.ParseDuration(tier.MaxExpiration); err != nil || d <= 0 {
+				return fmt.Errorf("tier %q has invalid maxExpiration %q, expected a positive duration such as \"720h\"", tier.Name, tier.MaxExpiration)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(tier.ResourceQuota)) {
+			if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+				return fmt.Errorf("tier %q has invalid resourceQuota resource name %q: %s", tier.Name, name, strings.Join(errs, "; "))
+			}
+			quantity, err := resource.ParseQuantity(tier.ResourceQuota[name])
+			if err != nil || quantity.Sign() < 0 {
+				return fmt.Errorf("tier %q has invalid resourceQuota quantity %q for %q, expected a non-negative quantity such as \"100\" or \"4Gi\"",
+					tier.Name, tier.ResourceQuota[name], name)
+			}
+		}
 	}
 
 	return nil
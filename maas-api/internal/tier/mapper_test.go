@@ -4,6 +4,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -21,7 +24,7 @@ const (
 func TestMapper_GetTierForGroups(t *testing.T) {
 	testLogger := logger.Development()
 	configMap := fixtures.CreateTierConfigMap(testNamespace)
-	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace)
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
 
 	tests := []struct {
 		name          string
@@ -172,7 +175,7 @@ func TestMapper_GetTierForGroups_SameLevels(t *testing.T) {
 		},
 	}
 
-	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace)
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
 
 	// When levels are equal, first tier found should win
 	mappedTier, err := mapper.GetTierForGroups("group-a", "group-b")
@@ -186,6 +189,62 @@ func TestMapper_GetTierForGroups_SameLevels(t *testing.T) {
 	}
 }
 
+func TestMapper_GetTierForGroups_CatchAllGroups(t *testing.T) {
+	testLogger := logger.Development()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constant.TierMappingConfigMap,
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			"tiers": `
+- name: default
+  description: Default tier
+  level: 50
+  catchAllGroups:
+  - system:authenticated
+- name: specific
+  description: Specific tier
+  level: 1
+  groups:
+  - specific-users
+`,
+		},
+	}
+
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
+
+	tests := []struct {
+		name         string
+		groups       []string
+		expectedTier string
+	}{
+		{
+			name:         "only catch-all group",
+			groups:       []string{"system:authenticated"},
+			expectedTier: "default",
+		},
+		{
+			name:         "specific group wins over higher level catch-all",
+			groups:       []string{"system:authenticated", "specific-users"},
+			expectedTier: "specific",
+		},
+		{
+			name:         "unknown groups fall back to catch-all",
+			groups:       []string{"unknown-group", "system:authenticated"},
+			expectedTier: "default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mappedTier, err := mapper.GetTierForGroups(tt.groups...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTier, mappedTier.Name)
+		})
+	}
+}
+
 func TestMapper_GetTierForGroups_InvalidConfig(t *testing.T) {
 	testLogger := logger.Development()
 	tests := []struct {
@@ -228,6 +287,94 @@ func TestMapper_GetTierForGroups_InvalidConfig(t *testing.T) {
 `,
 			errContains: "empty name",
 		},
+		{
+			name: "invalid namespace",
+			tiersYAML: `
+- name: free
+  level: 0
+  namespace: Not_A_Namespace
+  groups:
+  - group-a
+`,
+			errContains: "invalid namespace",
+		},
+		{
+			name: "shared namespace",
+			tiersYAML: `
+- name: free
+  level: 0
+  namespace: shared-ns
+  groups:
+  - group-a
+- name: premium
+  level: 1
+  namespace: shared-ns
+  groups:
+  - group-b
+`,
+			errContains: "share namespace",
+		},
+		{
+			name: "invalid maxExpiration",
+			tiersYAML: `
+- name: free
+  level: 0
+  maxExpiration: 30d
+  groups:
+  - group-a
+`,
+			errContains: "invalid maxExpiration",
+		},
+		{
+			name: "catch-all group also in groups",
+			tiersYAML: `
+- name: free
+  level: 0
+  catchAllGroups:
+  - group-a
+- name: premium
+  level: 1
+  groups:
+  - group-a
+`,
+			errContains: "catch-all group",
+		},
+		{
+			name: "invalid resourceQuota quantity",
+			tiersYAML: `
+- name: free
+  level: 0
+  resourceQuota:
+    count/serviceaccounts: lots
+  groups:
+  - group-a
+`,
+			errContains: "invalid resourceQuota quantity",
+		},
+		{
+			name: "negative resourceQuota quantity",
+			tiersYAML: `
+- name: free
+  level: 0
+  resourceQuota:
+    count/serviceaccounts: -1
+  groups:
+  - group-a
+`,
+			errContains: "invalid resourceQuota quantity",
+		},
+		{
+			name: "invalid resourceQuota resource name",
+			tiersYAML: `
+- name: free
+  level: 0
+  resourceQuota:
+    "count serviceaccounts": 100
+  groups:
+  - group-a
+`,
+			errContains: "invalid resourceQuota resource name",
+		},
 	}
 
 	for _, tt := range tests {
@@ -242,7 +389,7 @@ func TestMapper_GetTierForGroups_InvalidConfig(t *testing.T) {
 				},
 			}
 
-			mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace)
+			mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
 
 			_, err := mapper.GetTierForGroups("group-a")
 			if err == nil {
@@ -256,3 +403,145 @@ func TestMapper_GetTierForGroups_InvalidConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMapper_Namespace(t *testing.T) {
+	testLogger := logger.Development()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constant.TierMappingConfigMap,
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			"tiers": `
+- name: free
+  level: 0
+  groups:
+  - group-a
+- name: premium
+  level: 1
+  namespace: platform-premium
+  groups:
+  - group-b
+`,
+		},
+	}
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
+
+	freeNs, err := mapper.Namespace("free")
+	require.NoError(t, err)
+	assert.Equal(t, testTenant+"-tier-free", freeNs)
+
+	premiumNs, err := mapper.Namespace("premium")
+	require.NoError(t, err)
+	assert.Equal(t, "platform-premium", premiumNs)
+
+	premiumTier, err := mapper.TierForNamespace("platform-premium")
+	require.NoError(t, err)
+	assert.Equal(t, "premium", premiumTier)
+
+	// Service accounts in the pre-created namespace map back to the tier.
+	saTier, err := mapper.GetTierForGroups("system:serviceaccounts:platform-premium")
+	require.NoError(t, err)
+	assert.Equal(t, "premium", saTier.Name)
+}
+
+func TestMapper_GetTierForGroups_ResolutionMetric(t *testing.T) {
+	testLogger := logger.Development()
+	configMap := fixtures.CreateTierConfigMap(testNamespace)
+	mapper := tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
+
+	fallbacks := resolutionCount(t, "none", "true")
+	matches := resolutionCount(t, "free", "false")
+
+	_, err := mapper.GetTierForGroups("unmapped-group")
+	require.Error(t, err)
+	assert.Equal(t, fallbacks+1, resolutionCount(t, "none", "true"), "unmapped groups must be counted as fallback")
+
+	_, err = mapper.GetTierForGroups("system:authenticated")
+	require.NoError(t, err)
+	assert.Equal(t, matches+1, resolutionCount(t, "free", "false"))
+	assert.Equal(t, fallbacks+1, resolutionCount(t, "none", "true"))
+}
+
+// resolutionCount returns the current value of tier_resolution_total for the given labels.
+func resolutionCount(t *testing.T, tierName, fallback string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "tier_resolution_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["tier"] == tierName && labels["fallback"] == fallback {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestParseConfig_Formats(t *testing.T) {
+	const tiersJSON = `[
+		{"name": "free", "displayName": "Free Tier", "description": "Free tier", "level": 1, "maxExpiration": "2160h", "groups": ["system:authenticated", "free-users"]},
+		{"name": "premium", "displayName": "Premium Tier", "description": "Premium tier", "level": 10, "groups": ["premium-users", "beta-testers"]},
+		{"name": "developer", "displayName": "Developer Tier", "description": "Developer tier", "level": 15, "groups": ["developer-users"]},
+		{"name": "enterprise", "displayName": "Enterprise Tier", "description": "Enterprise tier", "level": 20, "groups": ["enterprise-users", "admin-users"]}
+	]`
+
+	fromYAML, err := tier.ParseConfig(map[string]string{tier.ConfigKeyYAML: fixtures.TierConfigYAML})
+	require.NoError(t, err)
+
+	fromJSON, err := tier.ParseConfig(map[string]string{tier.ConfigKeyJSON: tiersJSON})
+	require.NoError(t, err)
+
+	assert.Len(t, fromYAML, 4)
+	assert.Equal(t, fromYAML, fromJSON, "equivalent YAML and JSON must produce the same tiers")
+
+	t.Run("both keys set", func(t *testing.T) {
+		_, err := tier.ParseConfig(map[string]string{
+			tier.ConfigKeyYAML: fixtures.TierConfigYAML,
+			tier.ConfigKeyJSON: tiersJSON,
+		})
+		require.ErrorContains(t, err, "not both")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := tier.ParseConfig(map[string]string{tier.ConfigKeyJSON: `[{"name": "free",`})
+		require.ErrorContains(t, err, "as JSON")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		_, err := tier.ParseConfig(map[string]string{tier.ConfigKeyYAML: "- name: [free"})
+		require.ErrorContains(t, err, "as YAML")
+	})
+
+	t.Run("JSON is validated", func(t *testing.T) {
+		_, err := tier.ParseConfig(map[string]string{tier.ConfigKeyJSON: `[{"name": "free"}, {"name": "free"}]`})
+		require.ErrorContains(t, err, "duplicate tier name")
+	})
+
+	t.Run("mapper reads JSON", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constant.TierMappingConfigMap,
+				Namespace: testNamespace,
+			},
+			Data: map[string]string{
+				tier.ConfigKeyJSON: tiersJSON,
+			},
+		}
+		mapper := tier.NewMapper(logger.Development(), fixtures.NewConfigMapLister(configMap), testTenant, testNamespace, tier.MapperOptions{})
+
+		resolved, err := mapper.GetTierForGroups("premium-users")
+		require.NoError(t, err)
+		assert.Equal(t, "premium", resolved.Name)
+	})
+}
@@ -0,0 +1,25 @@
+package tier
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// noTier is the tier label value recorded when none of the groups is mapped to a tier.
+const noTier = "none"
+
+// resolutions counts tier resolutions. Resolutions where none of the groups is mapped to a tier are recorded
+// with fallback="true": a high rate of them hints at groups missing from the tier ConfigMap.
+var resolutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tier_resolution_total",
+	Help: "Number of tier resolutions by resolved tier, with fallback=\"true\" when no tier maps the user groups.",
+}, []string{"tier", "fallback"})
+
+func init() {
+	prometheus.MustRegister(resolutions)
+}
+
+func recordResolution(tier string, fallback bool) {
+	resolutions.WithLabelValues(tier, strconv.FormatBool(fallback)).Inc()
+}
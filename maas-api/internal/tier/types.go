@@ -1,17 +1,70 @@
 package tier
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 // Tier represents a subscription tier with associated user groups and level.
 //
 // Level determines precedence, where higher values take precedence over lower values.
 // This can be needed in scenarios when users belong to multiple groups across different tiers.
 type Tier struct {
-	Name        string   `yaml:"name"`                  // Tier name - stable identifier (e.g., "free", "premium", "enterprise")
-	DisplayName string   `yaml:"displayName,omitempty"` // Human-friendly label (optional, falls back to Name)
-	Description string   `yaml:"description,omitempty"` // Human-readable description
-	Groups      []string `yaml:"groups"`                // List of groups that belong to this tier
-	Level       int      `yaml:"level,omitempty"`       // Level for importance (higher wins)
+	Name        string   `json:"name"                  yaml:"name"`                  // Tier name - stable identifier (e.g., "free", "premium", "enterprise")
+	DisplayName string   `json:"displayName,omitempty" yaml:"displayName,omitempty"` // Human-friendly label (optional, falls back to Name)
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"` // Human-readable description
+	Groups      []string `json:"groups"                yaml:"groups"`                // List of groups that belong to this tier
+	Level       int      `json:"level,omitempty"       yaml:"level,omitempty"`       // Level for importance (higher wins)
+	Namespace   string   `json:"namespace,omitempty"   yaml:"namespace,omitempty"`   // Pre-existing namespace for the tier (optional, falls back to {instance}-tier-{tier})
+	// CatchAllGroups map users to the tier only when none of their groups is listed in Groups of any tier,
+	// e.g. system:authenticated for a default tier that must not shadow more specific group memberships (optional).
+	CatchAllGroups []string `json:"catchAllGroups,omitempty" yaml:"catchAllGroups,omitempty"`
+	// MaxExpiration caps the lifetime of API keys issued to the tier, as a Go duration such as "720h" (optional).
+	MaxExpiration string `json:"maxExpiration,omitempty" yaml:"maxExpiration,omitempty"`
+	// ResourceQuota sets hard limits on the tier namespace when maas-api creates it, keyed by resource name
+	// such as "count/serviceaccounts" or "requests.cpu" (optional).
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+}
+
+// displayName returns the human-friendly label of the tier, falling back to its name.
+func (t *Tier) displayName() string {
+	if t.DisplayName != "" {
+		return t.DisplayName
+	}
+	return t.Name
+}
+
+// MaxKeyLifetime returns the longest lifetime of API keys issued to the tier, or 0 when the tier sets no limit.
+// The value is checked when the tier configuration is loaded, an unparsable one is reported as no limit.
+func (t *Tier) MaxKeyLifetime() time.Duration {
+	if t.MaxExpiration == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(t.MaxExpiration)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// ResourceQuotaHard returns the hard limits of the tier namespace, or nil when the tier sets none.
+// The quantities are checked when the tier configuration is loaded, unparsable ones are left out.
+func (t *Tier) ResourceQuotaHard() corev1.ResourceList {
+	if len(t.ResourceQuota) == 0 {
+		return nil
+	}
+	hard := make(corev1.ResourceList, len(t.ResourceQuota))
+	for name, value := range t.ResourceQuota {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			continue
+		}
+		hard[corev1.ResourceName(name)] = quantity
+	}
+	return hard
 }
 
 // GroupNotFoundError indicates that a group was not found in any tier.
@@ -11,24 +11,35 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
 )
 
 type Handler struct {
-	name    string
-	manager *Manager
-	logger  *logger.Logger
+	name      string
+	manager   *Manager
+	maxGroups int
+	logger    *logger.Logger
 }
 
-func NewHandler(log *logger.Logger, name string, manager *Manager) *Handler {
+// HandlerOptions configures how the Handler reads the user info set by the auth policy.
+type HandlerOptions struct {
+	// MaxGroups caps the number of distinct groups accepted in the group header, requests exceeding it are rejected
+	// with 400. It protects tier resolution from policies sending thousands of groups. 0 disables the cap.
+	MaxGroups int
+}
+
+func NewHandler(log *logger.Logger, name string, manager *Manager, options HandlerOptions) *Handler {
 	if log == nil {
 		log = logger.Production()
 	}
 	return &Handler{
-		name:    name,
-		manager: manager,
-		logger:  log,
+		name:      name,
+		manager:   manager,
+		maxGroups: options.MaxGroups,
+		logger:    log,
 	}
 }
 
@@ -45,16 +56,39 @@ func parseGroupsHeader(header string) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse header as JSON array: %w", err)
 	}
 
-	if len(groups) == 0 {
-		return nil, errors.New("no groups found in header")
+	// Trim whitespace from each group, dropping empty and duplicate ones
+	unique := make([]string, 0, len(groups))
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if group = strings.TrimSpace(group); group != "" && !seen[group] {
+			seen[group] = true
+			unique = append(unique, group)
+		}
 	}
 
-	// Trim whitespace from each group
-	for i := range groups {
-		groups[i] = strings.TrimSpace(groups[i])
+	if len(unique) == 0 {
+		return nil, errors.New("no groups found in header")
 	}
 
-	return groups, nil
+	return unique, nil
+}
+
+// maxSourceLength caps the length of the source header, which is stored with the API keys created.
+const maxSourceLength = 32
+
+// parseSourceHeader normalizes the source header to lower case. The source is client-provided, an empty string is
+// returned when it is longer than maxSourceLength or holds characters other than letters, digits, '.', '_' and '-'.
+func parseSourceHeader(header string) string {
+	source := strings.ToLower(strings.TrimSpace(header))
+	if len(source) > maxSourceLength {
+		return ""
+	}
+	for _, r := range source {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '_' && r != '-' {
+			return ""
+		}
+	}
+	return source
 }
 
 // ExtractUserInfo extracts user information from headers set by the auth policy.
@@ -69,11 +103,8 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 			h.logger.Error("Missing or empty username header",
 				"header", constant.HeaderUsername,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "001",
-			})
+			apierror.WriteWithDetails(c, http.StatusInternalServerError, apierror.CodeAuthFailure,
+				"Exception thrown while generating token", gin.H{"refId": "001"})
 			c.Abort()
 			return
 		}
@@ -83,11 +114,8 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 				"header", constant.HeaderGroup,
 				"username", username,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "002",
-			})
+			apierror.WriteWithDetails(c, http.StatusInternalServerError, apierror.CodeAuthFailure,
+				"Exception thrown while generating token", gin.H{"refId": "002"})
 			c.Abort()
 			return
 		}
@@ -101,24 +129,44 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 				"header_value", groupHeader,
 				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "003",
-			})
+			apierror.WriteWithDetails(c, http.StatusInternalServerError, apierror.CodeAuthFailure,
+				"Exception thrown while generating token", gin.H{"refId": "003"})
+			c.Abort()
+			return
+		}
+
+		if h.maxGroups > 0 && len(groups) > h.maxGroups {
+			h.logger.Warn("Too many groups in group header",
+				"header", constant.HeaderGroup,
+				"username", username,
+				"groups", len(groups),
+				"max_groups", h.maxGroups,
+			)
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest,
+				fmt.Sprintf("too many groups: %d, at most %d are accepted", len(groups), h.maxGroups))
 			c.Abort()
 			return
 		}
 
+		source := parseSourceHeader(c.GetHeader(constant.HeaderSource))
+		if source == "" && c.GetHeader(constant.HeaderSource) != "" {
+			h.logger.Debug("Ignoring invalid source header",
+				"header", constant.HeaderSource,
+				"username", username,
+			)
+		}
+
 		// Create UserContext from headers
 		userContext := &UserContext{
 			Username: username,
 			Groups:   groups,
+			Source:   source,
 		}
 
 		h.logger.Debug("Extracted user info from headers",
 			"username", username,
 			"groups", groups,
+			"source", source,
 		)
 
 		c.Set("user", userContext)
@@ -133,7 +181,7 @@ func (h *Handler) IssueToken(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// Allow empty request body for default expiration
 		if !errors.Is(err, io.EOF) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
 			return
 		}
 	}
@@ -144,40 +192,135 @@ func (h *Handler) IssueToken(c *gin.Context) {
 
 	userCtx, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User context not found"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
 		return
 	}
 
 	user, ok := userCtx.(*UserContext)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context type"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
 		return
 	}
 
-	expiration := req.Expiration.Duration
-	if err := ValidateExpiration(expiration, 10*time.Minute); err != nil {
-		response := gin.H{"error": err.Error()}
-		if expiration > 0 && expiration < 10*time.Minute {
-			response["provided_expiration"] = expiration.String()
-		}
-		c.JSON(http.StatusBadRequest, response)
+	h.issueToken(c, user, req.Expiration.Duration)
+}
+
+// IssueTokenOnBehalf handles POST /v1/admin/tokens for issuing ephemeral tokens to another user than the caller.
+// The token is minted in the tier namespace of the target user, determined by the groups given in the request.
+// It relies on the user context set by ExtractUserInfo, and access must be restricted to administrators.
+func (h *Handler) IssueTokenOnBehalf(c *gin.Context) {
+	var req ImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	if req.Expiration == nil {
+		req.Expiration = &Duration{time.Hour * 4}
+	}
+
+	userCtx, exists := c.Get("user")
+	if !exists {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "User context not found")
+		return
+	}
+
+	caller, ok := userCtx.(*UserContext)
+	if !ok {
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid user context type")
+		return
+	}
+
+	target := &UserContext{
+		Username: strings.TrimSpace(req.Username),
+		Groups:   req.Groups,
+	}
+	if target.Username == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "username must not be empty")
+		return
+	}
+
+	if token := h.issueToken(c, target, req.Expiration.Duration); token != nil {
+		h.logger.Info("Issued token on behalf of another user",
+			"impersonator", caller.Username,
+			"username", target.Username,
+			"groups", target.Groups,
+			"jti", token.JTI,
+			"client_ip", ClientIPFromContext(c.Request.Context()),
+		)
+	}
+}
+
+// PreviewServiceAccount handles GET /v1/admin/sa-name, returning the Service Account the tokens of the user given
+// in the username query parameter are issued for. The optional, repeatable groups query parameter resolves the tier
+// the user would get, otherwise the tier of their existing Service Account is returned.
+// Access must be restricted to administrators.
+func (h *Handler) PreviewServiceAccount(c *gin.Context) {
+	username := strings.TrimSpace(c.Query("username"))
+	if username == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "username must not be empty")
+		return
+	}
+
+	preview, err := h.manager.PreviewServiceAccount(username, c.QueryArray("groups"))
+	if errors.Is(err, ErrInvalidUsername) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+	var groupNotFoundErr *tier.GroupNotFoundError
+	if errors.As(err, &groupNotFoundErr) {
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNotFound, groupNotFoundErr.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to preview service account",
+			"error", err,
+		)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to preview service account")
 		return
 	}
 
+	c.JSON(http.StatusOK, preview)
+}
+
+// issueToken validates the expiration and writes an ephemeral token issued for the user.
+// Returns the token, or nil when an error response was written instead.
+func (h *Handler) issueToken(c *gin.Context, user *UserContext, expiration time.Duration) *Token {
+	if err := ValidateExpiration(expiration, minExpiration); err != nil {
+		var details gin.H
+		if expiration > 0 && expiration < minExpiration {
+			details = gin.H{"provided_expiration": expiration.String()}
+		}
+		apierror.WriteWithDetails(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error(), details)
+		return nil
+	}
+
 	// For ephemeral tokens, we explicitly pass an empty name.
 	token, err := h.manager.GenerateToken(c.Request.Context(), user, expiration, "")
+	var limitErr *ExpirationLimitError
+	if errors.As(err, &limitErr) {
+		apierror.WriteWithDetails(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "token expiration "+limitErr.Error(),
+			gin.H{"provided_expiration": expiration.String(), "max_expiration": limitErr.Max.String()})
+		return nil
+	}
+	if errors.Is(err, ErrTierNamespaceForbidden) {
+		apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, TierNamespaceForbiddenMessage)
+		return nil
+	}
 	if err != nil {
 		h.logger.Error("Failed to generate token",
 			"error", err,
 			"expiration", expiration.String(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-		return
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to generate token")
+		return nil
 	}
 
 	response := Response{
-		Token: token,
+		Token:     token,
+		ExpiresIn: ExpiresIn(token.ExpiresAt, time.Now()),
 	}
 
 	c.JSON(http.StatusCreated, response)
+	return token
 }
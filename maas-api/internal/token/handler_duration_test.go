@@ -8,8 +8,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
 )
 
@@ -140,6 +146,14 @@ func TestIssueToken_ExpirationFormats(t *testing.T) {
 			shouldHaveToken: false,
 			description:     "Decimal without unit should be rejected",
 		},
+		{
+			name:            "beyond tier maximum",
+			expiration:      "2161h",
+			expectedStatus:  http.StatusBadRequest,
+			expectedError:   "token expiration must not exceed 2160h0m0s for tier free",
+			shouldHaveToken: false,
+			description:     "Expiration beyond the maxExpiration of the tier should be rejected",
+		},
 	}
 
 	for _, tt := range tests {
@@ -186,16 +200,48 @@ func TestIssueToken_ExpirationFormats(t *testing.T) {
 					t.Errorf("expected non-empty token. Description: %s", tt.description)
 				}
 			} else {
-				if response["error"] == nil {
-					t.Errorf("expected error for invalid Expiration. Description: %s", tt.description)
+				var errResponse apierror.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &errResponse); err != nil {
+					t.Errorf("failed to unmarshal error response: %v", err)
 				}
-				errorMsg, ok := response["error"].(string)
-				if !ok {
-					t.Errorf("expected error to be a string, got %T", response["error"])
-				} else if !strings.Contains(errorMsg, tt.expectedError) {
-					t.Errorf("expected error message: '%s'; got: '%v'\n", tt.expectedError, response["error"])
+				if !strings.Contains(errResponse.Error.Message, tt.expectedError) {
+					t.Errorf("expected error message: '%s'; got: '%v'\n", tt.expectedError, errResponse.Error.Message)
 				}
 			}
 		})
 	}
 }
+
+func TestIssueToken_ExpiresIn(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+	router, cleanupRouter := fixtures.SetupTestRouter(manager)
+	defer func() {
+		if err := cleanupRouter(); err != nil {
+			t.Logf("Router cleanup error: %v", err)
+		}
+	}()
+
+	w := httptest.NewRecorder()
+	request, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/tokens", bytes.NewBufferString(`{"expiration": "2h"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(constant.HeaderUsername, "expires-in@example.com")
+	request.Header.Set(constant.HeaderGroup, `["system:authenticated"]`)
+	router.ServeHTTP(w, request)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response token.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	requested := int64((2 * time.Hour).Seconds())
+	assert.InDelta(t, requested, response.ExpiresIn, 5, "expiresIn should match the requested TTL")
+	assert.InDelta(t, response.ExpiresAt-time.Now().Unix(), response.ExpiresIn, 5, "expiresIn should be consistent with expiresAt")
+}
+
+func TestExpiresIn(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	assert.Equal(t, int64(3600), token.ExpiresIn(now.Add(time.Hour).Unix(), now))
+	assert.Equal(t, int64(0), token.ExpiresIn(now.Unix(), now))
+	assert.Equal(t, int64(0), token.ExpiresIn(now.Add(-time.Minute).Unix(), now), "expired tokens must not report a negative value")
+}
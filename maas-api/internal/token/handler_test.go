@@ -3,15 +3,29 @@ package token_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
 )
@@ -23,7 +37,7 @@ func TestAPIEndpoints(t *testing.T) {
 	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
 	defer cleanup()
 
-	handler := token.NewHandler(testLogger, "test", manager)
+	handler := token.NewHandler(testLogger, "test", manager, token.HandlerOptions{})
 
 	router := gin.New()
 	router.Use(handler.ExtractUserInfo())
@@ -109,32 +123,297 @@ func TestAPIEndpoints(t *testing.T) {
 					t.Errorf("expected non-empty token. Description: %s", tt.description)
 				}
 			} else {
-				if response["error"] == nil {
-					t.Errorf("expected error. Description: %s", tt.description)
-				}
-				errorMsg, ok := response["error"].(string)
-				if !ok {
-					t.Errorf("expected error to be a string, got %T", response["error"])
-				} else if errorMsg != tt.expectedError {
-					t.Errorf("expected error message: '%s'; got: '%s'. Description: %s", tt.expectedError, errorMsg, tt.description)
-				}
+				var errResponse apierror.Response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResponse))
+				assert.Equal(t, tt.expectedError, errResponse.Error.Message, tt.description)
+				assert.NotEmpty(t, errResponse.Error.RequestID, tt.description)
 				if tt.expectedCode != "" {
-					exceptionCode, ok := response["exceptionCode"].(string)
-					if !ok {
-						t.Errorf("expected exceptionCode to be a string, got %T", response["exceptionCode"])
-					} else if exceptionCode != tt.expectedCode {
-						t.Errorf("expected exceptionCode: '%s'; got: '%s'. Description: %s", tt.expectedCode, exceptionCode, tt.description)
-					}
+					assert.Equal(t, tt.expectedCode, errResponse.Error.Code, tt.description)
 				}
 				if tt.expectedRefId != "" {
-					refId, ok := response["refId"].(string)
-					if !ok {
-						t.Errorf("expected refId to be a string, got %T", response["refId"])
-					} else if refId != tt.expectedRefId {
-						t.Errorf("expected refId: '%s'; got: '%s'. Description: %s", tt.expectedRefId, refId, tt.description)
-					}
+					assert.Equal(t, map[string]any{"refId": tt.expectedRefId}, errResponse.Error.Details, tt.description)
 				}
 			}
 		})
 	}
 }
+
+func TestExtractUserInfo_Groups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	manyGroups := make([]string, constant.DefaultMaxGroups+1)
+	for i := range manyGroups {
+		manyGroups[i] = fmt.Sprintf("group-%d", i)
+	}
+
+	tests := []struct {
+		name           string
+		maxGroups      int
+		groups         []string
+		expectedStatus int
+		expectedGroups []string
+	}{
+		{
+			name:           "trims and de-duplicates groups",
+			groups:         []string{" team-a ", "team-a", "", "system:authenticated", "team-a", "  "},
+			expectedStatus: http.StatusOK,
+			expectedGroups: []string{"team-a", "system:authenticated"},
+		},
+		{
+			name:           "counts distinct groups against the cap",
+			maxGroups:      2,
+			groups:         []string{"team-a", "team-b", "team-a", "team-b"},
+			expectedStatus: http.StatusOK,
+			expectedGroups: []string{"team-a", "team-b"},
+		},
+		{
+			name:           "rejects groups beyond the configured cap",
+			maxGroups:      2,
+			groups:         []string{"team-a", "team-b", "team-c"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejects groups beyond the default cap",
+			maxGroups:      constant.DefaultMaxGroups,
+			groups:         manyGroups,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "cap disabled",
+			groups:         manyGroups,
+			expectedStatus: http.StatusOK,
+			expectedGroups: manyGroups,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := token.NewHandler(logger.Development(), "test", nil, token.HandlerOptions{MaxGroups: tt.maxGroups})
+
+			router := gin.New()
+			router.Use(handler.ExtractUserInfo())
+			router.GET("/whoami", func(c *gin.Context) {
+				user, _ := c.Get("user")
+				c.JSON(http.StatusOK, user)
+			})
+
+			groups, err := json.Marshal(tt.groups)
+			require.NoError(t, err)
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/whoami", nil)
+			req.Header.Set(constant.HeaderUsername, "test-user")
+			req.Header.Set(constant.HeaderGroup, string(groups))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+
+			if tt.expectedStatus != http.StatusOK {
+				var errResponse apierror.Response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResponse))
+				assert.Equal(t, apierror.CodeInvalidRequest, errResponse.Error.Code)
+				assert.Contains(t, errResponse.Error.Message, "too many groups")
+				return
+			}
+
+			var user token.UserContext
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+			assert.Equal(t, tt.expectedGroups, user.Groups)
+		})
+	}
+}
+
+func TestIssueTokenOnBehalf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const impersonationGroup = "token-admins"
+
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := token.NewHandler(logger.FromZap(zap.New(core), false), "test", manager, token.HandlerOptions{})
+
+	router := gin.New()
+	router.POST("/v1/admin/tokens", handler.ExtractUserInfo(), handlers.RequireAnyGroup([]string{impersonationGroup}), handler.IssueTokenOnBehalf)
+
+	issue := func(t *testing.T, groups string, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+
+		reqBody, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/admin/tokens", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "admin-user")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("MintedInTargetTierNamespace", func(t *testing.T) {
+		w := issue(t, `["system:authenticated","`+impersonationGroup+`"]`, map[string]any{
+			"username":   "pipeline-bot",
+			"groups":     []string{"premium-users"},
+			"expiration": "1h",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response token.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Token)
+
+		claims := jwt.MapClaims{}
+		_, _, err := jwt.NewParser().ParseUnverified(response.Token.Token, claims)
+		require.NoError(t, err)
+		sub, err := claims.GetSubject()
+		require.NoError(t, err)
+		parts := strings.Split(sub, ":")
+		require.Len(t, parts, 4)
+		assert.Equal(t, fixtures.TestTenant+"-tier-premium", parts[2], "token must be minted in the tier namespace of the target user")
+		assert.True(t, strings.HasPrefix(parts[3], "pipeline-bot-"), "token must be minted for the target user, got %s", parts[3])
+
+		audited := logs.FilterMessage("Issued token on behalf of another user").All()
+		require.Len(t, audited, 1)
+		fields := audited[0].ContextMap()
+		assert.Equal(t, "admin-user", fields["impersonator"])
+		assert.Equal(t, "pipeline-bot", fields["username"])
+		assert.Equal(t, response.Token.JTI, fields["jti"])
+	})
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		w := issue(t, `["system:authenticated"]`, map[string]any{
+			"username": "pipeline-bot",
+			"groups":   []string{"premium-users"},
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("MissingTargetUser", func(t *testing.T) {
+		for _, body := range []map[string]any{
+			{"groups": []string{"premium-users"}},
+			{"username": "   ", "groups": []string{"premium-users"}},
+			{"username": "pipeline-bot"},
+		} {
+			w := issue(t, `["`+impersonationGroup+`"]`, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestPreviewServiceAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const adminGroup = "maas-admins"
+
+	testLogger := logger.Development()
+	existing := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alice-example-com-fc2398a7",
+			Namespace: fixtures.TestTenant + "-tier-premium",
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  "token-issuer",
+				"app.kubernetes.io/part-of":    "maas-api",
+				"maas.opendatahub.io/instance": fixtures.TestTenant,
+				"maas.opendatahub.io/tier":     "premium",
+			},
+		},
+	}
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		tier.NewMapper(testLogger, fixtures.NewConfigMapLister(fixtures.CreateTierConfigMap(fixtures.TestNamespace)), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+		k8sfake.NewClientset(),
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(existing),
+		token.ManagerOptions{},
+	)
+	handler := token.NewHandler(testLogger, "test", manager, token.HandlerOptions{})
+
+	router := gin.New()
+	router.GET("/v1/admin/sa-name", handler.ExtractUserInfo(), handlers.RequireAnyGroup([]string{adminGroup}), handler.PreviewServiceAccount)
+
+	preview := func(t *testing.T, groups string, query url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/admin/sa-name?"+query.Encode(), nil)
+		require.NoError(t, err)
+		req.Header.Set(constant.HeaderUsername, "admin-user")
+		req.Header.Set(constant.HeaderGroup, groups)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("SanitizedNames", func(t *testing.T) {
+		tests := []struct {
+			username string
+			expected string
+		}{
+			{username: "alice@example.com", expected: "alice-example-com-fc2398a7"},
+			{username: "CN=Bob Smith,OU=Engineering,DC=example,DC=com", expected: "cn-bob-smith-ou-engineering-dc-example-dc-com-140e5c86"},
+			{username: "system:serviceaccount:ci:deployer", expected: "system-serviceaccount-ci-deployer-b3b57173"},
+			{username: "Ünïcode_User!!", expected: "n-code-user-d28241cb"},
+			{username: strings.Repeat("a", 80), expected: strings.Repeat("a", 54) + "-86f33652"},
+		}
+
+		for _, tt := range tests {
+			w := preview(t, `["`+adminGroup+`"]`, url.Values{"username": {tt.username}, "groups": {"system:authenticated"}})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response token.ServiceAccountPreview
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.username, response.Username)
+			assert.Equal(t, tt.expected, response.Name, "username %q", tt.username)
+			assert.LessOrEqual(t, len(response.Name), 63)
+			assert.Equal(t, "free", response.Tier)
+			assert.Equal(t, fixtures.TestTenant+"-tier-free", response.Namespace)
+		}
+	})
+
+	t.Run("TierFromGroups", func(t *testing.T) {
+		w := preview(t, `["`+adminGroup+`"]`, url.Values{"username": {"alice@example.com"}, "groups": {"system:authenticated", "premium-users"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response token.ServiceAccountPreview
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "premium", response.Tier)
+		assert.Equal(t, fixtures.TestTenant+"-tier-premium", response.Namespace)
+		assert.True(t, response.Exists)
+	})
+
+	t.Run("TierFromExistingServiceAccount", func(t *testing.T) {
+		w := preview(t, `["`+adminGroup+`"]`, url.Values{"username": {"alice@example.com"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response token.ServiceAccountPreview
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "premium", response.Tier)
+		assert.Equal(t, fixtures.TestTenant+"-tier-premium", response.Namespace)
+		assert.True(t, response.Exists)
+
+		w = preview(t, `["`+adminGroup+`"]`, url.Values{"username": {"carol@example.com"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.Tier)
+		assert.Empty(t, response.Namespace)
+		assert.False(t, response.Exists)
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		w := preview(t, `["`+adminGroup+`"]`, url.Values{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = preview(t, `["`+adminGroup+`"]`, url.Values{"username": {"@@@"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = preview(t, `["`+adminGroup+`"]`, url.Values{"username": {"alice@example.com"}, "groups": {"unknown-group"}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("CallerNotAllowed", func(t *testing.T) {
+		w := preview(t, `["system:authenticated"]`, url.Values{"username": {"alice@example.com"}})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -10,6 +10,18 @@ type Request struct {
 	Name string `json:"name,omitempty"`
 }
 
+// ImpersonationRequest is the body of POST /v1/admin/tokens, issuing a token on behalf of another user.
+type ImpersonationRequest struct {
+	Request
+
+	// Username is the user the token is issued for.
+	Username string `json:"username" binding:"required"`
+	// Groups are the groups of the user, which determine the tier of the token.
+	Groups []string `json:"groups" binding:"required,min=1"`
+}
+
 type Response struct {
 	*Token `json:",inline,omitempty"`
+	// ExpiresIn is the number of seconds until the token expires, computed when the response is sent.
+	ExpiresIn int64 `json:"expiresIn"`
 }
@@ -3,6 +3,7 @@ package token
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -21,3 +22,34 @@ func extractClaims(tokenString string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// ExtractJTI returns the jti claim of the token without validating it.
+func ExtractJTI(tokenString string) (string, error) {
+	claims, err := extractClaims(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return "", errors.New("token has no jti claim")
+	}
+
+	return jti, nil
+}
+
+// serviceAccountNamespace returns the namespace of the service account the token was issued for,
+// read from its "system:serviceaccount:<namespace>:<name>" subject.
+func serviceAccountNamespace(claims jwt.MapClaims) (string, error) {
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return "", fmt.Errorf("failed to read sub claim: %w", err)
+	}
+
+	parts := strings.Split(sub, ":")
+	if len(parts) != 4 || parts[0] != "system" || parts[1] != "serviceaccount" {
+		return "", fmt.Errorf("subject %q is not a service account", sub)
+	}
+
+	return parts[2], nil
+}
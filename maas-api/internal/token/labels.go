@@ -1,5 +1,22 @@
 package token
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maasLabelPrefix is the prefix of labels owned by maas-api, which cannot be set through label templates.
+const maasLabelPrefix = "maas.opendatahub.io/"
+
+// tokensValidUntilAnnotation records on a Service Account the expiration of the latest token issued for it, in
+// RFC 3339 format, so that Service Accounts with tokens still valid are not pruned.
+const tokensValidUntilAnnotation = maasLabelPrefix + "tokens-valid-until"
+
 func namespaceLabels(instance, tier string) map[string]string {
 	return map[string]string{
 		"app.kubernetes.io/component":        "token-issuer",
@@ -10,6 +27,15 @@ func namespaceLabels(instance, tier string) map[string]string {
 	}
 }
 
+func resourceQuotaLabels(instance, tier string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/component":  "token-issuer",
+		"app.kubernetes.io/part-of":    "maas-api",
+		"maas.opendatahub.io/instance": instance,
+		"maas.opendatahub.io/tier":     tier,
+	}
+}
+
 func serviceAccountLabels(instance, tier string) map[string]string {
 	return map[string]string{
 		"app.kubernetes.io/component":  "token-issuer",
@@ -18,3 +44,71 @@ func serviceAccountLabels(instance, tier string) map[string]string {
 		"maas.opendatahub.io/tier":     tier,
 	}
 }
+
+// tierNamespaceSelector selects the tier namespaces created for the instance, in any tier.
+func tierNamespaceSelector(instance string) labels.Selector {
+	set := namespaceLabels(instance, "")
+	delete(set, "maas.opendatahub.io/tier")
+	return labels.SelectorFromSet(set)
+}
+
+// serviceAccountSelector selects the Service Accounts created for the users of the instance, in any tier.
+func serviceAccountSelector(instance string) string {
+	set := serviceAccountLabels(instance, "")
+	delete(set, "maas.opendatahub.io/tier")
+	return labels.SelectorFromSet(set).String()
+}
+
+// ParseLabelTemplates parses key=value label templates. Values may reference {instance} and {tier}.
+// Labels set by maas-api itself are reserved and rejected.
+func ParseLabelTemplates(entries []string) (map[string]string, error) {
+	templates := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !found {
+			return nil, fmt.Errorf("invalid label template %q: expected key=value", entry)
+		}
+
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+
+		if isReservedLabel(key) {
+			return nil, fmt.Errorf("label %q is reserved and cannot be overridden", key)
+		}
+
+		templates[key] = strings.TrimSpace(value)
+	}
+
+	return templates, nil
+}
+
+func isReservedLabel(key string) bool {
+	if strings.HasPrefix(key, maasLabelPrefix) {
+		return true
+	}
+	_, reserved := namespaceLabels("", "")[key]
+	return reserved
+}
+
+// tierNamespaceLabels renders the label templates for the tier namespace and merges them with the maas-api labels,
+// which always take precedence.
+func tierNamespaceLabels(instance, tier string, templates map[string]string) (map[string]string, error) {
+	replacer := strings.NewReplacer("{instance}", instance, "{tier}", tier)
+
+	labels := make(map[string]string, len(templates))
+	var errs []error
+	for key, template := range templates {
+		value := replacer.Replace(template)
+		if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid value %q for label %q: %s", value, key, strings.Join(msgs, "; ")))
+			continue
+		}
+		labels[key] = value
+	}
+
+	maps.Copy(labels, namespaceLabels(instance, tier))
+
+	return labels, errors.Join(errs...)
+}
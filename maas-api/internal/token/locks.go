@@ -0,0 +1,49 @@
+package token
+
+import "sync"
+
+// userLocks hands out one mutex per key, so that operations on the Service Account of a single user
+// are serialized while operations for different users still run concurrently.
+// Entries are reference counted and removed once no goroutine holds or waits for them.
+//
+// Lock ordering: the registry mutex is only held while looking up or releasing an entry, never while
+// waiting for a user lock. A goroutine holds at most one user lock at a time, and user locks are the
+// innermost lock taken by the Manager - tier resolution and namespace creation happen before acquiring one.
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[string]*userLock
+}
+
+type userLock struct {
+	sync.Mutex
+
+	refs int
+}
+
+// lock blocks until the lock for the given key is acquired and returns the function releasing it.
+func (l *userLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*userLock)
+	}
+	entry, ok := l.locks[key]
+	if !ok {
+		entry = &userLock{}
+		l.locks[key] = entry
+	}
+	entry.refs++
+	l.mu.Unlock()
+
+	entry.Lock()
+
+	return func() {
+		entry.Unlock()
+
+		l.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
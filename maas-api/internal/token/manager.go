@@ -2,19 +2,27 @@ package token
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha1" //nolint:gosec // SHA1 used for non-cryptographic hashing of usernames, not for security
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	corelistersv1 "k8s.io/client-go/listers/core/v1"
 
@@ -28,7 +36,99 @@ type Manager struct {
 	clientset            kubernetes.Interface
 	namespaceLister      corelistersv1.NamespaceLister
 	serviceAccountLister corelistersv1.ServiceAccountLister
-	logger               *logger.Logger
+	options              ManagerOptions
+	audiences            []string
+	// expirationJitter is the fraction by which token expirations are randomly spread,
+	// see ManagerOptions.ExpirationJitterPercent.
+	expirationJitter float64
+	logger           *logger.Logger
+
+	// serviceAccountLocks serializes changes to the Service Account of a user, see userLocks for the lock ordering.
+	serviceAccountLocks userLocks
+
+	// appliedQuotas records the hard limits last applied to the resource quota of each tier namespace,
+	// see reconcileTierResourceQuota.
+	appliedQuotasMu sync.Mutex
+	appliedQuotas   map[string]string
+}
+
+// ManagerOptions configures the tier namespaces managed by the Manager and the tokens it issues.
+type ManagerOptions struct {
+	// LabelTemplates are additional labels set on tier namespaces when they are created, see ParseLabelTemplates.
+	LabelTemplates map[string]string
+	// Unmanaged disables the creation of tier namespaces. They must be pre-created by administrators,
+	// either under the name set in the tier configuration or following the {instance}-tier-{tier} convention.
+	Unmanaged bool
+	// FallbackNamespace is used for tiers whose namespace maas-api is not allowed to create. When empty,
+	// token requests for these tiers fail with ErrTierNamespaceForbidden.
+	FallbackNamespace string
+	// MaxNamespaces caps the number of tier namespaces created for the instance. Token requests for a tier whose
+	// namespace would exceed it fail with ErrTierNamespaceLimit. 0 disables the cap.
+	MaxNamespaces int
+	// SystemUsers are shell-style patterns, e.g. system:serviceaccount:platform:*, of the usernames of system
+	// identities. Their tokens are issued in SystemNamespace, which is never created, without resolving their tier:
+	// they have no tier, hence no tier expiration limit. This only changes where their Service Account lives,
+	// it grants no other privilege.
+	SystemUsers []string
+	// SystemNamespace is the namespace of the Service Accounts of SystemUsers. It is required when SystemUsers is set.
+	SystemNamespace string
+	// Audiences are the audiences of the Service Account tokens issued to clients, so that a single token is accepted
+	// by the authentication policies of all gateways of the instance. Defaults to {instance}-sa alone.
+	Audiences []string
+	// ExpirationJitterPercent spreads the expiration of issued tokens randomly by up to ±percent of the requested one,
+	// so that clients requesting the same expiration at the same time do not all renew their tokens at once.
+	// The granted expiration never goes below the minimum token lifetime nor above the maximum of the tier.
+	// 0 disables the jitter, tokens then expire exactly as requested.
+	ExpirationJitterPercent int
+}
+
+// ValidateSystemUsers checks that the patterns of system usernames are well-formed, see ManagerOptions.SystemUsers.
+func ValidateSystemUsers(patterns []string) error {
+	var errs []error
+	for _, pattern := range patterns {
+		if pattern == "" {
+			errs = append(errs, errors.New("system user pattern must not be empty"))
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid system user pattern %q: %w", pattern, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TierResourceQuotaName is the name of the ResourceQuota created in tier namespaces for tiers setting a resourceQuota.
+const TierResourceQuotaName = "maas-tier-quota"
+
+// ErrTierNamespaceMissing is returned when namespace management is disabled and the tier namespace does not exist.
+var ErrTierNamespaceMissing = errors.New("tier namespace does not exist")
+
+// ErrTierNamespaceLimit is returned when creating the tier namespace would exceed ManagerOptions.MaxNamespaces.
+var ErrTierNamespaceLimit = errors.New("tier namespace limit reached")
+
+// ErrTierNamespaceForbidden is returned when maas-api is not allowed to create the tier namespace
+// and no fallback namespace is configured.
+var ErrTierNamespaceForbidden = errors.New("not allowed to create the tier namespace")
+
+// TierNamespaceForbiddenMessage is the message of the 403 responses to token requests failing with
+// ErrTierNamespaceForbidden. The RBAC details are only logged.
+const TierNamespaceForbiddenMessage = "The maas-api service account lacks permission to create the namespace of your tier, " +
+	"contact an administrator"
+
+// minExpiration is the shortest token lifetime, the minimum accepted by the API server for TokenRequests.
+const minExpiration = 10 * time.Minute
+
+// ErrInvalidUsername is returned when no Service Account name can be derived from a username.
+var ErrInvalidUsername = errors.New("invalid username")
+
+// ExpirationLimitError is returned when the requested token lifetime exceeds the maximum set for the tier of the user.
+type ExpirationLimitError struct {
+	Tier string
+	Max  time.Duration
+}
+
+func (e *ExpirationLimitError) Error() string {
+	return fmt.Sprintf("must not exceed %s for tier %s", e.Max, e.Tier)
 }
 
 func NewManager(
@@ -38,17 +138,61 @@ func NewManager(
 	clientset kubernetes.Interface,
 	namespaceLister corelistersv1.NamespaceLister,
 	serviceAccountLister corelistersv1.ServiceAccountLister,
+	options ManagerOptions,
 ) *Manager {
+	var audiences []string
+	for _, audience := range options.Audiences {
+		if !slices.Contains(audiences, audience) {
+			audiences = append(audiences, audience)
+		}
+	}
+
 	return &Manager{
 		tenantName:           tenantName,
 		tierMapper:           tierMapper,
 		clientset:            clientset,
 		namespaceLister:      namespaceLister,
 		serviceAccountLister: serviceAccountLister,
+		options:              options,
+		audiences:            audiences,
+		expirationJitter:     float64(options.ExpirationJitterPercent) / 100,
 		logger:               log,
+		appliedQuotas:        map[string]string{},
 	}
 }
 
+// UserTier returns the tier of the user, resolved from their groups on first use and kept on the user for the
+// rest of the request.
+func (m *Manager) UserTier(user *UserContext) (*tier.Tier, error) {
+	user.tierMu.Lock()
+	defer user.tierMu.Unlock()
+
+	if user.Tier != nil {
+		return user.Tier, nil
+	}
+
+	userTier, err := m.tierMapper.GetTierForGroups(user.Groups...)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Tier = userTier
+	return userTier, nil
+}
+
+// isSystemUser reports whether the username matches one of the system user patterns, see ManagerOptions.SystemUsers.
+func (m *Manager) isSystemUser(username string) bool {
+	if m.options.SystemNamespace == "" {
+		return false
+	}
+	for _, pattern := range m.options.SystemUsers {
+		if matched, err := path.Match(pattern, username); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateToken creates a Service Account token in the namespace bound to the tier the user belongs to.
 func (m *Manager) GenerateToken(ctx context.Context, user *UserContext, expiration time.Duration, name string) (*Token, error) {
 	// name parameter is ignored - kept for interface compatibility
@@ -57,34 +201,116 @@ func (m *Manager) GenerateToken(ctx context.Context, user *UserContext, expirati
 	log := m.logger.WithFields(
 		"expiration", expiration.String(),
 	)
+	if clientIP := ClientIPFromContext(ctx); clientIP != "" {
+		log = log.WithFields("client_ip", clientIP)
+	}
+
+	var (
+		namespace     string
+		tierName      string
+		maxExpiration time.Duration
+	)
+	if m.isSystemUser(user.Username) {
+		namespace = m.options.SystemNamespace
+		log = log.WithFields("system_user", true)
+		log.Debug("Skipping tier resolution for system user")
+	} else {
+		userTier, err := m.UserTier(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine user tier for %s (groups: %v): %w", user.Username, user.Groups, err)
+		}
 
-	userTier, err := m.tierMapper.GetTierForGroups(user.Groups...)
+		log = log.WithFields("tier", userTier.Name)
+		log.Debug("Determined user tier")
+
+		maxExpiration = userTier.MaxKeyLifetime()
+		if maxExpiration > 0 && expiration > maxExpiration {
+			return nil, &ExpirationLimitError{Tier: userTier.Name, Max: maxExpiration}
+		}
+
+		var errNs error
+		namespace, errNs = m.ensureTierNamespace(ctx, userTier)
+		if errNs != nil {
+			return nil, fmt.Errorf("failed to ensure tier namespace for tier %s: %w", userTier.Name, errNs)
+		}
+		tierName = userTier.Name
+	}
+
+	// The granted expiration is reported in the token, clients must not assume the requested one.
+	granted := m.jitteredExpiration(expiration, maxExpiration)
+
+	saName, errName := m.sanitizeServiceAccountName(user.Username)
+	if errName != nil {
+		return nil, fmt.Errorf("failed to sanitize service account name for user %s: %w", user.Username, errName)
+	}
+
+	// Hold the user lock until the token is minted, so that a concurrent revocation cannot delete
+	// the Service Account between ensuring it exists and requesting the token.
+	unlock := m.serviceAccountLocks.lock(saName)
+	token, errToken := m.issueServiceAccountToken(ctx, namespace, saName, tierName, int(granted.Seconds()))
+	unlock()
+	if errToken != nil {
+		return nil, fmt.Errorf("failed to issue token for user %s in namespace %s: %w", user.Username, namespace, errToken)
+	}
+
+	jti, issuedAt, err := tokenIdentity(log, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine user tier for %s (groups: %v): %w", user.Username, user.Groups, err)
+		return nil, err
 	}
 
-	log = log.WithFields("tier", userTier.Name)
-	log.Debug("Determined user tier")
+	// The token itself is never logged, the JTI identifies it for audits.
+	log.Info("Issued token",
+		"username", user.Username,
+		"namespace", namespace,
+		"jti", jti,
+	)
+	log.Debug("Issued token details",
+		"groups", user.Groups,
+		"service_account", saName,
+		"granted_expiration", granted.String(),
+		"expires_at", token.Status.ExpirationTimestamp.Unix(),
+	)
 
-	namespace, errNs := m.ensureTierNamespace(ctx, userTier.Name)
-	if errNs != nil {
-		return nil, fmt.Errorf("failed to ensure tier namespace for tier %s: %w", userTier.Name, errNs)
+	result := &Token{
+		Token:      token.Status.Token,
+		Expiration: Duration{granted},
+		ExpiresAt:  token.Status.ExpirationTimestamp.Unix(),
+		IssuedAt:   issuedAt,
+		JTI:        jti,
+		Namespace:  namespace,
 	}
 
-	saName, errSA := m.ensureServiceAccount(ctx, namespace, user.Username, userTier.Name)
-	if errSA != nil {
-		return nil, fmt.Errorf("failed to ensure service account for user %s in namespace %s: %w", user.Username, namespace, errSA)
+	return result, nil
+}
+
+// jitteredExpiration returns the expiration spread by the configured jitter, truncated to seconds as TokenRequests
+// are. It stays within minExpiration, or the requested expiration when shorter, and maxExpiration when set.
+func (m *Manager) jitteredExpiration(expiration, maxExpiration time.Duration) time.Duration {
+	if m.expirationJitter <= 0 {
+		return expiration
 	}
 
-	token, errToken := m.createServiceAccountToken(ctx, namespace, saName, int(expiration.Seconds()))
-	if errToken != nil {
-		return nil, fmt.Errorf("failed to create token for service account %s in namespace %s: %w", saName, namespace, errToken)
+	//nolint:gosec // G404: spreading expirations does not need a cryptographically secure source
+	spread := m.expirationJitter * (2*rand.Float64() - 1)
+	jittered := (expiration + time.Duration(spread*float64(expiration))).Truncate(time.Second)
+
+	jittered = max(jittered, min(expiration, minExpiration))
+	if maxExpiration > 0 {
+		jittered = min(jittered, maxExpiration)
 	}
+	return jittered
+}
 
+// tokenIdentity returns the JTI and the issuance time of the token. Tokens whose claims cannot be read,
+// e.g. opaque tokens issued by non-standard API servers, are still usable: they are tracked with a locally
+// generated JTI and the time they were received at, their expiration is taken from the TokenRequest status.
+func tokenIdentity(log *logger.Logger, token *authv1.TokenRequest) (string, int64, error) {
 	claims, err := extractClaims(token.Status.Token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract claims from new token: %w", err)
+		log.Warn("Failed to read the claims of the issued token, tracking it with a local JTI", "error", err)
+		claims = jwt.MapClaims{}
 	}
+
 	jti, ok := claims["jti"].(string)
 	if !ok || jti == "" {
 		// Fallback: cluster does not emit a jti claim (ServiceAccountTokenJTI feature gate disabled or K8s < 1.29).
@@ -92,49 +318,116 @@ func (m *Manager) GenerateToken(ctx context.Context, user *UserContext, expirati
 		var errJTI error
 		jti, errJTI = generateLocalJTI()
 		if errJTI != nil {
-			return nil, fmt.Errorf("jti claim not found and fallback generation failed: %w", errJTI)
+			return "", 0, fmt.Errorf("jti claim not found and fallback generation failed: %w", errJTI)
 		}
 	}
 
-	// Extract iat (issued at) claim from JWT - required for K8s SA tokens
 	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		if len(claims) > 0 {
+			log.Warn("Issued token has no valid iat claim, using the current time", "jti", jti)
+		}
+		return jti, time.Now().Unix(), nil
+	}
+
+	return jti, iat.Unix(), nil
+}
+
+// TokenTier returns the tier of the token, based on the tier namespace of the service account it was issued for.
+// The token is not validated.
+func (m *Manager) TokenTier(tokenString string) (string, error) {
+	claims, err := extractClaims(tokenString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract iat claim: %w", err)
+		return "", err
 	}
-	if iat == nil {
-		return nil, errors.New("token is missing required 'iat' claim")
+
+	namespace, err := serviceAccountNamespace(claims)
+	if err != nil {
+		return "", err
 	}
-	issuedAt := iat.Unix()
 
-	log.Debug("Successfully generated token",
-		"expires_at", token.Status.ExpirationTimestamp.Unix(),
-		"jti", jti,
-	)
+	return m.tierMapper.TierForNamespace(namespace)
+}
 
-	result := &Token{
-		Token:      token.Status.Token,
-		Expiration: Duration{expiration},
-		ExpiresAt:  token.Status.ExpirationTimestamp.Unix(),
-		IssuedAt:   issuedAt,
-		JTI:        jti,
+// PreviewServiceAccount returns the Service Account the tokens of the user are issued for, without creating anything.
+// With groups, the tier and namespace are the ones the groups map to. Without, they are the ones of the existing
+// Service Account of the user, and are left empty when there is none. System users have no tier, see
+// ManagerOptions.SystemUsers.
+func (m *Manager) PreviewServiceAccount(username string, groups []string) (*ServiceAccountPreview, error) {
+	saName, err := m.sanitizeServiceAccountName(username)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	preview := &ServiceAccountPreview{
+		Username:          username,
+		ServiceAccountRef: ServiceAccountRef{Name: saName},
+	}
+
+	if len(groups) == 0 {
+		selector, errSelector := labels.Parse(serviceAccountSelector(m.tenantName))
+		if errSelector != nil {
+			return nil, fmt.Errorf("failed to parse service account selector: %w", errSelector)
+		}
+		serviceAccounts, errList := m.serviceAccountLister.List(selector)
+		if errList != nil {
+			return nil, fmt.Errorf("failed to list service accounts: %w", errList)
+		}
+		for _, sa := range serviceAccounts {
+			if sa.Name == saName {
+				preview.Namespace = sa.Namespace
+				preview.Tier = sa.Labels["maas.opendatahub.io/tier"]
+				preview.Exists = true
+				break
+			}
+		}
+		return preview, nil
+	}
+
+	if m.isSystemUser(username) {
+		preview.Namespace = m.options.SystemNamespace
+	} else {
+		userTier, errTier := m.tierMapper.GetTierForGroups(groups...)
+		if errTier != nil {
+			return nil, fmt.Errorf("failed to determine user tier for %s (groups: %v): %w", username, groups, errTier)
+		}
+
+		namespace, errNS := m.tierMapper.Namespace(userTier.Name)
+		if errNS != nil {
+			return nil, fmt.Errorf("failed to determine namespace for tier %s: %w", userTier.Name, errNS)
+		}
+		preview.Tier = userTier.Name
+		preview.Namespace = namespace
+	}
+	namespace := preview.Namespace
+
+	_, err = m.serviceAccountLister.ServiceAccounts(namespace).Get(saName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check service account %s in namespace %s: %w", saName, namespace, err)
+	}
+	preview.Exists = err == nil
+
+	return preview, nil
 }
 
 // RevokeTokens revokes all tokens for a user by recreating their Service Account.
 func (m *Manager) RevokeTokens(ctx context.Context, user *UserContext) error {
 	log := m.logger
 
-	userTier, err := m.tierMapper.GetTierForGroups(user.Groups...)
-	if err != nil {
-		return fmt.Errorf("failed to determine user tier for %s (groups: %v): %w", user.Username, user.Groups, err)
-	}
+	namespace, tierName := m.options.SystemNamespace, ""
+	if !m.isSystemUser(user.Username) {
+		userTier, err := m.UserTier(user)
+		if err != nil {
+			return fmt.Errorf("failed to determine user tier for %s (groups: %v): %w", user.Username, user.Groups, err)
+		}
 
-	log = log.WithFields("tier", userTier.Name)
-	namespace, errNS := m.tierMapper.Namespace(userTier.Name)
-	if errNS != nil {
-		return fmt.Errorf("failed to determine namespace for tier %s: %w", userTier.Name, errNS)
+		log = log.WithFields("tier", userTier.Name)
+		var errNS error
+		namespace, errNS = m.tierMapper.Namespace(userTier.Name)
+		if errNS != nil {
+			return fmt.Errorf("failed to determine namespace for tier %s: %w", userTier.Name, errNS)
+		}
+		tierName = userTier.Name
 	}
 
 	saName, errName := m.sanitizeServiceAccountName(user.Username)
@@ -142,7 +435,10 @@ func (m *Manager) RevokeTokens(ctx context.Context, user *UserContext) error {
 		return fmt.Errorf("failed to sanitize service account name for user %s: %w", user.Username, errName)
 	}
 
-	_, err = m.serviceAccountLister.ServiceAccounts(namespace).Get(saName)
+	unlock := m.serviceAccountLocks.lock(saName)
+	defer unlock()
+
+	_, err := m.serviceAccountLister.ServiceAccounts(namespace).Get(saName)
 	if apierrors.IsNotFound(err) {
 		log.Debug("Service account not found, nothing to revoke")
 		return nil
@@ -157,7 +453,8 @@ func (m *Manager) RevokeTokens(ctx context.Context, user *UserContext) error {
 		return fmt.Errorf("failed to delete service account %s in namespace %s: %w", saName, namespace, err)
 	}
 
-	_, err = m.ensureServiceAccount(ctx, namespace, user.Username, userTier.Name)
+	// The lister may still see the deleted Service Account, so it is recreated without consulting it.
+	err = m.createServiceAccount(ctx, namespace, saName, tierName)
 	if err != nil {
 		return fmt.Errorf("failed to recreate service account for user %s in namespace %s: %w", user.Username, namespace, err)
 	}
@@ -166,61 +463,353 @@ func (m *Manager) RevokeTokens(ctx context.Context, user *UserContext) error {
 	return nil
 }
 
-// ensureTierNamespace creates a tier-based namespace if it doesn't exist.
-// It takes a tier name, formats it as {instance}-tier-{tier}, and returns the namespace name.
-func (m *Manager) ensureTierNamespace(ctx context.Context, tier string) (string, error) {
-	namespace, errNs := m.tierMapper.Namespace(tier)
+// PruneServiceAccounts deletes the Service Accounts of the instance that no active token was issued for, and returns
+// them. A Service Account is kept when active tokens of its user were issued in its namespace, or in any namespace
+// when the namespace of the tokens is unknown, and while the latest token issued for it, e.g. an ephemeral one,
+// has not expired. In dry-run mode, the orphaned Service Accounts are only returned.
+// Deleting a Service Account revokes every token issued for it.
+func (m *Manager) PruneServiceAccounts(ctx context.Context, active []ActiveServiceAccount, dryRun bool) ([]ServiceAccountRef, error) {
+	keep := make(map[ServiceAccountRef]bool, len(active))
+	for _, account := range active {
+		// Usernames that cannot be sanitized never had a Service Account.
+		if saName, err := m.sanitizeServiceAccountName(account.Username); err == nil {
+			keep[ServiceAccountRef{Namespace: account.Namespace, Name: saName}] = true
+		}
+	}
+
+	// The cluster is queried rather than the informer cache, which may lag behind recent changes.
+	list, err := m.clientset.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: serviceAccountSelector(m.tenantName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+
+	now := time.Now()
+	orphans := []ServiceAccountRef{}
+	for i := range list.Items {
+		sa := &list.Items[i]
+		if keep[ServiceAccountRef{Namespace: sa.Namespace, Name: sa.Name}] || keep[ServiceAccountRef{Name: sa.Name}] ||
+			tokensValidAt(sa, now) {
+			continue
+		}
+
+		m.logger.Info("Found orphaned service account",
+			"namespace", sa.Namespace,
+			"service_account", sa.Name,
+			"dry_run", dryRun,
+		)
+
+		if !dryRun {
+			deleted, errDelete := m.deleteOrphanedServiceAccount(ctx, sa.Namespace, sa.Name, now)
+			if errDelete != nil {
+				return nil, errDelete
+			}
+			if !deleted {
+				continue
+			}
+		}
+
+		orphans = append(orphans, ServiceAccountRef{
+			Namespace: sa.Namespace,
+			Name:      sa.Name,
+			Tier:      sa.Labels["maas.opendatahub.io/tier"],
+		})
+	}
+
+	return orphans, nil
+}
+
+// deleteOrphanedServiceAccount deletes an orphaned Service Account, unless a token was issued for it since it was
+// listed, and reports whether it was deleted. The lock of the user is held, so that no token is issued meanwhile,
+// and the deletion is conditioned on the checked version, for tokens issued by other replicas.
+func (m *Manager) deleteOrphanedServiceAccount(ctx context.Context, namespace, saName string, now time.Time) (bool, error) {
+	unlock := m.serviceAccountLocks.lock(saName)
+	defer unlock()
+
+	sa, err := m.clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, saName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get service account %s in namespace %s: %w", saName, namespace, err)
+	}
+	if tokensValidAt(sa, now) {
+		m.logger.Info("Kept service account a token was issued for meanwhile",
+			"namespace", namespace,
+			"service_account", saName,
+		)
+		return false, nil
+	}
+
+	err = m.clientset.CoreV1().ServiceAccounts(namespace).Delete(ctx, saName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &sa.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to delete service account %s in namespace %s: %w", saName, namespace, err)
+	}
+
+	m.logger.Debug("Deleted orphaned service account",
+		"namespace", namespace,
+		"service_account", saName,
+	)
+	return true, nil
+}
+
+// tokensValidAt reports whether the latest token issued for the Service Account is still valid at the given time,
+// see tokensValidUntilAnnotation.
+func tokensValidAt(sa *corev1.ServiceAccount, now time.Time) bool {
+	validUntil, err := time.Parse(time.RFC3339, sa.Annotations[tokensValidUntilAnnotation])
+	return err == nil && validUntil.After(now)
+}
+
+// ensureTierNamespace creates a tier-based namespace if it doesn't exist, and reconciles the resource quota of the
+// tier in it, see reconcileTierResourceQuota. It resolves the namespace of the tier through the tier mapper and
+// returns the namespace name. When namespace management is disabled, the namespace is only looked up and must
+// already exist, its resource quota is left to administrators.
+func (m *Manager) ensureTierNamespace(ctx context.Context, userTier *tier.Tier) (string, error) {
+	namespace, errNs := m.tierMapper.Namespace(userTier.Name)
 	if errNs != nil {
-		return "", fmt.Errorf("failed to determine namespace for tier %q: %w", tier, errNs)
+		return "", fmt.Errorf("failed to determine namespace for tier %q: %w", userTier.Name, errNs)
 	}
 
 	_, err := m.namespaceLister.Get(namespace)
 	if err == nil {
-		return namespace, nil
+		if m.options.Unmanaged {
+			return namespace, nil
+		}
+		return namespace, m.reconcileTierResourceQuota(ctx, namespace, userTier)
 	}
 
 	if !apierrors.IsNotFound(err) {
 		return "", fmt.Errorf("failed to check namespace %s: %w", namespace, err)
 	}
 
+	if m.options.Unmanaged {
+		return "", fmt.Errorf("%w: namespace %s for tier %q must be created by an administrator when namespace management is disabled",
+			ErrTierNamespaceMissing, namespace, userTier.Name)
+	}
+
+	if err := m.checkNamespaceLimit(namespace, userTier); err != nil {
+		return "", err
+	}
+
+	labels, errLabels := tierNamespaceLabels(m.tenantName, userTier.Name, m.options.LabelTemplates)
+	if errLabels != nil {
+		return "", fmt.Errorf("failed to render labels for namespace %s: %w", namespace, errLabels)
+	}
+
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   namespace,
-			Labels: namespaceLabels(m.tenantName, tier),
+			Labels: labels,
 		},
 	}
 
 	_, err = m.clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 	if err != nil {
 		if apierrors.IsAlreadyExists(err) {
-			return namespace, nil
+			return namespace, m.reconcileTierResourceQuota(ctx, namespace, userTier)
+		}
+		if apierrors.IsForbidden(err) {
+			return m.forbiddenTierNamespace(namespace, userTier, err)
 		}
 		return "", fmt.Errorf("failed to create namespace %s: %w", namespace, err)
 	}
 
 	m.logger.Info("Created tier namespace",
-		"tier", tier,
+		"tier", userTier.Name,
 	)
-	return namespace, nil
+
+	return namespace, m.reconcileTierResourceQuota(ctx, namespace, userTier)
 }
 
-// ensureServiceAccount creates a service account if it doesn't exist.
-// It takes a raw username, sanitizes it for Kubernetes naming, and returns the sanitized name.
-func (m *Manager) ensureServiceAccount(ctx context.Context, namespace, username, userTier string) (string, error) {
-	saName, errName := m.sanitizeServiceAccountName(username)
-	if errName != nil {
-		return "", fmt.Errorf("failed to sanitize service account name for user %s: %w", username, errName)
+// checkNamespaceLimit returns ErrTierNamespaceLimit when the instance already has ManagerOptions.MaxNamespaces
+// tier namespaces. Namespaces are counted from the informer cache, so concurrent requests for distinct new tiers may
+// each pass the check.
+func (m *Manager) checkNamespaceLimit(namespace string, userTier *tier.Tier) error {
+	limit := m.options.MaxNamespaces
+	if limit <= 0 {
+		return nil
+	}
+
+	existing, err := m.namespaceLister.List(tierNamespaceSelector(m.tenantName))
+	if err != nil {
+		return fmt.Errorf("failed to count tier namespaces: %w", err)
+	}
+	if len(existing) < limit {
+		return nil
+	}
+
+	m.logger.Error("Not creating tier namespace: the maximum number of tier namespaces is reached, "+
+		"check the tier configuration or raise the limit",
+		"tier", userTier.Name,
+		"namespace", namespace,
+		"tier_namespaces", len(existing),
+		"max_tier_namespaces", limit,
+	)
+	return fmt.Errorf("%w: namespace %s for tier %q would exceed the maximum of %d tier namespaces",
+		ErrTierNamespaceLimit, namespace, userTier.Name, limit)
+}
+
+// forbiddenTierNamespace handles the RBAC denial of the creation of a tier namespace: it falls back to the configured
+// namespace if any, otherwise it fails with ErrTierNamespaceForbidden.
+func (m *Manager) forbiddenTierNamespace(namespace string, userTier *tier.Tier, err error) (string, error) {
+	if fallback := m.options.FallbackNamespace; fallback != "" {
+		m.logger.Warn("Not allowed to create tier namespace, using the fallback namespace",
+			"tier", userTier.Name,
+			"namespace", namespace,
+			"fallback_namespace", fallback,
+			"error", err,
+		)
+		return fallback, nil
+	}
+
+	m.logger.Error("Not allowed to create tier namespace: grant the maas-api service account create on namespaces, "+
+		"pre-create the namespace or configure a fallback namespace",
+		"tier", userTier.Name,
+		"namespace", namespace,
+		"error", err,
+	)
+	return "", fmt.Errorf("%w: the maas-api service account lacks permission to create namespace %s for tier %q: %w",
+		ErrTierNamespaceForbidden, namespace, userTier.Name, err)
+}
+
+// reconcileTierResourceQuota applies the resource quota of the tier to its namespace, unless this process already
+// applied the same hard limits. Changes to the tier configuration are thus reconciled the next time the namespace is
+// resolved, while changes made to the quota in the cluster are only reverted once maas-api restarts.
+func (m *Manager) reconcileTierResourceQuota(ctx context.Context, namespace string, userTier *tier.Tier) error {
+	applied := resourceListKey(userTier.ResourceQuotaHard())
+
+	m.appliedQuotasMu.Lock()
+	current, known := m.appliedQuotas[namespace]
+	m.appliedQuotasMu.Unlock()
+	if known && current == applied {
+		return nil
+	}
+
+	if err := m.applyTierResourceQuota(ctx, namespace, userTier); err != nil {
+		return err
+	}
+
+	m.appliedQuotasMu.Lock()
+	m.appliedQuotas[namespace] = applied
+	m.appliedQuotasMu.Unlock()
+	return nil
+}
+
+// resourceListKey returns a canonical representation of the resource list, to compare resource quotas.
+func resourceListKey(resources corev1.ResourceList) string {
+	entries := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		entries = append(entries, string(name)+"="+quantity.String())
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, ",")
+}
+
+// applyTierResourceQuota creates the resource quota of the tier in its namespace, or updates its hard limits
+// when it already exists. The resource quota is deleted when the tier sets none.
+func (m *Manager) applyTierResourceQuota(ctx context.Context, namespace string, userTier *tier.Tier) error {
+	hard := userTier.ResourceQuotaHard()
+	quotas := m.clientset.CoreV1().ResourceQuotas(namespace)
+	if len(hard) == 0 {
+		// The tier may have dropped its quota since it was applied.
+		err := quotas.Delete(ctx, TierResourceQuotaName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete resource quota %s in namespace %s: %w", TierResourceQuotaName, namespace, err)
+		}
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TierResourceQuotaName,
+			Namespace: namespace,
+			Labels:    resourceQuotaLabels(m.tenantName, userTier.Name),
+		},
+		Spec: corev1.ResourceQuotaSpec{Hard: hard},
+	}
+
+	_, err := quotas.Create(ctx, quota, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, errGet := quotas.Get(ctx, TierResourceQuotaName, metav1.GetOptions{})
+		if errGet != nil {
+			return fmt.Errorf("failed to get resource quota %s in namespace %s: %w", TierResourceQuotaName, namespace, errGet)
+		}
+		existing.Spec.Hard = hard
+		_, err = quotas.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply resource quota %s in namespace %s: %w", TierResourceQuotaName, namespace, err)
+	}
+
+	m.logger.Info("Applied tier resource quota",
+		"tier", userTier.Name,
+	)
+	return nil
+}
+
+// issueServiceAccountToken ensures the service account exists and creates a token for it.
+// Callers must hold the user lock for saName.
+func (m *Manager) issueServiceAccountToken(ctx context.Context, namespace, saName, userTier string, ttl int) (*authv1.TokenRequest, error) {
+	if err := m.ensureServiceAccount(ctx, namespace, saName, userTier); err != nil {
+		return nil, err
+	}
+
+	// Recorded before the token is issued, so that the Service Account is never pruned with a valid token.
+	if err := m.recordTokenExpiration(ctx, namespace, saName, time.Now().Add(time.Duration(ttl)*time.Second)); err != nil {
+		return nil, err
+	}
+
+	return m.createServiceAccountToken(ctx, namespace, saName, ttl)
+}
+
+// recordTokenExpiration raises the tokensValidUntilAnnotation of the Service Account to the expiration of a token
+// issued for it. The annotation is never lowered, tokens are issued with varying expirations.
+func (m *Manager) recordTokenExpiration(ctx context.Context, namespace, saName string, expiresAt time.Time) error {
+	// The lister may lag behind, keeping a later expiration only delays pruning.
+	if sa, err := m.serviceAccountLister.ServiceAccounts(namespace).Get(saName); err == nil {
+		if validUntil, errParse := time.Parse(time.RFC3339, sa.Annotations[tokensValidUntilAnnotation]); errParse == nil && validUntil.After(expiresAt) {
+			expiresAt = validUntil
+		}
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{tokensValidUntilAnnotation: expiresAt.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode token expiration: %w", err)
+	}
+
+	_, err = m.clientset.CoreV1().ServiceAccounts(namespace).Patch(ctx, saName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to record token expiration on service account %s in namespace %s: %w", saName, namespace, err)
 	}
+	return nil
+}
 
+// ensureServiceAccount creates a service account if it doesn't exist.
+func (m *Manager) ensureServiceAccount(ctx context.Context, namespace, saName, userTier string) error {
 	_, err := m.serviceAccountLister.ServiceAccounts(namespace).Get(saName)
 	if err == nil {
-		return saName, nil
+		return nil
 	}
 
 	if !apierrors.IsNotFound(err) {
-		return "", fmt.Errorf("failed to check service account %s in namespace %s: %w", saName, namespace, err)
+		return fmt.Errorf("failed to check service account %s in namespace %s: %w", saName, namespace, err)
 	}
 
+	return m.createServiceAccount(ctx, namespace, saName, userTier)
+}
+
+// createServiceAccount creates a service account, treating an already existing one as success.
+func (m *Manager) createServiceAccount(ctx context.Context, namespace, saName, userTier string) error {
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      saName,
@@ -229,28 +818,33 @@ func (m *Manager) ensureServiceAccount(ctx context.Context, namespace, username,
 		},
 	}
 
-	_, err = m.clientset.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
+	_, err := m.clientset.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
 	if err != nil {
 		if apierrors.IsAlreadyExists(err) {
-			return saName, nil
+			return nil
 		}
-		return "", fmt.Errorf("failed to create service account %s in namespace %s: %w", saName, namespace, err)
+		return fmt.Errorf("failed to create service account %s in namespace %s: %w", saName, namespace, err)
 	}
 
 	m.logger.Debug("Created service account",
 		"tier", userTier,
 	)
-	return saName, nil
+	return nil
 }
 
 // createServiceAccountToken creates a token for the service account using TokenRequest.
 func (m *Manager) createServiceAccountToken(ctx context.Context, namespace, saName string, ttl int) (*authv1.TokenRequest, error) {
 	expirationSeconds := int64(ttl)
 
+	audiences := m.audiences
+	if len(audiences) == 0 {
+		audiences = []string{m.tenantName + "-sa"}
+	}
+
 	tokenRequest := &authv1.TokenRequest{
 		Spec: authv1.TokenRequestSpec{
 			ExpirationSeconds: &expirationSeconds,
-			Audiences:         []string{m.tenantName + "-sa"},
+			Audiences:         audiences,
 		},
 	}
 
@@ -294,7 +888,7 @@ func (m *Manager) sanitizeServiceAccountName(username string) (string, error) {
 	name = reDash.ReplaceAllString(name, "-")
 	name = strings.Trim(name, "-")
 	if name == "" {
-		return "", fmt.Errorf("invalid username %q", username)
+		return "", fmt.Errorf("%w %q", ErrInvalidUsername, username)
 	}
 
 	// Append a stable short hash to reduce collisions
@@ -314,11 +908,11 @@ func (m *Manager) sanitizeServiceAccountName(username string) (string, error) {
 
 // generateLocalJTI generates a local JTI identifier when the cluster does not provide one.
 // This is needed for clusters running Kubernetes < 1.29 or when ServiceAccountTokenJTI feature gate is disabled.
+// It is a random UUID, like the jti claim set by the API server, so that IDs share one format whatever their origin.
 func generateLocalJTI() (string, error) {
-	const size = 16
-	b := make([]byte, size)
-	if _, err := rand.Read(b); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes for JTI: %w", err)
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random UUID for JTI: %w", err)
 	}
-	return hex.EncodeToString(b), nil
+	return id.String(), nil
 }
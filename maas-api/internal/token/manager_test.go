@@ -0,0 +1,880 @@
+package token_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/test/fixtures"
+)
+
+func TestGenerateToken_TierNamespaceLabels(t *testing.T) {
+	templates, err := token.ParseLabelTemplates([]string{
+		"cost-center=ai-{tier}",
+		"example.com/instance={instance}",
+		"team=platform",
+	})
+	require.NoError(t, err)
+
+	manager, fakeClient, cleanup := fixtures.StubTokenProviderAPIsWithOptions(t, true, token.ManagerOptions{
+		LabelTemplates: templates,
+	})
+	defer cleanup()
+
+	user := &token.UserContext{Username: "labels-user", Groups: []string{"system:authenticated"}}
+	_, err = manager.GenerateToken(t.Context(), user, time.Hour, "")
+	require.NoError(t, err)
+
+	ns, err := fakeClient.CoreV1().Namespaces().Get(t.Context(), fixtures.TestTenant+"-tier-free", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ai-free", ns.Labels["cost-center"])
+	assert.Equal(t, fixtures.TestTenant, ns.Labels["example.com/instance"])
+	assert.Equal(t, "platform", ns.Labels["team"])
+	assert.Equal(t, "free", ns.Labels["maas.opendatahub.io/tier"], "maas labels must still be set")
+	assert.Equal(t, "true", ns.Labels["maas.opendatahub.io/tier-namespace"], "maas labels must still be set")
+}
+
+func TestGenerateToken_MaxNamespaces(t *testing.T) {
+	tierNamespace := func(name, instance string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app.kubernetes.io/component":        "token-issuer",
+				"app.kubernetes.io/part-of":          "maas-api",
+				"maas.opendatahub.io/instance":       instance,
+				"maas.opendatahub.io/tier-namespace": "true",
+			},
+		}}
+	}
+	premium := tierNamespace(fixtures.TestTenant+"-tier-premium", fixtures.TestTenant)
+	// Namespaces of other instances do not count towards the cap.
+	otherInstance := tierNamespace("other-tier-premium", "other")
+
+	newManager := func(maxNamespaces int) (*token.Manager, *k8sfake.Clientset) {
+		fakeClient := k8sfake.NewClientset(premium, otherInstance)
+		fixtures.StubServiceAccountTokenCreation(fakeClient)
+		manager := token.NewManager(logger.Development(), fixtures.TestTenant, fixtures.CreateTestMapper(true),
+			fakeClient, fixtures.NewNamespaceLister(premium, otherInstance), fixtures.NewServiceAccountLister(),
+			token.ManagerOptions{MaxNamespaces: maxNamespaces})
+		return manager, fakeClient
+	}
+	freeUser := &token.UserContext{Username: "free-user", Groups: []string{"system:authenticated"}}
+
+	t.Run("creating beyond the cap is rejected", func(t *testing.T) {
+		manager, fakeClient := newManager(1)
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.ErrorIs(t, err, token.ErrTierNamespaceLimit)
+
+		_, err = fakeClient.CoreV1().Namespaces().Get(t.Context(), fixtures.TestTenant+"-tier-free", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "the namespace must not be created")
+	})
+
+	t.Run("existing namespaces are used at the cap", func(t *testing.T) {
+		manager, _ := newManager(1)
+
+		premiumUser := &token.UserContext{Username: "premium-user", Groups: []string{"premium-users"}}
+		_, err := manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("creating below the cap is allowed", func(t *testing.T) {
+		manager, fakeClient := newManager(2)
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.NoError(t, err)
+
+		_, err = fakeClient.CoreV1().Namespaces().Get(t.Context(), fixtures.TestTenant+"-tier-free", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func TestGenerateToken_SystemUsers(t *testing.T) {
+	const systemNamespace = "platform-system"
+
+	manager, fakeClient, cleanup := fixtures.StubTokenProviderAPIsWithOptions(t, true, token.ManagerOptions{
+		SystemUsers:     []string{"system:serviceaccount:platform:*", "operator@example.com"},
+		SystemNamespace: systemNamespace,
+	})
+	defer cleanup()
+
+	tierNamespace := fixtures.TestTenant + "-tier-free"
+
+	tests := []struct {
+		name              string
+		username          string
+		groups            []string
+		expectedNamespace string
+		expectedTier      string
+	}{
+		{
+			name:              "user matching a pattern gets the system namespace",
+			username:          "system:serviceaccount:platform:operator",
+			expectedNamespace: systemNamespace,
+		},
+		{
+			name:              "user matching exactly gets the system namespace without resolving a tier",
+			username:          "operator@example.com",
+			groups:            []string{"unknown-group"},
+			expectedNamespace: systemNamespace,
+		},
+		{
+			name:              "other user gets the tier namespace",
+			username:          "alice@example.com",
+			groups:            []string{"system:authenticated"},
+			expectedNamespace: tierNamespace,
+			expectedTier:      "free",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &token.UserContext{Username: tt.username, Groups: tt.groups}
+			_, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+			require.NoError(t, err)
+
+			preview, err := manager.PreviewServiceAccount(tt.username, []string{"system:authenticated"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNamespace, preview.Namespace)
+
+			sa, err := fakeClient.CoreV1().ServiceAccounts(tt.expectedNamespace).Get(t.Context(), preview.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTier, sa.Labels["maas.opendatahub.io/tier"])
+		})
+	}
+
+	_, err := fakeClient.CoreV1().Namespaces().Get(t.Context(), systemNamespace, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the system namespace must not be created")
+}
+
+func TestValidateSystemUsers(t *testing.T) {
+	require.NoError(t, token.ValidateSystemUsers([]string{"system:serviceaccount:platform:*", "operator@example.com"}))
+
+	err := token.ValidateSystemUsers([]string{"", "team-[a"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be empty")
+	assert.Contains(t, err.Error(), `invalid system user pattern "team-[a"`)
+}
+
+func TestGenerateToken_Audiences(t *testing.T) {
+	tests := []struct {
+		name              string
+		audiences         []string
+		expectedAudiences []string
+	}{
+		{
+			name:              "defaults to the instance audience",
+			expectedAudiences: []string{fixtures.TestTenant + "-sa"},
+		},
+		{
+			name:              "carries the configured audiences without duplicates",
+			audiences:         []string{fixtures.TestTenant + "-sa", "internal-gateway-sa", "edge-gateway-sa", "internal-gateway-sa"},
+			expectedAudiences: []string{fixtures.TestTenant + "-sa", "internal-gateway-sa", "edge-gateway-sa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, fakeClient, cleanup := fixtures.StubTokenProviderAPIsWithOptions(t, true, token.ManagerOptions{
+				Audiences: tt.audiences,
+			})
+			defer cleanup()
+
+			var requested []string
+			fakeClient.PrependReactor("create", "serviceaccounts/token", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				createAction, ok := action.(k8stesting.CreateAction)
+				require.True(t, ok)
+				tokenRequest, ok := createAction.GetObject().(*authv1.TokenRequest)
+				require.True(t, ok)
+				requested = tokenRequest.Spec.Audiences
+				return false, nil, nil
+			})
+
+			user := &token.UserContext{Username: "audience-user", Groups: []string{"system:authenticated"}}
+			_, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedAudiences, requested)
+		})
+	}
+}
+
+func TestGenerateToken_LocalJTI(t *testing.T) {
+	const tokens = 1000
+
+	// Clusters without the ServiceAccountTokenJTI feature issue tokens without a jti claim.
+	fakeClient := k8sfake.NewClientset()
+	fakeClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		now := time.Now()
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iat": now.Unix(),
+			"exp": now.Add(time.Hour).Unix(),
+			"sub": "system:serviceaccount:" + action.GetNamespace() + ":local-jti-user",
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			return true, nil, err
+		}
+		return true, &authv1.TokenRequest{Status: authv1.TokenRequestStatus{
+			Token:               signed,
+			ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+		}}, nil
+	})
+
+	manager := token.NewManager(logger.Development(), fixtures.TestTenant, fixtures.CreateTestMapper(true),
+		fakeClient, fixtures.NewNamespaceLister(), fixtures.NewServiceAccountLister(), token.ManagerOptions{})
+	user := &token.UserContext{Username: "local-jti-user", Groups: []string{"system:authenticated"}}
+
+	seen := make(map[string]bool, tokens)
+	for range tokens {
+		issued, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+		require.NoError(t, err)
+
+		id, err := uuid.Parse(issued.JTI)
+		require.NoError(t, err, "local JTIs are UUIDs: %s", issued.JTI)
+		assert.Equal(t, uuid.Version(4), id.Version())
+		require.False(t, seen[issued.JTI], "JTI %s generated twice", issued.JTI)
+		seen[issued.JTI] = true
+	}
+}
+
+func TestGenerateToken_UnreadableClaims(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(namespace string, now time.Time) string
+	}{
+		{
+			name: "opaque token",
+			token: func(string, time.Time) string {
+				return "opaque-token-from-a-non-standard-api-server"
+			},
+		},
+		{
+			name: "token without jti and iat claims",
+			token: func(namespace string, now time.Time) string {
+				signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"exp": now.Add(time.Hour).Unix(),
+					"sub": "system:serviceaccount:" + namespace + ":opaque-user",
+				}).SignedString([]byte("secret"))
+				require.NoError(t, err)
+				return signed
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+			var issuedToken string
+
+			fakeClient := k8sfake.NewClientset()
+			fakeClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "token" {
+					return false, nil, nil
+				}
+				issuedToken = tt.token(action.GetNamespace(), time.Now())
+				return true, &authv1.TokenRequest{Status: authv1.TokenRequestStatus{
+					Token:               issuedToken,
+					ExpirationTimestamp: metav1.NewTime(expiresAt),
+				}}, nil
+			})
+
+			manager := token.NewManager(logger.Development(), fixtures.TestTenant, fixtures.CreateTestMapper(true),
+				fakeClient, fixtures.NewNamespaceLister(), fixtures.NewServiceAccountLister(), token.ManagerOptions{})
+			user := &token.UserContext{Username: "opaque-user", Groups: []string{"system:authenticated"}}
+
+			before := time.Now().Unix()
+			issued, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+			require.NoError(t, err, "a valid token must be issued even when its claims cannot be read")
+
+			assert.Equal(t, issuedToken, issued.Token)
+			assert.Equal(t, expiresAt.Unix(), issued.ExpiresAt, "expiration must come from the TokenRequest")
+			assert.GreaterOrEqual(t, issued.IssuedAt, before)
+			_, err = uuid.Parse(issued.JTI)
+			assert.NoError(t, err, "a synthetic JTI must track the token: %s", issued.JTI)
+		})
+	}
+}
+
+func TestGenerateToken_ExpirationJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		percent  int
+		request  time.Duration
+		min, max time.Duration
+	}{
+		{name: "spread around the requested expiration", percent: 20, request: time.Hour, min: 48 * time.Minute, max: 72 * time.Minute},
+		{name: "clamped to the minimum expiration", percent: 50, request: 12 * time.Minute, min: 10 * time.Minute, max: 18 * time.Minute},
+		{name: "clamped to the maximum of the tier", percent: 20, request: 2100 * time.Hour, min: 1680 * time.Hour, max: 2160 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, _, cleanup := fixtures.StubTokenProviderAPIsWithOptions(t, true, token.ManagerOptions{
+				ExpirationJitterPercent: tt.percent,
+			})
+			defer cleanup()
+
+			user := &token.UserContext{Username: "jitter-user", Groups: []string{"system:authenticated"}}
+			granted := make(map[time.Duration]bool)
+			for range 100 {
+				issued, err := manager.GenerateToken(t.Context(), user, tt.request, "")
+				require.NoError(t, err)
+
+				assert.GreaterOrEqual(t, issued.Expiration.Duration, tt.min)
+				assert.LessOrEqual(t, issued.Expiration.Duration, tt.max)
+				assert.InDelta(t, time.Now().Add(issued.Expiration.Duration).Unix(), issued.ExpiresAt, 2,
+					"the token must expire after the granted expiration")
+				granted[issued.Expiration.Duration] = true
+			}
+			assert.Greater(t, len(granted), 1, "expirations must be spread")
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+		defer cleanup()
+
+		user := &token.UserContext{Username: "jitter-user", Groups: []string{"system:authenticated"}}
+		issued, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, issued.Expiration.Duration)
+	})
+}
+
+func TestUserTier_ResolvedOncePerRequest(t *testing.T) {
+	manager, _, cleanup := fixtures.StubTokenProviderAPIs(t, true)
+	defer cleanup()
+
+	user := &token.UserContext{Username: "tier-user", Groups: []string{"premium-users"}}
+	resolutions := tierResolutionCount(t, "premium")
+
+	first, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+	require.NoError(t, err)
+	require.NotNil(t, user.Tier, "the resolved tier must be kept on the user")
+	assert.Equal(t, "premium", user.Tier.Name)
+
+	second, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+	require.NoError(t, err)
+	require.NoError(t, manager.RevokeTokens(t.Context(), user))
+
+	assert.NotEqual(t, first.JTI, second.JTI)
+	assert.Equal(t, resolutions+1, tierResolutionCount(t, "premium"), "the tier must be resolved once and reused")
+}
+
+// tierResolutionCount returns the number of successful resolutions of the tier recorded by tier_resolution_total.
+func tierResolutionCount(t *testing.T, tierName string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "tier_resolution_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["tier"] == tierName && labels["fallback"] == "false" {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestGenerateToken_LogsIssuance(t *testing.T) {
+	fakeClient := k8sfake.NewClientset()
+	fixtures.StubServiceAccountTokenCreation(fakeClient)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	manager := token.NewManager(
+		logger.FromZap(zap.New(core), false),
+		fixtures.TestTenant,
+		fixtures.CreateTestMapper(true),
+		fakeClient,
+		fixtures.NewNamespaceLister(),
+		fixtures.NewServiceAccountLister(),
+		token.ManagerOptions{},
+	)
+
+	user := &token.UserContext{Username: "audited-user", Groups: []string{"premium-users"}}
+	issued, err := manager.GenerateToken(t.Context(), user, time.Hour, "")
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("Issued token").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "audited-user", fields["username"])
+	assert.Equal(t, "premium", fields["tier"])
+	assert.Equal(t, fixtures.TestTenant+"-tier-premium", fields["namespace"])
+	assert.Equal(t, issued.JTI, fields["jti"])
+	assert.Equal(t, "1h0m0s", fields["expiration"])
+
+	assert.Empty(t, logs.FilterMessage("Issued token details").All(), "details are only logged in debug mode")
+	for _, entry := range logs.All() {
+		for _, value := range entry.ContextMap() {
+			assert.NotEqual(t, issued.Token, value, "the token must never be logged")
+		}
+	}
+}
+
+func TestParseLabelTemplates(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:     "valid templates",
+			entries:  []string{"cost-center = ai-{tier}", "team=platform"},
+			expected: map[string]string{"cost-center": "ai-{tier}", "team": "platform"},
+		},
+		{
+			name:     "empty value",
+			entries:  []string{"team="},
+			expected: map[string]string{"team": ""},
+		},
+		{
+			name:        "maas label is reserved",
+			entries:     []string{"maas.opendatahub.io/tier=premium"},
+			expectError: true,
+		},
+		{
+			name:        "component label is reserved",
+			entries:     []string{"app.kubernetes.io/part-of=other"},
+			expectError: true,
+		},
+		{
+			name:        "invalid key",
+			entries:     []string{"not a key=value"},
+			expectError: true,
+		},
+		{
+			name:        "missing value separator",
+			entries:     []string{"team"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := token.ParseLabelTemplates(tt.entries)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, templates)
+		})
+	}
+}
+
+func TestGenerateToken_NamespaceManagement(t *testing.T) {
+	const tierConfig = `
+- name: free
+  level: 1
+  groups:
+  - system:authenticated
+- name: premium
+  level: 10
+  namespace: platform-premium
+  groups:
+  - premium-users
+`
+
+	newManager := func(t *testing.T, options token.ManagerOptions, namespaces ...*corev1.Namespace) (*token.Manager, *k8sfake.Clientset) {
+		t.Helper()
+
+		configMap := fixtures.CreateTierConfigMap(fixtures.TestNamespace)
+		configMap.Data["tiers"] = tierConfig
+
+		objects := make([]runtime.Object, 0, len(namespaces))
+		for _, ns := range namespaces {
+			objects = append(objects, ns)
+		}
+		fakeClient := k8sfake.NewClientset(objects...)
+		fixtures.StubServiceAccountTokenCreation(fakeClient)
+
+		testLogger := logger.Development()
+		manager := token.NewManager(
+			testLogger,
+			fixtures.TestTenant,
+			tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+			fakeClient,
+			fixtures.NewNamespaceLister(namespaces...),
+			fixtures.NewServiceAccountLister(),
+			options,
+		)
+		return manager, fakeClient
+	}
+
+	namespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	freeUser := &token.UserContext{Username: "free-user", Groups: []string{"system:authenticated"}}
+	premiumUser := &token.UserContext{Username: "premium-user", Groups: []string{"premium-users"}}
+
+	t.Run("auto-create creates the conventional namespace", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.NoError(t, err)
+
+		_, err = fakeClient.CoreV1().Namespaces().Get(t.Context(), fixtures.TestTenant+"-tier-free", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("auto-create honors the namespace set in the tier configuration", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+
+		_, err := manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+
+		_, err = fakeClient.CoreV1().Namespaces().Get(t.Context(), "platform-premium", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("BYO uses the pre-created namespace", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{Unmanaged: true}, namespace("platform-premium"))
+
+		_, err := manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+
+		sa, err := fakeClient.CoreV1().ServiceAccounts("platform-premium").List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, sa.Items, 1, "service account should be created in the pre-created namespace")
+	})
+
+	t.Run("BYO fails when the namespace is missing", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{Unmanaged: true})
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.ErrorIs(t, err, token.ErrTierNamespaceMissing)
+		assert.Contains(t, err.Error(), fixtures.TestTenant+"-tier-free")
+
+		namespaces, err := fakeClient.CoreV1().Namespaces().List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, namespaces.Items, "no namespace must be created when namespace management is disabled")
+	})
+
+	forbidNamespaceCreation := func(fakeClient *k8sfake.Clientset) {
+		fakeClient.PrependReactor("create", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.CreateAction).GetObject().(*corev1.Namespace).Name
+			return true, nil, apierrors.NewForbidden(corev1.Resource("namespaces"), name,
+				errors.New(`User "system:serviceaccount:maas-api:maas-api" cannot create resource "namespaces"`))
+		})
+	}
+
+	t.Run("forbidden creation fails with a clear error", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+		forbidNamespaceCreation(fakeClient)
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.ErrorIs(t, err, token.ErrTierNamespaceForbidden)
+		assert.Contains(t, err.Error(), "lacks permission to create namespace "+fixtures.TestTenant+"-tier-free")
+		assert.True(t, apierrors.IsForbidden(err), "the RBAC error must be wrapped")
+	})
+
+	t.Run("forbidden creation uses the fallback namespace", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{FallbackNamespace: "maas-shared"}, namespace("maas-shared"))
+		forbidNamespaceCreation(fakeClient)
+
+		_, err := manager.GenerateToken(t.Context(), freeUser, time.Hour, "")
+		require.NoError(t, err)
+
+		sa, err := fakeClient.CoreV1().ServiceAccounts("maas-shared").List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, sa.Items, 1, "service account should be created in the fallback namespace")
+	})
+}
+
+func TestGenerateToken_TierResourceQuota(t *testing.T) {
+	const tierConfig = `
+- name: free
+  level: 1
+  groups:
+  - system:authenticated
+- name: premium
+  level: 10
+  resourceQuota:
+    count/serviceaccounts: 500
+    requests.cpu: "4"
+  groups:
+  - premium-users
+`
+
+	newManager := func(t *testing.T, options token.ManagerOptions, namespaces ...*corev1.Namespace) (*token.Manager, *k8sfake.Clientset) {
+		t.Helper()
+
+		configMap := fixtures.CreateTierConfigMap(fixtures.TestNamespace)
+		configMap.Data["tiers"] = tierConfig
+
+		fakeClient := k8sfake.NewClientset()
+		fixtures.StubServiceAccountTokenCreation(fakeClient)
+
+		testLogger := logger.Development()
+		manager := token.NewManager(
+			testLogger,
+			fixtures.TestTenant,
+			tier.NewMapper(testLogger, fixtures.NewConfigMapLister(configMap), fixtures.TestTenant, fixtures.TestNamespace, tier.MapperOptions{}),
+			fakeClient,
+			fixtures.NewNamespaceLister(namespaces...),
+			fixtures.NewServiceAccountLister(),
+			options,
+		)
+		return manager, fakeClient
+	}
+
+	t.Run("tier with a resource quota", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+
+		_, err := manager.GenerateToken(t.Context(), &token.UserContext{Username: "premium-user", Groups: []string{"premium-users"}}, time.Hour, "")
+		require.NoError(t, err)
+
+		namespace := fixtures.TestTenant + "-tier-premium"
+		quota, err := fakeClient.CoreV1().ResourceQuotas(namespace).Get(t.Context(), token.TierResourceQuotaName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "premium", quota.Labels["maas.opendatahub.io/tier"])
+		assert.True(t, resource.MustParse("500").Equal(quota.Spec.Hard[corev1.ResourceName("count/serviceaccounts")]))
+		assert.True(t, resource.MustParse("4").Equal(quota.Spec.Hard[corev1.ResourceRequestsCPU]))
+	})
+
+	t.Run("existing quota is updated", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+
+		namespace := fixtures.TestTenant + "-tier-premium"
+		_, err := fakeClient.CoreV1().ResourceQuotas(namespace).Create(t.Context(), &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: token.TierResourceQuotaName, Namespace: namespace},
+			Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		_, err = manager.GenerateToken(t.Context(), &token.UserContext{Username: "premium-user", Groups: []string{"premium-users"}}, time.Hour, "")
+		require.NoError(t, err)
+
+		quota, err := fakeClient.CoreV1().ResourceQuotas(namespace).Get(t.Context(), token.TierResourceQuotaName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.True(t, resource.MustParse("4").Equal(quota.Spec.Hard[corev1.ResourceRequestsCPU]))
+	})
+
+	t.Run("tier without a resource quota", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{})
+
+		_, err := manager.GenerateToken(t.Context(), &token.UserContext{Username: "free-user", Groups: []string{"system:authenticated"}}, time.Hour, "")
+		require.NoError(t, err)
+
+		quotas, err := fakeClient.CoreV1().ResourceQuotas(fixtures.TestTenant+"-tier-free").List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, quotas.Items)
+	})
+
+	premiumUser := &token.UserContext{Username: "premium-user", Groups: []string{"premium-users"}}
+	premiumNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fixtures.TestTenant + "-tier-premium"}}
+
+	t.Run("quota of an existing namespace is reconciled", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{}, premiumNamespace)
+
+		_, err := manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+
+		quota, err := fakeClient.CoreV1().ResourceQuotas(premiumNamespace.Name).Get(t.Context(), token.TierResourceQuotaName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.True(t, resource.MustParse("4").Equal(quota.Spec.Hard[corev1.ResourceRequestsCPU]))
+
+		// Reverting the quota in the cluster is not noticed until the next restart, the quota is applied once.
+		require.NoError(t, fakeClient.CoreV1().ResourceQuotas(premiumNamespace.Name).Delete(t.Context(), token.TierResourceQuotaName, metav1.DeleteOptions{}))
+		_, err = manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+		_, err = fakeClient.CoreV1().ResourceQuotas(premiumNamespace.Name).Get(t.Context(), token.TierResourceQuotaName, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "the quota must not be applied on every token request")
+	})
+
+	t.Run("quota dropped from the tier is deleted", func(t *testing.T) {
+		freeNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fixtures.TestTenant + "-tier-free"}}
+		manager, fakeClient := newManager(t, token.ManagerOptions{}, freeNamespace)
+
+		_, err := fakeClient.CoreV1().ResourceQuotas(freeNamespace.Name).Create(t.Context(), &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: token.TierResourceQuotaName, Namespace: freeNamespace.Name},
+			Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		_, err = manager.GenerateToken(t.Context(), &token.UserContext{Username: "free-user", Groups: []string{"system:authenticated"}}, time.Hour, "")
+		require.NoError(t, err)
+
+		_, err = fakeClient.CoreV1().ResourceQuotas(freeNamespace.Name).Get(t.Context(), token.TierResourceQuotaName, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "the quota must be deleted, got %v", err)
+	})
+
+	t.Run("quota of unmanaged namespaces is left to administrators", func(t *testing.T) {
+		manager, fakeClient := newManager(t, token.ManagerOptions{Unmanaged: true}, premiumNamespace)
+
+		_, err := manager.GenerateToken(t.Context(), premiumUser, time.Hour, "")
+		require.NoError(t, err)
+
+		quotas, err := fakeClient.CoreV1().ResourceQuotas(premiumNamespace.Name).List(t.Context(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, quotas.Items)
+	})
+}
+
+func TestGenerateToken_ConcurrentRevocation(t *testing.T) {
+	const (
+		username   = "racing-user"
+		iterations = 20
+	)
+
+	namespace := fixtures.TestTenant + "-tier-free"
+	user := &token.UserContext{Username: username, Groups: []string{"system:authenticated"}}
+
+	fakeClient := k8sfake.NewClientset()
+	fixtures.StubServiceAccountTokenCreation(fakeClient)
+
+	var (
+		mu                 sync.Mutex
+		serviceAccountLive = true
+		mintedWhileDeleted int
+	)
+	fakeClient.PrependReactor("delete", "serviceaccounts", func(k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		serviceAccountLive = false
+		mu.Unlock()
+		return true, nil, nil
+	})
+	fakeClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if action.GetSubresource() == "token" {
+			if !serviceAccountLive {
+				mintedWhileDeleted++
+			}
+			return false, nil, nil
+		}
+		serviceAccountLive = true
+		return true, nil, nil
+	})
+
+	// The cached Service Account makes GenerateToken skip creation and RevokeTokens proceed with the recreation.
+	probe := token.NewManager(logger.Development(), fixtures.TestTenant, fixtures.CreateTestMapper(true),
+		fakeClient, fixtures.NewNamespaceLister(), fixtures.NewServiceAccountLister(), token.ManagerOptions{})
+	generated, err := probe.GenerateToken(t.Context(), user, time.Hour, "")
+	require.NoError(t, err)
+	saName := serviceAccountFromToken(t, generated.Token)
+
+	testLogger := logger.Development()
+	manager := token.NewManager(
+		testLogger,
+		fixtures.TestTenant,
+		fixtures.CreateTestMapper(true),
+		slowDeleteClientset{fakeClient},
+		fixtures.NewNamespaceLister(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
+		fixtures.NewServiceAccountLister(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: namespace}}),
+		token.ManagerOptions{},
+	)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*iterations)
+	for range iterations {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, errGen := manager.GenerateToken(t.Context(), user, time.Hour, "")
+			errs <- errGen
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- manager.RevokeTokens(t.Context(), user)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for errOp := range errs {
+		require.NoError(t, errOp)
+	}
+	assert.Zero(t, mintedWhileDeleted, "no token must be minted while the service account is deleted")
+	assert.True(t, serviceAccountLive, "service account must exist after revocation")
+}
+
+// slowDeleteClientset pauses after deleting a service account, widening the window between the deletion
+// and the recreation of the service account during revocation. Reactors of the fake clientset run under
+// its lock, so the pause has to happen outside of it.
+type slowDeleteClientset struct {
+	kubernetes.Interface
+}
+
+//nolint:ireturn // test helper
+func (c slowDeleteClientset) CoreV1() corev1client.CoreV1Interface {
+	return slowDeleteCoreV1{c.Interface.CoreV1()}
+}
+
+type slowDeleteCoreV1 struct {
+	corev1client.CoreV1Interface
+}
+
+//nolint:ireturn // test helper
+func (c slowDeleteCoreV1) ServiceAccounts(namespace string) corev1client.ServiceAccountInterface {
+	return slowDeleteServiceAccounts{c.CoreV1Interface.ServiceAccounts(namespace)}
+}
+
+type slowDeleteServiceAccounts struct {
+	corev1client.ServiceAccountInterface
+}
+
+func (s slowDeleteServiceAccounts) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	err := s.ServiceAccountInterface.Delete(ctx, name, opts)
+	time.Sleep(time.Millisecond)
+	return err
+}
+
+// serviceAccountFromToken returns the service account name from the sub claim of a token.
+func serviceAccountFromToken(t *testing.T, tokenString string) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	require.NoError(t, err)
+
+	sub, err := claims.GetSubject()
+	require.NoError(t, err)
+	parts := strings.Split(sub, ":")
+	require.Len(t, parts, 4)
+
+	return parts[3]
+}
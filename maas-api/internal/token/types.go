@@ -1,16 +1,49 @@
 package token
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
 )
 
 // UserContext holds user information extracted from the token.
 type UserContext struct {
 	Username string   `json:"username"`
 	Groups   []string `json:"groups"`
+	// Tier is the tier resolved from the groups, set by Manager.UserTier so that it is resolved once per request.
+	Tier *tier.Tier `json:"-"`
+	// Source is the client the request was made from, e.g. ui, cli or ci, as reported by the source header.
+	// Empty when the header is missing or invalid.
+	Source string `json:"-"`
+
+	// tierMu guards Tier, the user may be shared by concurrent operations.
+	tierMu sync.Mutex
+}
+
+// InAnyGroup reports whether the user belongs to at least one of the given groups.
+func (u *UserContext) InAnyGroup(groups []string) bool {
+	return slices.ContainsFunc(u.Groups, func(group string) bool {
+		return slices.Contains(groups, group)
+	})
+}
+
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of ctx carrying the IP of the client the request originates from.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the client IP set with WithClientIP, or an empty string if none was set.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
 }
 
 type Token struct {
@@ -19,6 +52,30 @@ type Token struct {
 	ExpiresAt  int64    `json:"expiresAt"`
 	IssuedAt   int64    `json:"issuedAt,omitempty"` // JWT iat claim
 	JTI        string   `json:"jti,omitempty"`
+	// Namespace is the namespace of the Service Account the token was issued for.
+	Namespace string `json:"-"`
+}
+
+// ServiceAccountRef identifies a Service Account tokens are issued for.
+type ServiceAccountRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tier      string `json:"tier,omitempty"`
+}
+
+// ActiveServiceAccount is a Service Account active tokens of a user were issued for, see
+// Manager.PruneServiceAccounts. Namespace is empty when it is unknown, e.g. for tokens stored before it was recorded.
+type ActiveServiceAccount struct {
+	Username  string
+	Namespace string
+}
+
+// ServiceAccountPreview is the Service Account the tokens of a user are issued for, see Manager.PreviewServiceAccount.
+type ServiceAccountPreview struct {
+	Username string `json:"username"`
+	ServiceAccountRef
+	// Exists reports whether the Service Account was already created.
+	Exists bool `json:"exists"`
 }
 
 type Duration struct {
@@ -62,6 +119,12 @@ func (d *Duration) UnmarshalJSON(b []byte) error {
 	}
 }
 
+// ExpiresIn returns the number of whole seconds between now and the expiresAt unix timestamp.
+// It never returns a negative value, so an already expired token reports 0.
+func ExpiresIn(expiresAt int64, now time.Time) int64 {
+	return max(expiresAt-now.Unix(), 0)
+}
+
 // ValidateExpiration validates that a duration is positive and meets minimum requirements.
 // This provides consistent validation across handlers while keeping business rules
 // (like minimum duration) in the handlers that use them.
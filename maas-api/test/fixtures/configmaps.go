@@ -13,6 +13,7 @@ const TierConfigYAML = `
   displayName: Free Tier
   description: Free tier
   level: 1
+  maxExpiration: 2160h
   groups:
   - system:authenticated
   - free-users
@@ -3,4 +3,7 @@ package fixtures
 const (
 	TestNamespace = "test-namespace"
 	TestTenant    = "test-tenant"
+
+	// TestIntrospectionGroup is the admin group allowed to call the introspection and reconciliation endpoints of the test router.
+	TestIntrospectionGroup = "maas-introspectors"
 )
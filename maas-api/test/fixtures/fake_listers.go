@@ -50,3 +50,30 @@ func NewHTTPRouteLister(items ...runtime.Object) gatewaylisters.HTTPRouteLister
 	}
 	return gatewaylisters.NewHTTPRouteLister(indexer)
 }
+
+//nolint:ireturn // test helper
+func NewGatewayLister(items ...runtime.Object) gatewaylisters.GatewayLister {
+	indexer := newIndexer()
+	for _, item := range items {
+		_ = indexer.Add(item)
+	}
+	return gatewaylisters.NewGatewayLister(indexer)
+}
+
+//nolint:ireturn // test helper
+func NewNamespaceLister(items ...*corev1.Namespace) corelisters.NamespaceLister {
+	indexer := newIndexer()
+	for _, item := range items {
+		_ = indexer.Add(item)
+	}
+	return corelisters.NewNamespaceLister(indexer)
+}
+
+//nolint:ireturn // test helper
+func NewServiceAccountLister(items ...*corev1.ServiceAccount) corelisters.ServiceAccountLister {
+	indexer := newIndexer()
+	for _, item := range items {
+		_ = indexer.Add(item)
+	}
+	return corelisters.NewServiceAccountLister(indexer)
+}
@@ -77,6 +77,30 @@ func WithURL(url ModelURL) LLMInferenceServiceOption {
 	}
 }
 
+// NamedAddress is an entry of the status addresses of an LLMInferenceService.
+type NamedAddress struct {
+	Name string
+	URL  string
+}
+
+// WithAddresses sets the status addresses.
+func WithAddresses(addresses ...NamedAddress) LLMInferenceServiceOption {
+	return func(llm *kservev1alpha1.LLMInferenceService) {
+		llm.Status.Addresses = make([]duckv1.Addressable, 0, len(addresses))
+		for _, address := range addresses {
+			parsedURL, err := apis.ParseURL(address.URL)
+			if err != nil {
+				panic("invalid URL: " + err.Error())
+			}
+			addressable := duckv1.Addressable{URL: parsedURL}
+			if address.Name != "" {
+				addressable.Name = &address.Name
+			}
+			llm.Status.Addresses = append(llm.Status.Addresses, addressable)
+		}
+	}
+}
+
 // WithGatewaySpec sets the router gateway specification.
 func WithGatewaySpec(name, namespace string) LLMInferenceServiceOption {
 	return func(llm *kservev1alpha1.LLMInferenceService) {
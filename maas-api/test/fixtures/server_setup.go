@@ -23,6 +23,9 @@ import (
 	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tier"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
@@ -113,7 +116,14 @@ func SetupTestServer(_ *testing.T, config TestServerConfig) (*gin.Engine, *TestC
 }
 
 // StubTokenProviderAPIs creates common test components for token tests.
-func StubTokenProviderAPIs(_ *testing.T, withTierConfig bool) (*token.Manager, *k8sfake.Clientset, func()) {
+func StubTokenProviderAPIs(t *testing.T, withTierConfig bool) (*token.Manager, *k8sfake.Clientset, func()) {
+	t.Helper()
+	return StubTokenProviderAPIsWithOptions(t, withTierConfig, token.ManagerOptions{})
+}
+
+// StubTokenProviderAPIsWithOptions creates common test components for token tests,
+// with a token manager configured with the given options.
+func StubTokenProviderAPIsWithOptions(_ *testing.T, withTierConfig bool, options token.ManagerOptions) (*token.Manager, *k8sfake.Clientset, func()) {
 	testLogger := logger.Development()
 
 	var objects []runtime.Object
@@ -134,7 +144,7 @@ func StubTokenProviderAPIs(_ *testing.T, withTierConfig bool) (*token.Manager, *
 	namespaceLister := informerFactory.Core().V1().Namespaces().Lister()
 	serviceAccountLister := informerFactory.Core().V1().ServiceAccounts().Lister()
 
-	tierMapper := tier.NewMapper(testLogger, NewConfigMapLister(configMaps...), TestTenant, TestNamespace)
+	tierMapper := tier.NewMapper(testLogger, NewConfigMapLister(configMaps...), TestTenant, TestNamespace, tier.MapperOptions{})
 	manager := token.NewManager(
 		testLogger,
 		TestTenant,
@@ -142,6 +152,7 @@ func StubTokenProviderAPIs(_ *testing.T, withTierConfig bool) (*token.Manager, *
 		fakeClient,
 		namespaceLister,
 		serviceAccountLister,
+		options,
 	)
 
 	cleanup := func() {}
@@ -152,24 +163,43 @@ func StubTokenProviderAPIs(_ *testing.T, withTierConfig bool) (*token.Manager, *
 // SetupTestRouter creates a test router with token endpoints.
 // Returns the router and a cleanup function that must be called to close the store and remove the temp DB file.
 func SetupTestRouter(manager *token.Manager) (*gin.Engine, func() error) {
+	return SetupTestRouterWithOptions(manager, api_keys.ServiceOptions{})
+}
+
+// SetupTestRouterWithOptions creates a test router with token endpoints, backed by an API key service
+// configured with the given options.
+func SetupTestRouterWithOptions(manager *token.Manager, serviceOptions api_keys.ServiceOptions) (*gin.Engine, func() error) {
 	testLogger := logger.Development()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
-	store, err := api_keys.NewSQLiteStore(context.Background(), testLogger, ":memory:")
+	store, err := api_keys.NewSQLiteStore(context.Background(), testLogger, ":memory:", api_keys.StoreOptions{})
 	if err != nil {
 		panic(fmt.Sprintf("failed to create test store: %v", err))
 	}
 
-	tokenHandler := token.NewHandler(testLogger, "test", manager)
-	apiKeyService := api_keys.NewService(manager, store)
+	tokenHandler := token.NewHandler(testLogger, "test", manager, token.HandlerOptions{MaxGroups: constant.DefaultMaxGroups})
+	apiKeyService := api_keys.NewService(manager, store, serviceOptions)
 	apiKeyHandler := api_keys.NewHandler(testLogger, apiKeyService)
 
 	protected := router.Group("/v1")
-	protected.Use(tokenHandler.ExtractUserInfo())
+	protected.Use(handlers.LimitRequestBody(config.DefaultMaxRequestBodySize), tokenHandler.ExtractUserInfo())
 	protected.POST("/tokens", tokenHandler.IssueToken)
 	protected.DELETE("/tokens", apiKeyHandler.RevokeAllTokens)
+	protected.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+	protected.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+	protected.GET("/api-keys/:id", apiKeyHandler.GetAPIKey)
+	protected.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+	protected.PATCH("/api-keys/:id", apiKeyHandler.ExtendAPIKey)
+	protected.GET("/api-keys:method", apiKeyHandler.APIKeysMethod)
+	protected.POST("/introspect", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.Introspect)
+	protected.POST("/admin/reconcile-sa", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.ReconcileServiceAccounts)
+	protected.POST("/admin/revoke", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.RevokeIssuedBefore)
+	protected.GET("/admin/tokens", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.SearchTokens)
+	protected.POST("/admin/tokens/import", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.ImportTokens)
+	protected.GET("/admin/sa-name", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), tokenHandler.PreviewServiceAccount)
+	protected.GET("/admin/users/:username/identity", handlers.RequireAnyGroup([]string{TestIntrospectionGroup}), apiKeyHandler.GetUserIdentity)
 
 	cleanup := func() error {
 		return store.Close()
@@ -184,7 +214,12 @@ func SetupTierTestRouter(mapper *tier.Mapper) *gin.Engine {
 	router := gin.New()
 
 	handler := tier.NewHandler(mapper)
+	router.GET("/tiers", handler.ListTiers)
 	router.POST("/tiers/lookup", handler.TierLookup)
+	router.GET("/admin/tiers", handler.ListTierConfig)
+	router.POST("/admin/tiers", handler.CreateTier)
+	router.PUT("/admin/tiers/:name", handler.UpdateTier)
+	router.DELETE("/admin/tiers/:name", handler.DeleteTier)
 
 	return router
 }
@@ -199,7 +234,7 @@ func CreateTestMapper(withConfigMap bool) *tier.Mapper {
 		configMaps = append(configMaps, CreateTierConfigMap(TestNamespace))
 	}
 
-	return tier.NewMapper(testLogger, NewConfigMapLister(configMaps...), TestTenant, TestNamespace)
+	return tier.NewMapper(testLogger, NewConfigMapLister(configMaps...), TestTenant, TestNamespace, tier.MapperOptions{})
 }
 
 // StubServiceAccountTokenCreation sets up ServiceAccount token creation mocking for tests.
@@ -219,12 +254,19 @@ func StubServiceAccountTokenCreation(clientset kubernetes.Interface) {
 			return true, nil, fmt.Errorf("expected TokenRequest, got %T", createAction.GetObject())
 		}
 
+		// Honor the requested lifetime, like the API server does.
+		expiration := time.Hour
+		if tokenRequest.Spec.ExpirationSeconds != nil {
+			expiration = time.Duration(*tokenRequest.Spec.ExpirationSeconds) * time.Second
+		}
+		expiresAt := time.Now().Add(expiration)
+
 		// Generate valid JWT
 		claims := jwt.MapClaims{
 			"jti": fmt.Sprintf("mock-jti-%d", time.Now().UnixNano()),
 			"iat": time.Now().Unix(),
-			"exp": time.Now().Add(time.Hour).Unix(),
-			"sub": "system:serviceaccount:test-namespace:test-sa",
+			"exp": expiresAt.Unix(),
+			"sub": fmt.Sprintf("system:serviceaccount:%s:%s", action.GetNamespace(), serviceAccountName(createAction)),
 		}
 
 		signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
@@ -234,9 +276,17 @@ func StubServiceAccountTokenCreation(clientset kubernetes.Interface) {
 
 		tokenRequest.Status = authv1.TokenRequestStatus{
 			Token:               signedToken,
-			ExpirationTimestamp: metav1.NewTime(time.Now().Add(time.Hour)),
+			ExpirationTimestamp: metav1.NewTime(expiresAt),
 		}
 
 		return true, tokenRequest, nil
 	})
 }
+
+// serviceAccountName returns the name of the service account a token is requested for, falling back to a fixed name.
+func serviceAccountName(action k8stesting.CreateAction) string {
+	if impl, ok := action.(k8stesting.CreateActionImpl); ok && impl.Name != "" {
+		return impl.Name
+	}
+	return "test-sa"
+}
@@ -177,10 +177,10 @@ NO_NAME_RESPONSE=$(curl -sSk \
     "${API_BASE}/maas-api/v1/api-keys")
 
 no_name_status=$(echo "$NO_NAME_RESPONSE" | grep "HTTP_STATUS:" | cut -d':' -f2)
-if [ "$no_name_status" == "400" ]; then
+if [ "$no_name_status" == "422" ]; then
     echo -e "${GREEN}✓ Success (Correctly rejected)${NC}"
 else
-    echo -e "${RED}✗ Failed (Expected 400, got $no_name_status)${NC}"
+    echo -e "${RED}✗ Failed (Expected 422, got $no_name_status)${NC}"
 fi
 
 # Test 3.1: Create API Key